@@ -21,6 +21,7 @@ import (
 	prommetrics "github.com/kedacore/keda/pkg/metrics"
 	kedaprovider "github.com/kedacore/keda/pkg/provider"
 	"github.com/kedacore/keda/pkg/scaling"
+	"github.com/kedacore/keda/pkg/scaling/executor"
 	"github.com/kedacore/keda/version"
 )
 
@@ -66,7 +67,7 @@ func (a *Adapter) makeProviderOrDie() provider.MetricsProvider {
 		os.Exit(1)
 	}
 
-	handler := scaling.NewScaleHandler(kubeclient, nil, scheme)
+	handler := scaling.NewScaleHandler(kubeclient, nil, scheme, nil, executor.DefaultConfig())
 
 	namespace, err := getWatchNamespace()
 	if err != nil {
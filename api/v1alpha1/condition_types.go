@@ -14,6 +14,25 @@ const (
 	// ConditionActive specifies that the resource has finished.
 	// For resource which run to completion.
 	ConditionActive ConditionType = "Active"
+	// ConditionOverCapacity specifies that more Jobs are running than allowed by maxReplicaCount.
+	// For ScaledJobs only.
+	ConditionOverCapacity ConditionType = "OverCapacity"
+	// ConditionTemplateInvalid specifies that the API server rejected Jobs created from the template.
+	// For ScaledJobs only.
+	ConditionTemplateInvalid ConditionType = "TemplateInvalid"
+	// ConditionGlobalJobLimitReached specifies that no Jobs are created as the operator-level limit
+	// of the Jobs running for all ScaledJobs is reached.
+	// For ScaledJobs only.
+	ConditionGlobalJobLimitReached ConditionType = "GlobalJobLimitReached"
+	// ConditionPaused specifies that no Jobs are created as the scaling is paused by an annotation.
+	// For ScaledJobs only.
+	ConditionPaused ConditionType = "Paused"
+	// ConditionDegraded specifies that the last scaling of the Jobs failed.
+	// For ScaledJobs only.
+	ConditionDegraded ConditionType = "Degraded"
+	// ConditionNamespaceTerminating specifies that no Jobs are created as the namespace of the ScaledJob is terminating.
+	// For ScaledJobs only.
+	ConditionNamespaceTerminating ConditionType = "NamespaceTerminating"
 )
 
 // Condition to store the condition state
@@ -33,6 +52,10 @@ type Condition struct {
 	// A human readable message indicating details about the transition.
 	// +optional
 	Message string `json:"message,omitempty" description:"human-readable message indicating details about last transition"`
+
+	// The last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty" description:"last time the condition transitioned from one status to another"`
 }
 
 // Conditions an array representation to store multiple Conditions
@@ -107,6 +130,14 @@ func (c *Conditions) SetActiveCondition(status metav1.ConditionStatus, reason st
 	c.setCondition(ConditionActive, status, reason, message)
 }
 
+// GetReadyCondition returns Condition of type Ready
+func (c *Conditions) GetReadyCondition() Condition {
+	if *c == nil {
+		c = GetInitializedConditions()
+	}
+	return c.getCondition(ConditionReady)
+}
+
 // GetActiveCondition returns Condition of type Active
 func (c *Conditions) GetActiveCondition() Condition {
 	if *c == nil {
@@ -115,6 +146,83 @@ func (c *Conditions) GetActiveCondition() Condition {
 	return c.getCondition(ConditionActive)
 }
 
+// SetOverCapacityCondition modifies OverCapacity Condition according to input parameters,
+// the Condition is added if it is not present
+func (c *Conditions) SetOverCapacityCondition(status metav1.ConditionStatus, reason string, message string) {
+	c.setOrAddCondition(ConditionOverCapacity, status, reason, message)
+}
+
+// GetOverCapacityCondition returns Condition of type OverCapacity
+func (c *Conditions) GetOverCapacityCondition() Condition {
+	return c.getCondition(ConditionOverCapacity)
+}
+
+// SetTemplateInvalidCondition modifies TemplateInvalid Condition according to input parameters,
+// the Condition is added if it is not present
+func (c *Conditions) SetTemplateInvalidCondition(status metav1.ConditionStatus, reason string, message string) {
+	c.setOrAddCondition(ConditionTemplateInvalid, status, reason, message)
+}
+
+// GetTemplateInvalidCondition returns Condition of type TemplateInvalid
+func (c *Conditions) GetTemplateInvalidCondition() Condition {
+	return c.getCondition(ConditionTemplateInvalid)
+}
+
+// SetGlobalJobLimitReachedCondition modifies GlobalJobLimitReached Condition according to input parameters,
+// the Condition is added if it is not present
+func (c *Conditions) SetGlobalJobLimitReachedCondition(status metav1.ConditionStatus, reason string, message string) {
+	c.setOrAddCondition(ConditionGlobalJobLimitReached, status, reason, message)
+}
+
+// GetGlobalJobLimitReachedCondition returns Condition of type GlobalJobLimitReached
+func (c *Conditions) GetGlobalJobLimitReachedCondition() Condition {
+	return c.getCondition(ConditionGlobalJobLimitReached)
+}
+
+// SetPausedCondition modifies Paused Condition according to input parameters,
+// the Condition is added if it is not present
+func (c *Conditions) SetPausedCondition(status metav1.ConditionStatus, reason string, message string) {
+	c.setOrAddCondition(ConditionPaused, status, reason, message)
+}
+
+// GetPausedCondition returns Condition of type Paused
+func (c *Conditions) GetPausedCondition() Condition {
+	return c.getCondition(ConditionPaused)
+}
+
+// SetDegradedCondition modifies Degraded Condition according to input parameters,
+// the Condition is added if it is not present
+func (c *Conditions) SetDegradedCondition(status metav1.ConditionStatus, reason string, message string) {
+	c.setOrAddCondition(ConditionDegraded, status, reason, message)
+}
+
+// GetDegradedCondition returns Condition of type Degraded
+func (c *Conditions) GetDegradedCondition() Condition {
+	return c.getCondition(ConditionDegraded)
+}
+
+// SetNamespaceTerminatingCondition modifies NamespaceTerminating Condition according to input parameters,
+// the Condition is added if it is not present
+func (c *Conditions) SetNamespaceTerminatingCondition(status metav1.ConditionStatus, reason string, message string) {
+	c.setOrAddCondition(ConditionNamespaceTerminating, status, reason, message)
+}
+
+// GetNamespaceTerminatingCondition returns Condition of type NamespaceTerminating
+func (c *Conditions) GetNamespaceTerminatingCondition() Condition {
+	return c.getCondition(ConditionNamespaceTerminating)
+}
+
+func (c *Conditions) setOrAddCondition(conditionType ConditionType, status metav1.ConditionStatus, reason string, message string) {
+	for i := range *c {
+		if (*c)[i].Type == conditionType {
+			(*c)[i].update(status, reason, message)
+			return
+		}
+	}
+	now := metav1.Now()
+	*c = append(*c, Condition{Type: conditionType, Status: status, Reason: reason, Message: message, LastTransitionTime: &now})
+}
+
 func (c Conditions) getCondition(conditionType ConditionType) Condition {
 	for i := range c {
 		if c[i].Type == conditionType {
@@ -127,10 +235,19 @@ func (c Conditions) getCondition(conditionType ConditionType) Condition {
 func (c Conditions) setCondition(conditionType ConditionType, status metav1.ConditionStatus, reason string, message string) {
 	for i := range c {
 		if c[i].Type == conditionType {
-			c[i].Status = status
-			c[i].Reason = reason
-			c[i].Message = message
+			c[i].update(status, reason, message)
 			break
 		}
 	}
 }
+
+// update sets the Condition, the LastTransitionTime is set when the status changes
+func (c *Condition) update(status metav1.ConditionStatus, reason string, message string) {
+	if c.Status != status || c.LastTransitionTime == nil {
+		now := metav1.Now()
+		c.LastTransitionTime = &now
+	}
+	c.Status = status
+	c.Reason = reason
+	c.Message = message
+}
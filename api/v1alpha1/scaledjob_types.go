@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -9,6 +10,8 @@ import (
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=scaledjobs,scope=Namespaced,shortName=sj
+// +kubebuilder:printcolumn:name="MaxReplicas",type="integer",JSONPath=".spec.maxReplicaCount"
+// +kubebuilder:printcolumn:name="Running",type="integer",JSONPath=".status.runningJobCount"
 // +kubebuilder:printcolumn:name="Triggers",type="string",JSONPath=".spec.triggers[*].type"
 // +kubebuilder:printcolumn:name="Authentication",type="string",JSONPath=".spec.triggers[*].authenticationRef.name"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
@@ -30,14 +33,262 @@ type ScaledJobSpec struct {
 	// +optional
 	PollingInterval *int32 `json:"pollingInterval,omitempty"`
 	// +optional
+	RequeueInterval *int32 `json:"requeueInterval,omitempty"`
+	// +optional
+	AtCapacityRequeueInterval *int32 `json:"atCapacityRequeueInterval,omitempty"`
+	// +optional
 	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
 	// +optional
+	SuccessfulJobsCleanupOrder JobsCleanupOrder `json:"successfulJobsCleanupOrder,omitempty"`
+	// +optional
+	AlwaysRetainLastSuccess bool `json:"alwaysRetainLastSuccess,omitempty"`
+	// +optional
 	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
 	// +optional
+	FailedJobPodsHistoryLimit *int32 `json:"failedJobPodsHistoryLimit,omitempty"`
+	// +optional
+	FailedJobsCleanupOrder JobsCleanupOrder `json:"failedJobsCleanupOrder,omitempty"`
+	// +optional
+	TotalJobsHistoryLimit *int32 `json:"totalJobsHistoryLimit,omitempty"`
+	// +optional
+	TriggerHistoryLimits []TriggerHistoryLimit `json:"triggerHistoryLimits,omitempty"`
+	// +optional
+	SoftDeleteAnnotation bool `json:"softDeleteAnnotation,omitempty"`
+	// +optional
+	IncidentRetentionAnnotation string `json:"incidentRetentionAnnotation,omitempty"`
+	// +optional
 	EnvSourceContainerName string `json:"envSourceContainerName,omitempty"`
 	// +optional
-	MaxReplicaCount *int32          `json:"maxReplicaCount,omitempty"`
-	Triggers        []ScaleTriggers `json:"triggers"`
+	JobNamePrefix string `json:"jobNamePrefix,omitempty"`
+	// +optional
+	JobTemplatePatch string `json:"jobTemplatePatch,omitempty"`
+	// +optional
+	MaxReplicaCount *int32 `json:"maxReplicaCount,omitempty"`
+	// +optional
+	EnforceMaxScale bool `json:"enforceMaxScale,omitempty"`
+	// +optional
+	CountRunningPods bool `json:"countRunningPods,omitempty"`
+	// +optional
+	RespectPendingPressure bool `json:"respectPendingPressure,omitempty"`
+	// +optional
+	BacklogCriticalThreshold *int32 `json:"backlogCriticalThreshold,omitempty"`
+	// +optional
+	ReplaceFailedJobs bool `json:"replaceFailedJobs,omitempty"`
+	// +optional
+	MaxJobAgeSeconds *int64 `json:"maxJobAgeSeconds,omitempty"`
+	// +optional
+	MaxJobAgeDurationMultiplier *int32 `json:"maxJobAgeDurationMultiplier,omitempty"`
+	// +optional
+	DefaultPodSecurityContext *corev1.PodSecurityContext `json:"defaultPodSecurityContext,omitempty"`
+	// +optional
+	DefaultTolerations []corev1.Toleration `json:"defaultTolerations,omitempty"`
+	// +optional
+	DeleteOrphanedPods bool `json:"deleteOrphanedPods,omitempty"`
+	// +optional
+	AdoptOwnerlessJobs bool `json:"adoptOwnerlessJobs,omitempty"`
+	// +optional
+	FailImagePullBackOffJobs bool `json:"failImagePullBackOffJobs,omitempty"`
+	// +optional
+	ConcurrentCleanup bool `json:"concurrentCleanup,omitempty"`
+	// +optional
+	InjectScaleEnv bool `json:"injectScaleEnv,omitempty"`
+	// +optional
+	InjectScaleMetadataVolume bool `json:"injectScaleMetadataVolume,omitempty"`
+	// +optional
+	ShardedCreation bool `json:"shardedCreation,omitempty"`
+	// +optional
+	BatchAffinityTopologyKey string `json:"batchAffinityTopologyKey,omitempty"`
+	// +optional
+	EmitScaleConfigMap bool `json:"emitScaleConfigMap,omitempty"`
+	// +optional
+	ScalingStrategy *ScalingStrategy `json:"scalingStrategy,omitempty"`
+	// +optional
+	ScalingModifiers *ScalingModifiers `json:"scalingModifiers,omitempty"`
+	// +optional
+	ExternalQuotaRef *ExternalQuotaRef `json:"externalQuotaRef,omitempty"`
+	// +optional
+	SemaphoreRef *SemaphoreRef `json:"semaphoreRef,omitempty"`
+	// +optional
+	CreationRateLimit *CreationRateLimit `json:"creationRateLimit,omitempty"`
+	// +optional
+	MaxDeletesPerReconcile *int32 `json:"maxDeletesPerReconcile,omitempty"`
+	// +optional
+	StuckJobRecreation *StuckJobRecreation `json:"stuckJobRecreation,omitempty"`
+	// +optional
+	CompletionProbe *CompletionProbe `json:"completionProbe,omitempty"`
+	// +optional
+	MainContainerName string `json:"mainContainerName,omitempty"`
+	// +optional
+	JobNamespaces []string        `json:"jobNamespaces,omitempty"`
+	Triggers      []ScaleTriggers `json:"triggers"`
+}
+
+// JobsCleanupOrder defines which Jobs are deleted first when the history limit is exceeded
+type JobsCleanupOrder string
+
+const (
+	// OldestJobsCleanupOrder deletes the Jobs that finished first, the most recent Jobs are kept
+	OldestJobsCleanupOrder JobsCleanupOrder = "Oldest"
+	// NewestJobsCleanupOrder deletes the Jobs that finished last, the earliest Jobs are kept
+	NewestJobsCleanupOrder JobsCleanupOrder = "Newest"
+)
+
+// ScalingStrategyType is the name of a ScalingStrategy
+type ScalingStrategyType string
+
+const (
+	// DefaultScalingStrategy creates a Job for every item in the queue
+	DefaultScalingStrategy ScalingStrategyType = "default"
+	// PercentageScalingStrategy creates Jobs for a percentage of the items in the queue
+	PercentageScalingStrategy ScalingStrategyType = "percentage"
+	// ThroughputScalingStrategy creates fewer Jobs when the Jobs complete faster than expected
+	ThroughputScalingStrategy ScalingStrategyType = "throughput"
+)
+
+// ScalingStrategy defines the strategy used to compute the number of Jobs to create
+type ScalingStrategy struct {
+	// Strategy is the name of the strategy, one of "default", "percentage" or "throughput"
+	// +optional
+	Strategy ScalingStrategyType `json:"strategy,omitempty"`
+	// Percentage of the queue length covered by Jobs in every polling interval,
+	// used by the "percentage" strategy
+	// +optional
+	Percentage *int32 `json:"percentage,omitempty"`
+	// BacklogAgeThresholdSeconds is the age of the oldest pending item, in seconds,
+	// above which the number of created Jobs is boosted
+	// +optional
+	BacklogAgeThresholdSeconds *int32 `json:"backlogAgeThresholdSeconds,omitempty"`
+	// BacklogAgeBoostPercentage is the percentage added to the number of Jobs to create
+	// once the BacklogAgeThresholdSeconds is exceeded, defaults to 100
+	// +optional
+	BacklogAgeBoostPercentage *int32 `json:"backlogAgeBoostPercentage,omitempty"`
+	// CompletionsDivisor splits the number of Jobs to create into shares, every created Job
+	// gets its share as completions and parallelism, it must be greater than 0
+	// +optional
+	CompletionsDivisor *int32 `json:"completionsDivisor,omitempty"`
+	// ExpectedJobDurationSeconds is the expected duration of a Job used by the "throughput" strategy,
+	// when the average duration of the completed Jobs is shorter, the number of Jobs to create is reduced
+	// by the ratio of the average to the expected duration
+	// +optional
+	ExpectedJobDurationSeconds *int32 `json:"expectedJobDurationSeconds,omitempty"`
+	// TriggerWeights weight the queue lengths of the triggers, the number of Jobs to create is computed
+	// from the weighted sum of the queue lengths. The triggers without a weight are weighted 100
+	// +optional
+	TriggerWeights []TriggerWeight `json:"triggerWeights,omitempty"`
+}
+
+// TriggerWeight is the weight of the queue length of a trigger
+type TriggerWeight struct {
+	// Trigger is the name, or the type of an unnamed trigger
+	Trigger string `json:"trigger"`
+	// Weight is the percentage of the queue length of the trigger counted towards the number of Jobs to create,
+	// 0 ignores the queue length of the trigger
+	Weight int32 `json:"weight"`
+}
+
+// ScalingModifiers adjust the limits of the scaling depending on external conditions
+type ScalingModifiers struct {
+	// TimeWindows cap the number of running Jobs while they are in effect, the lowest cap
+	// of the windows in effect is used
+	// +optional
+	TimeWindows []TimeWindow `json:"timeWindows,omitempty"`
+	// MaxFormula computes the maximum number of running Jobs instead of the static maxReplicaCount,
+	// eg. "min(maxReplicaCount, ceil(queueLength / 20))", the variables queueLength, maxReplicaCount
+	// and runningJobCount and the functions min, max, ceil and floor are available. The result is capped
+	// to maxReplicaCount
+	// +optional
+	MaxFormula string `json:"maxFormula,omitempty"`
+	// ScaleUpStabilizationWindow is the number of the most recent polls whose highest scaleTo is used,
+	// so a transient dip of the metric doesn't scale down prematurely
+	// +optional
+	ScaleUpStabilizationWindow *int32 `json:"scaleUpStabilizationWindow,omitempty"`
+	// ScaleDownStabilizationWindow is the number of seconds scaleTo has to stay below its last peak
+	// before the lower scaleTo is used, so the creation of Jobs doesn't thrash after scaling up
+	// +optional
+	ScaleDownStabilizationWindow *int32 `json:"scaleDownStabilizationWindow,omitempty"`
+}
+
+// TimeWindow caps the number of running Jobs between its start and end
+type TimeWindow struct {
+	// Start is the cron expression at which the window starts, eg. "0 18 * * 1-5"
+	Start string `json:"start"`
+	// End is the cron expression at which the window ends, eg. "0 8 * * 1-5"
+	End string `json:"end"`
+	// Timezone of the cron expressions, eg. "Europe/Prague", defaults to UTC
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+	// MaxReplicaCount is the maximum number of running Jobs while the window is in effect
+	MaxReplicaCount int32 `json:"maxReplicaCount"`
+}
+
+// ExternalQuotaRef points to an external quota service asked for permission before Jobs are created
+type ExternalQuotaRef struct {
+	// URL of the quota service, the number of Jobs to create is POSTed as JSON and the service
+	// responds with the allowed number of Jobs. The host must be allowed by the operator
+	URL string `json:"url"`
+	// TimeoutSeconds of the request to the quota service, defaults to 3
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// FailOpen creates the Jobs without a quota when the service can't be reached or fails,
+	// by default no Jobs are created then
+	// +optional
+	FailOpen bool `json:"failOpen,omitempty"`
+}
+
+// SemaphoreRef points to a Lease used as a distributed semaphore by the ScaledJobs sharing a globally limited resource,
+// eg. a licensed connector, every running Job holds a permit
+type SemaphoreRef struct {
+	// Name of the Lease holding the permits
+	Name string `json:"name"`
+	// Namespace of the Lease, defaults to the namespace of the ScaledJob, other namespaces must be allowed by the operator
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Permits is the number of the Jobs of all the ScaledJobs sharing the semaphore that can run at once
+	Permits int32 `json:"permits"`
+}
+
+// CreationRateLimit limits the rate at which the Jobs of a ScaledJob are created with a token bucket,
+// the Jobs exceeding the limit are created by the next scaling requests
+type CreationRateLimit struct {
+	// JobsPerMinute is the rate at which the bucket is refilled
+	JobsPerMinute int32 `json:"jobsPerMinute"`
+	// Burst is the size of the bucket, the number of Jobs that can be created at once, defaults to JobsPerMinute
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+// StuckJobRecreation deletes and recreates the running Jobs making no progress, e.g. whose pods wedge
+// without failing, instead of waiting for the backoff of the Job controller
+type StuckJobRecreation struct {
+	// StuckSeconds is the time since the start of a Job without active pods and completions
+	// after which the Job is considered stuck
+	StuckSeconds int32 `json:"stuckSeconds"`
+}
+
+// CompletionProbe detects the Jobs whose work is done while their pods keep running without completing the Job,
+// the endpoint of the running pods is probed and the Job is considered complete once a pod responds with a 2xx status
+type CompletionProbe struct {
+	// Port of the pods serving the endpoint
+	Port int32 `json:"port"`
+	// Path of the endpoint, defaults to "/"
+	// +optional
+	Path string `json:"path,omitempty"`
+	// TimeoutSeconds of the request to a pod, defaults to 1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// TriggerHistoryLimit sets the history limits of the finished Jobs labeled with a trigger by the keda.sh/trigger label,
+// they are cleaned up separately from the other Jobs of the ScaledJob, the other history settings apply to the other Jobs only
+type TriggerHistoryLimit struct {
+	// Trigger is the name, or the type of an unnamed trigger, the Jobs are labeled with
+	Trigger string `json:"trigger"`
+	// SuccessfulJobsHistoryLimit of the Jobs of the trigger, defaults to the successfulJobsHistoryLimit of the ScaledJob
+	// +optional
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+	// FailedJobsHistoryLimit of the Jobs of the trigger, defaults to the failedJobsHistoryLimit of the ScaledJob
+	// +optional
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
 }
 
 // ScaledJobStatus defines the observed state of ScaledJob
@@ -47,6 +298,47 @@ type ScaledJobStatus struct {
 	LastActiveTime *metav1.Time `json:"lastActiveTime,omitempty"`
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
+	// +optional
+	AverageJobDurationSeconds *int64 `json:"averageJobDurationSeconds,omitempty"`
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// +optional
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+	// +optional
+	RunningJobCount *int64 `json:"runningJobCount,omitempty"`
+	// +optional
+	LastCleanupDeletedCount *CleanupDeletedCount `json:"lastCleanupDeletedCount,omitempty"`
+	// +optional
+	RecentScaleTo []int64 `json:"recentScaleTo,omitempty"`
+	// +optional
+	JobLabelValue string `json:"jobLabelValue,omitempty"`
+	// +optional
+	LastFormulaEval *FormulaEvaluation `json:"lastFormulaEval,omitempty"`
+}
+
+// CleanupDeletedCount is the number of finished Jobs deleted by a cleanup as they exceeded the history limits
+type CleanupDeletedCount struct {
+	// Completed is the number of deleted completed Jobs
+	Completed int64 `json:"completed"`
+	// Failed is the number of deleted failed Jobs
+	Failed int64 `json:"failed"`
+}
+
+// FormulaEvaluation is the last evaluation of the maxFormula of the ScaledJob, to debug the number of created Jobs
+type FormulaEvaluation struct {
+	// Formula is the evaluated maxFormula
+	Formula string `json:"formula"`
+	// QueueLength is the queueLength variable of the evaluation
+	QueueLength int64 `json:"queueLength"`
+	// MaxReplicaCount is the maxReplicaCount variable of the evaluation
+	MaxReplicaCount int64 `json:"maxReplicaCount"`
+	// RunningJobCount is the runningJobCount variable of the evaluation
+	RunningJobCount int64 `json:"runningJobCount"`
+	// Result is the maxScale computed by the formula, the maxScale of the triggers if the evaluation failed
+	Result int64 `json:"result"`
+	// Error of the evaluation
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // ScaledJobList contains a list of ScaledJob
@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+type printerColumns struct {
+	Spec struct {
+		AdditionalPrinterColumns []struct {
+			JSONPath string `json:"JSONPath"`
+			Name     string `json:"name"`
+		} `json:"additionalPrinterColumns"`
+	} `json:"spec"`
+}
+
+func TestScaledJobPrinterColumns(t *testing.T) {
+	data, err := ioutil.ReadFile("../../config/crd/bases/keda.sh_scaledjobs.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crd := printerColumns{}
+	if err := yaml.Unmarshal(data, &crd); err != nil {
+		t.Fatal(err)
+	}
+
+	maxReplicaCount := int32(10)
+	runningJobCount := int64(3)
+	scaledJob := &ScaledJob{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+		Spec: ScaledJobSpec{
+			MaxReplicaCount: &maxReplicaCount,
+			Triggers: []ScaleTriggers{{
+				Type:              "rabbitmq",
+				AuthenticationRef: &ScaledObjectAuthRef{Name: "rabbitmq-auth"},
+			}},
+		},
+		Status: ScaledJobStatus{
+			RunningJobCount: &runningJobCount,
+			Conditions: Conditions{
+				{Type: ConditionReady, Status: metav1.ConditionTrue},
+				{Type: ConditionActive, Status: metav1.ConditionFalse},
+			},
+		},
+	}
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(scaledJob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"MaxReplicas":    "10",
+		"Running":        "3",
+		"Triggers":       "rabbitmq",
+		"Authentication": "rabbitmq-auth",
+		"Ready":          "True",
+		"Active":         "False",
+		"Age":            scaledJob.CreationTimestamp.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if len(crd.Spec.AdditionalPrinterColumns) != len(expected) {
+		t.Errorf("Expected %d printer columns, got %d", len(expected), len(crd.Spec.AdditionalPrinterColumns))
+	}
+	for _, column := range crd.Spec.AdditionalPrinterColumns {
+		path := jsonpath.New(column.Name)
+		if err := path.Parse(fmt.Sprintf("{%s}", column.JSONPath)); err != nil {
+			t.Errorf("Column %s has an invalid JSONPath %s: %s", column.Name, column.JSONPath, err)
+			continue
+		}
+		out := &bytes.Buffer{}
+		if err := path.Execute(out, object); err != nil {
+			t.Errorf("Column %s doesn't map to a field of the ScaledJob: %s", column.Name, err)
+			continue
+		}
+		if out.String() != expected[column.Name] {
+			t.Errorf("Column %s: expected %q, got %q", column.Name, expected[column.Name], out.String())
+		}
+	}
+}
@@ -23,6 +23,7 @@ package v1alpha1
 import (
 	"k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -91,9 +92,48 @@ func (in *AuthSecretTargetRef) DeepCopy() *AuthSecretTargetRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupDeletedCount) DeepCopyInto(out *CleanupDeletedCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupDeletedCount.
+func (in *CleanupDeletedCount) DeepCopy() *CleanupDeletedCount {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupDeletedCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompletionProbe) DeepCopyInto(out *CompletionProbe) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompletionProbe.
+func (in *CompletionProbe) DeepCopy() *CompletionProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(CompletionProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
@@ -111,7 +151,9 @@ func (in Conditions) DeepCopyInto(out *Conditions) {
 	{
 		in := &in
 		*out = make(Conditions, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -125,6 +167,26 @@ func (in Conditions) DeepCopy() Conditions {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CreationRateLimit) DeepCopyInto(out *CreationRateLimit) {
+	*out = *in
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreationRateLimit.
+func (in *CreationRateLimit) DeepCopy() *CreationRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(CreationRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Credential) DeepCopyInto(out *Credential) {
 	*out = *in
@@ -140,6 +202,41 @@ func (in *Credential) DeepCopy() *Credential {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalQuotaRef) DeepCopyInto(out *ExternalQuotaRef) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalQuotaRef.
+func (in *ExternalQuotaRef) DeepCopy() *ExternalQuotaRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalQuotaRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FormulaEvaluation) DeepCopyInto(out *FormulaEvaluation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FormulaEvaluation.
+func (in *FormulaEvaluation) DeepCopy() *FormulaEvaluation {
+	if in == nil {
+		return nil
+	}
+	out := new(FormulaEvaluation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupVersionKindResource) DeepCopyInto(out *GroupVersionKindResource) {
 	*out = *in
@@ -321,6 +418,16 @@ func (in *ScaledJobSpec) DeepCopyInto(out *ScaledJobSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.RequeueInterval != nil {
+		in, out := &in.RequeueInterval, &out.RequeueInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AtCapacityRequeueInterval != nil {
+		in, out := &in.AtCapacityRequeueInterval, &out.AtCapacityRequeueInterval
+		*out = new(int32)
+		**out = **in
+	}
 	if in.SuccessfulJobsHistoryLimit != nil {
 		in, out := &in.SuccessfulJobsHistoryLimit, &out.SuccessfulJobsHistoryLimit
 		*out = new(int32)
@@ -331,11 +438,100 @@ func (in *ScaledJobSpec) DeepCopyInto(out *ScaledJobSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailedJobPodsHistoryLimit != nil {
+		in, out := &in.FailedJobPodsHistoryLimit, &out.FailedJobPodsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TotalJobsHistoryLimit != nil {
+		in, out := &in.TotalJobsHistoryLimit, &out.TotalJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TriggerHistoryLimits != nil {
+		in, out := &in.TriggerHistoryLimits, &out.TriggerHistoryLimits
+		*out = make([]TriggerHistoryLimit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.MaxReplicaCount != nil {
 		in, out := &in.MaxReplicaCount, &out.MaxReplicaCount
 		*out = new(int32)
 		**out = **in
 	}
+	if in.BacklogCriticalThreshold != nil {
+		in, out := &in.BacklogCriticalThreshold, &out.BacklogCriticalThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxJobAgeSeconds != nil {
+		in, out := &in.MaxJobAgeSeconds, &out.MaxJobAgeSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxJobAgeDurationMultiplier != nil {
+		in, out := &in.MaxJobAgeDurationMultiplier, &out.MaxJobAgeDurationMultiplier
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DefaultPodSecurityContext != nil {
+		in, out := &in.DefaultPodSecurityContext, &out.DefaultPodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultTolerations != nil {
+		in, out := &in.DefaultTolerations, &out.DefaultTolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScalingStrategy != nil {
+		in, out := &in.ScalingStrategy, &out.ScalingStrategy
+		*out = new(ScalingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScalingModifiers != nil {
+		in, out := &in.ScalingModifiers, &out.ScalingModifiers
+		*out = new(ScalingModifiers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalQuotaRef != nil {
+		in, out := &in.ExternalQuotaRef, &out.ExternalQuotaRef
+		*out = new(ExternalQuotaRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SemaphoreRef != nil {
+		in, out := &in.SemaphoreRef, &out.SemaphoreRef
+		*out = new(SemaphoreRef)
+		**out = **in
+	}
+	if in.CreationRateLimit != nil {
+		in, out := &in.CreationRateLimit, &out.CreationRateLimit
+		*out = new(CreationRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxDeletesPerReconcile != nil {
+		in, out := &in.MaxDeletesPerReconcile, &out.MaxDeletesPerReconcile
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StuckJobRecreation != nil {
+		in, out := &in.StuckJobRecreation, &out.StuckJobRecreation
+		*out = new(StuckJobRecreation)
+		**out = **in
+	}
+	if in.CompletionProbe != nil {
+		in, out := &in.CompletionProbe, &out.CompletionProbe
+		*out = new(CompletionProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JobNamespaces != nil {
+		in, out := &in.JobNamespaces, &out.JobNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Triggers != nil {
 		in, out := &in.Triggers, &out.Triggers
 		*out = make([]ScaleTriggers, len(*in))
@@ -365,8 +561,39 @@ func (in *ScaledJobStatus) DeepCopyInto(out *ScaledJobStatus) {
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AverageJobDurationSeconds != nil {
+		in, out := &in.AverageJobDurationSeconds, &out.AverageJobDurationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RunningJobCount != nil {
+		in, out := &in.RunningJobCount, &out.RunningJobCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastCleanupDeletedCount != nil {
+		in, out := &in.LastCleanupDeletedCount, &out.LastCleanupDeletedCount
+		*out = new(CleanupDeletedCount)
+		**out = **in
+	}
+	if in.RecentScaleTo != nil {
+		in, out := &in.RecentScaleTo, &out.RecentScaleTo
+		*out = make([]int64, len(*in))
 		copy(*out, *in)
 	}
+	if in.LastFormulaEval != nil {
+		in, out := &in.LastFormulaEval, &out.LastFormulaEval
+		*out = new(FormulaEvaluation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaledJobStatus.
@@ -530,7 +757,9 @@ func (in *ScaledObjectStatus) DeepCopyInto(out *ScaledObjectStatus) {
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -544,6 +773,126 @@ func (in *ScaledObjectStatus) DeepCopy() *ScaledObjectStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingModifiers) DeepCopyInto(out *ScalingModifiers) {
+	*out = *in
+	if in.TimeWindows != nil {
+		in, out := &in.TimeWindows, &out.TimeWindows
+		*out = make([]TimeWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScaleUpStabilizationWindow != nil {
+		in, out := &in.ScaleUpStabilizationWindow, &out.ScaleUpStabilizationWindow
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleDownStabilizationWindow != nil {
+		in, out := &in.ScaleDownStabilizationWindow, &out.ScaleDownStabilizationWindow
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingModifiers.
+func (in *ScalingModifiers) DeepCopy() *ScalingModifiers {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingModifiers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingStrategy) DeepCopyInto(out *ScalingStrategy) {
+	*out = *in
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BacklogAgeThresholdSeconds != nil {
+		in, out := &in.BacklogAgeThresholdSeconds, &out.BacklogAgeThresholdSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BacklogAgeBoostPercentage != nil {
+		in, out := &in.BacklogAgeBoostPercentage, &out.BacklogAgeBoostPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CompletionsDivisor != nil {
+		in, out := &in.CompletionsDivisor, &out.CompletionsDivisor
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExpectedJobDurationSeconds != nil {
+		in, out := &in.ExpectedJobDurationSeconds, &out.ExpectedJobDurationSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TriggerWeights != nil {
+		in, out := &in.TriggerWeights, &out.TriggerWeights
+		*out = make([]TriggerWeight, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingStrategy.
+func (in *ScalingStrategy) DeepCopy() *ScalingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SemaphoreRef) DeepCopyInto(out *SemaphoreRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SemaphoreRef.
+func (in *SemaphoreRef) DeepCopy() *SemaphoreRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SemaphoreRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StuckJobRecreation) DeepCopyInto(out *StuckJobRecreation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StuckJobRecreation.
+func (in *StuckJobRecreation) DeepCopy() *StuckJobRecreation {
+	if in == nil {
+		return nil
+	}
+	out := new(StuckJobRecreation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeWindow) DeepCopyInto(out *TimeWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeWindow.
+func (in *TimeWindow) DeepCopy() *TimeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TriggerAuthentication) DeepCopyInto(out *TriggerAuthentication) {
 	*out = *in
@@ -629,6 +978,46 @@ func (in *TriggerAuthenticationSpec) DeepCopy() *TriggerAuthenticationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerHistoryLimit) DeepCopyInto(out *TriggerHistoryLimit) {
+	*out = *in
+	if in.SuccessfulJobsHistoryLimit != nil {
+		in, out := &in.SuccessfulJobsHistoryLimit, &out.SuccessfulJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedJobsHistoryLimit != nil {
+		in, out := &in.FailedJobsHistoryLimit, &out.FailedJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerHistoryLimit.
+func (in *TriggerHistoryLimit) DeepCopy() *TriggerHistoryLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerHistoryLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerWeight) DeepCopyInto(out *TriggerWeight) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerWeight.
+func (in *TriggerWeight) DeepCopy() *TriggerWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultSecret) DeepCopyInto(out *VaultSecret) {
 	*out = *in
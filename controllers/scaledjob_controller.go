@@ -6,42 +6,148 @@ import (
 
 	"github.com/go-logr/logr"
 	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	kedacontrollerutil "github.com/kedacore/keda/controllers/util"
 	"github.com/kedacore/keda/pkg/scaling"
+	"github.com/kedacore/keda/pkg/scaling/executor"
 )
 
 // +kubebuilder:rbac:groups=keda.sh,resources=scaledjobs;scaledjobs/status,verbs="*"
 // +kubebuilder:rbac:groups=keda.sh,resources=triggerauthentications;triggerauthentications/status,verbs="*"
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs="*"
+// +kubebuilder:rbac:groups="",resources=pods,verbs=delete
+// +kubebuilder:rbac:groups="",resources=namespaces;nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update
 
 // ScaledJobReconciler reconciles a ScaledJob object
 type ScaledJobReconciler struct {
 	client.Client
-	Log          logr.Logger
-	Scheme       *runtime.Scheme
-	scaleHandler scaling.ScaleHandler
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// JobScaleConfig is the operator-level configuration the Jobs are scaled and the ScaledJobs are validated with
+	JobScaleConfig executor.Config
+	scaleHandler   scaling.ScaleHandler
+	scaleExecutor  executor.JobScaleExecutor
 }
 
 // SetupWithManager initializes the ScaledJobReconciler instance and starts a new controller managed by the passed Manager instance.
 func (r *ScaledJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
-	r.scaleHandler = scaling.NewScaleHandler(mgr.GetClient(), nil, mgr.GetScheme())
+	// The executors list Jobs through the manager's client, which reads from the shared informer cache.
+	// The Job informer is registered upfront, so the cache is synced with the existing Jobs on start
+	// and no reconcile lists them from the API server
+	if _, err := mgr.GetCache().GetInformer(&batchv1.Job{}); err != nil {
+		return err
+	}
+	recorder := mgr.GetEventRecorderFor("keda-operator")
+	r.scaleHandler = scaling.NewScaleHandler(mgr.GetClient(), nil, mgr.GetScheme(), recorder, r.JobScaleConfig)
+	r.scaleExecutor = executor.NewScaleExecutor(mgr.GetClient(), nil, mgr.GetScheme(), recorder, r.JobScaleConfig)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		// Ignore updates to ScaledJob Status (in this case metadata.Generation does not change)
 		// so reconcile loop is not started on Status updates
-		For(&kedav1alpha1.ScaledJob{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The updates requesting a cleanup by the annotation are passed too
+		For(&kedav1alpha1.ScaledJob{}, builder.WithPredicates(scaledJobPredicate)).
+		// The Jobs missing the label selecting them are labeled again by the reconcile
+		Owns(&batchv1.Job{}, builder.WithPredicates(ownedJobPredicate)).
+		// Check the scalers of the ScaledJob once its Jobs finish, so new Jobs are created without waiting for the pollingInterval,
+		// the running ScaleLoop is triggered, a reconcile would restart it
+		Watches(&source.Kind{Type: &batchv1.Job{}}, &handler.Funcs{UpdateFunc: r.triggerScaleLoopOnJobFinished}).
 		Complete(r)
 }
 
+// scaledJobPredicate passes the updates of ScaledJobs changing their spec or requesting a cleanup of their Jobs
+var scaledJobPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if (predicate.GenerationChangedPredicate{}).Update(e) {
+			return true
+		}
+		return e.MetaNew != nil && isCleanupNowRequested(e.MetaNew.GetAnnotations())
+	},
+}
+
+// isCleanupNowRequested returns true if the annotations request a cleanup of the Jobs of the ScaledJob
+func isCleanupNowRequested(annotations map[string]string) bool {
+	return annotations[executor.CleanupNowAnnotation] == "true"
+}
+
+// jobFinishedPredicate passes the updates of Jobs that just finished
+var jobFinishedPredicate = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool {
+		return false
+	},
+	DeleteFunc: func(event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(event.GenericEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldJob, ok := e.ObjectOld.(*batchv1.Job)
+		if !ok {
+			return false
+		}
+		newJob, ok := e.ObjectNew.(*batchv1.Job)
+		if !ok {
+			return false
+		}
+		return !executor.IsJobFinished(oldJob) && executor.IsJobFinished(newJob)
+	},
+}
+
+// ownedJobPredicate passes the events of Jobs that are missing the label selecting them
+var ownedJobPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return e.Meta != nil && executor.IsJobLabelMissing(e.Meta)
+	},
+	DeleteFunc: func(event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(event.GenericEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.MetaNew != nil && executor.IsJobLabelMissing(e.MetaNew)
+	},
+}
+
+// triggerScaleLoopOnJobFinished triggers the ScaleLoop of the ScaledJob controlling the Job that just finished,
+// the ScaledJob isn't reconciled, so the ScaleLoop keeps running and no second one checks the scalers concurrently
+func (r *ScaledJobReconciler) triggerScaleLoopOnJobFinished(e event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+	if !jobFinishedPredicate.Update(e) || e.MetaNew == nil {
+		return
+	}
+	owner := metav1.GetControllerOf(e.MetaNew)
+	if owner == nil || owner.Kind != "ScaledJob" || owner.APIVersion != kedav1alpha1.GroupVersion.String() {
+		return
+	}
+
+	// the ScaleLoop is keyed by the ScaledJob as read by the reconcile
+	scaledJob := &kedav1alpha1.ScaledJob{}
+	key := types.NamespacedName{Namespace: e.MetaNew.GetNamespace(), Name: owner.Name}
+	if err := r.Client.Get(context.TODO(), key, scaledJob); err != nil {
+		if !errors.IsNotFound(err) {
+			r.Log.Error(err, "Failed to get the ScaledJob of the finished Job", "ScaledJob.Namespace", key.Namespace, "ScaledJob.Name", key.Name)
+		}
+		return
+	}
+	r.scaleHandler.TriggerScaleLoop(scaledJob)
+}
+
 // Reconcile performs reconciliation on the identified ScaledJob resource based on the request information passed, returns the result and an error (if any).
 func (r *ScaledJobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	reqLogger := r.Log.WithValues("ScaledJob.Namespace", req.Namespace, "ScaledJob.Name", req.Name)
@@ -63,6 +169,37 @@ func (r *ScaledJobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	reqLogger.Info("Reconciling ScaledJob")
 
+	// Check if the ScaledJob instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	if scaledJob.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, r.finalizeScaledJob(reqLogger, scaledJob)
+	}
+
+	// ensure finalizer is set on this CR
+	if err := r.ensureFinalizer(reqLogger, scaledJob); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the cleanup requested by the annotation runs right away, not with the next scaling
+	if isCleanupNowRequested(scaledJob.GetAnnotations()) {
+		if err := r.scaleExecutor.CleanUpNow(context.TODO(), scaledJob); err != nil {
+			reqLogger.Error(err, "Failed to clean up the Jobs requested by the annotation")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// the Jobs whose label was stripped, eg. by an admission controller, wouldn't be counted nor cleaned up
+	if err := r.scaleExecutor.RestoreJobLabels(context.TODO(), scaledJob); err != nil {
+		reqLogger.Error(err, "Failed to restore the labels of the Jobs")
+		return ctrl.Result{}, err
+	}
+
+	// ensure Status Conditions are initialized
+	if !scaledJob.Status.Conditions.AreInitialized() {
+		conditions := kedav1alpha1.GetInitializedConditions()
+		kedacontrollerutil.SetStatusConditions(r.Client, reqLogger, scaledJob, conditions)
+	}
+
 	var errMsg string
 	if scaledJob.Spec.JobTargetRef != nil {
 		reqLogger.Info("Detected ScaleType = Job")
@@ -76,6 +213,9 @@ func (r *ScaledJobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			reqLogger.V(1).Info(msg)
 			conditions.SetReadyCondition(metav1.ConditionTrue, "ScaledJobReady", msg)
 		}
+		executor.SetPausedCondition(&conditions, executor.IsScaledJobPaused(scaledJob))
+		// the Ready condition gates the creation of Jobs by the scale loop
+		kedacontrollerutil.SetStatusConditions(r.Client, reqLogger, scaledJob, &conditions)
 
 		return ctrl.Result{}, err
 	}
@@ -89,6 +229,80 @@ func (r *ScaledJobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 // reconcileJobType implemets reconciler logic for K8s Jobs based ScaleObject
 func (r *ScaledJobReconciler) reconcileScaledJob(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) (string, error) {
 
+	// Record the label value the Jobs of the ScaledJob are selected by
+	if err := r.updateJobLabelValue(scaledJob); err != nil {
+		return "Failed to update the label value of the Jobs", err
+	}
+
+	// Check the ScalingStrategy is valid
+	if err := validateScaledJobScalingStrategy(scaledJob); err != nil {
+		return "ScaledJob.Spec.ScalingStrategy is not valid", err
+	}
+
+	// Check the immutable fields of the jobTargetRef weren't changed while Jobs are running
+	if err := r.validateImmutableJobTargetRef(scaledJob); err != nil {
+		return "ScaledJob.Spec.JobTargetRef is not valid", err
+	}
+
+	// Check the names of the Jobs are valid
+	if scaledJob.Spec.JobNamePrefix != "" {
+		if err := executor.ValidateJobNamePrefix(scaledJob.Spec.JobNamePrefix); err != nil {
+			return "ScaledJob.Spec.JobNamePrefix is not valid", err
+		}
+	}
+
+	// Check the Jobs can be created in the jobNamespaces
+	if err := r.JobScaleConfig.ValidateJobNamespaces(scaledJob); err != nil {
+		return "ScaledJob.Spec.JobNamespaces is not valid", err
+	}
+
+	// Check the patch can be applied to the template of the Jobs
+	if err := executor.ValidateJobTemplatePatch(scaledJob.Spec.JobTemplatePatch, scaledJob.Spec.JobTargetRef); err != nil {
+		return "ScaledJob.Spec.JobTemplatePatch is not valid", err
+	}
+
+	// Check the external quota service is allowed by the operator
+	if err := r.JobScaleConfig.ValidateExternalQuotaRef(scaledJob.Spec.ExternalQuotaRef); err != nil {
+		return "ScaledJob.Spec.ExternalQuotaRef is not valid", err
+	}
+
+	// Check the semaphore Lease is in a namespace allowed by the operator
+	if err := r.JobScaleConfig.ValidateSemaphoreRef(scaledJob); err != nil {
+		return "ScaledJob.Spec.SemaphoreRef is not valid", err
+	}
+
+	// Check the max job age is valid
+	if multiplier := scaledJob.Spec.MaxJobAgeDurationMultiplier; multiplier != nil && *multiplier < 1 {
+		return "ScaledJob.Spec.MaxJobAgeDurationMultiplier is not valid", fmt.Errorf("maxJobAgeDurationMultiplier must be greater than 0, got %d", *multiplier)
+	}
+
+	// Check the cleanup can run concurrently with the creation of Jobs
+	if scaledJob.Spec.ConcurrentCleanup && scaledJob.Spec.ReplaceFailedJobs {
+		return "ScaledJob.Spec.ConcurrentCleanup is not valid", fmt.Errorf("concurrentCleanup can't be combined with replaceFailedJobs, the failed jobs are replaced within the jobs left after the creation, unset one of them")
+	}
+
+	// Check the stuck jobs are recreated within maxScale, the cleanup running concurrently doesn't know the Jobs left
+	if scaledJob.Spec.ConcurrentCleanup && scaledJob.Spec.StuckJobRecreation != nil {
+		return "ScaledJob.Spec.ConcurrentCleanup is not valid", fmt.Errorf("concurrentCleanup can't be combined with stuckJobRecreation, the stuck jobs are recreated within the jobs left after the creation, unset one of them")
+	}
+
+	// Check the failed jobs are deleted by KEDA, only the deleted jobs are replaced
+	if scaledJob.Spec.SoftDeleteAnnotation && scaledJob.Spec.ReplaceFailedJobs {
+		return "ScaledJob.Spec.SoftDeleteAnnotation is not valid", fmt.Errorf("softDeleteAnnotation can't be combined with replaceFailedJobs, the jobs marked for deletion stay until an external process deletes them and would never be replaced, unset one of them")
+	}
+
+	// Check the ScalingModifiers are valid
+	if scaledJob.Spec.ScalingModifiers != nil {
+		if err := executor.ValidateTimeWindows(scaledJob.Spec.ScalingModifiers.TimeWindows); err != nil {
+			return "ScaledJob.Spec.ScalingModifiers is not valid", err
+		}
+		if scaledJob.Spec.ScalingModifiers.MaxFormula != "" {
+			if err := executor.ValidateMaxFormula(scaledJob.Spec.ScalingModifiers.MaxFormula); err != nil {
+				return "ScaledJob.Spec.ScalingModifiers is not valid", err
+			}
+		}
+	}
+
 	msg, err := r.deletePreviousVersionScaleJobs(logger, scaledJob)
 	if err != nil {
 		return msg, err
@@ -103,6 +317,27 @@ func (r *ScaledJobReconciler) reconcileScaledJob(logger logr.Logger, scaledJob *
 	return "ScaledJob is defined correctly and is ready to scaling", nil
 }
 
+// validateScaledJobScalingStrategy checks the ScalingStrategy and its combination with the other fields of the ScaledJob
+func validateScaledJobScalingStrategy(scaledJob *kedav1alpha1.ScaledJob) error {
+	if err := executor.ValidateScalingStrategyForScaledJob(scaledJob, scaledJob.Spec.ScalingStrategy); err != nil {
+		return err
+	}
+	return executor.ValidateTriggerWeights(scaledJob)
+}
+
+// updateJobLabelValue records the value of the "scaledjob" label the Jobs of the ScaledJob are selected by in its status,
+// names that aren't valid label values are hashed
+func (r *ScaledJobReconciler) updateJobLabelValue(scaledJob *kedav1alpha1.ScaledJob) error {
+	value := executor.GetScaledJobLabelValue(scaledJob)
+	if scaledJob.Status.JobLabelValue == value {
+		return nil
+	}
+
+	patch := client.MergeFrom(scaledJob.DeepCopy())
+	scaledJob.Status.JobLabelValue = value
+	return r.Client.Status().Patch(context.TODO(), scaledJob, patch)
+}
+
 // Delete Jobs owned by the previous version of the scaledJob
 func (r *ScaledJobReconciler) deletePreviousVersionScaleJobs(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) (string, error) {
 	opts := []client.ListOption{
@@ -128,6 +363,33 @@ func (r *ScaledJobReconciler) deletePreviousVersionScaleJobs(logger logr.Logger,
 	return fmt.Sprintf("Deleted jobs owned by the previous version of the scaleJob: %d jobs deleted", jobs.Size()), nil
 }
 
+// validateImmutableJobTargetRef checks the selector of the jobTargetRef matches the selector of the running Jobs,
+// the selector of a Job can't be changed, so the running Jobs and the Jobs created from the changed selector
+// would select different pods
+func (r *ScaledJobReconciler) validateImmutableJobTargetRef(scaledJob *kedav1alpha1.ScaledJob) error {
+	selector := scaledJob.Spec.JobTargetRef.Selector
+	if selector == nil {
+		// the selectors of the Jobs are generated
+		return nil
+	}
+
+	jobs := &batchv1.JobList{}
+	err := r.Client.List(context.TODO(), jobs, client.InNamespace(scaledJob.GetNamespace()), client.MatchingLabels{"scaledjob": executor.GetScaledJobLabelValue(scaledJob)})
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs.Items {
+		if job.Spec.Selector == nil || executor.IsJobFinished(&job) {
+			continue
+		}
+		if !equality.Semantic.DeepEqual(job.Spec.Selector, selector) {
+			return fmt.Errorf("jobTargetRef.selector is immutable while Jobs are running, Job %s was created with selector %s, revert the selector or wait for the running Jobs to finish",
+				job.GetName(), metav1.FormatLabelSelector(job.Spec.Selector))
+		}
+	}
+	return nil
+}
+
 // requestScaleLoop request ScaleLoop handler for the respective ScaledJob
 func (r *ScaledJobReconciler) requestScaleLoop(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
 
@@ -135,3 +397,11 @@ func (r *ScaledJobReconciler) requestScaleLoop(logger logr.Logger, scaledJob *ke
 
 	return r.scaleHandler.HandleScalableObject(scaledJob)
 }
+
+// stopScaleLoop stops ScaleLoop handler for the respective ScaledJob
+func (r *ScaledJobReconciler) stopScaleLoop(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+
+	logger.V(1).Info("Stopping a ScaleLoop")
+
+	return r.scaleHandler.DeleteScalableObject(scaledJob)
+}
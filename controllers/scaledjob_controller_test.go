@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+	"github.com/kedacore/keda/pkg/scaling/executor"
+)
+
+func TestValidateScaledJobScalingStrategyConflicts(t *testing.T) {
+	divisor := int32(2)
+	scaledJob := &kedav1alpha1.ScaledJob{
+		Spec: kedav1alpha1.ScaledJobSpec{
+			ScalingStrategy: &kedav1alpha1.ScalingStrategy{CompletionsDivisor: &divisor},
+		},
+	}
+	assert.Nil(t, validateScaledJobScalingStrategy(scaledJob))
+
+	scaledJob.Spec.ReplaceFailedJobs = true
+	err := validateScaledJobScalingStrategy(scaledJob)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "completionsDivisor can't be combined with replaceFailedJobs")
+}
+
+func TestValidateScaledJobScalingStrategyTriggerWeights(t *testing.T) {
+	scaledJob := &kedav1alpha1.ScaledJob{
+		Spec: kedav1alpha1.ScaledJobSpec{
+			ScalingStrategy: &kedav1alpha1.ScalingStrategy{
+				TriggerWeights: []kedav1alpha1.TriggerWeight{{Trigger: "priority", Weight: 200}},
+			},
+			Triggers: []kedav1alpha1.ScaleTriggers{{Type: "rabbitmq", Name: "priority"}},
+		},
+	}
+	assert.Nil(t, validateScaledJobScalingStrategy(scaledJob))
+
+	scaledJob.Spec.ScalingStrategy.TriggerWeights[0].Trigger = "bulk"
+	err := validateScaledJobScalingStrategy(scaledJob)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `refers to the trigger "bulk"`)
+}
+
+func TestValidateImmutableJobTargetRef(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oldSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "consumer"}}
+	newSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "consumer-v2"}}
+	finished := false
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		job := batchv1.Job{Spec: batchv1.JobSpec{Selector: oldSelector}}
+		job.Name = "consumer-abcde"
+		if finished {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		}
+		list.(*batchv1.JobList).Items = []batchv1.Job{job}
+	}).
+		Return(nil).AnyTimes()
+	r := &ScaledJobReconciler{Client: client}
+
+	scaledJob := &kedav1alpha1.ScaledJob{Spec: kedav1alpha1.ScaledJobSpec{JobTargetRef: &batchv1.JobSpec{Selector: oldSelector}}}
+	assert.Nil(t, r.validateImmutableJobTargetRef(scaledJob))
+
+	// the selector was changed while a job is running
+	scaledJob.Spec.JobTargetRef.Selector = newSelector
+	assert.NotNil(t, r.validateImmutableJobTargetRef(scaledJob))
+
+	// the job created with the previous selector finished
+	finished = true
+	assert.Nil(t, r.validateImmutableJobTargetRef(scaledJob))
+}
+
+func TestUpdateJobLabelValue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock_client.NewMockClient(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	// the unchanged value is patched once
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	client.EXPECT().Status().Return(statusWriter).Times(1)
+	r := &ScaledJobReconciler{Client: client}
+
+	scaledJob := &kedav1alpha1.ScaledJob{}
+	scaledJob.Name = strings.Repeat("consumer-", 10) + "orders"
+	assert.Nil(t, r.updateJobLabelValue(scaledJob))
+	assert.Equal(t, executor.GetScaledJobLabelValue(scaledJob), scaledJob.Status.JobLabelValue)
+	assert.NotEqual(t, scaledJob.Name, scaledJob.Status.JobLabelValue)
+
+	assert.Nil(t, r.updateJobLabelValue(scaledJob))
+}
+
+func TestJobFinishedTriggersScaleLoop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	isController := true
+	runningJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "consumer-abcde",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: kedav1alpha1.GroupVersion.String(),
+				Kind:       "ScaledJob",
+				Name:       "consumer",
+				Controller: &isController,
+			}},
+		},
+	}
+	finishedJob := runningJob.DeepCopy()
+	finishedJob.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+
+	var triggered []string
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: "default", Name: "consumer"}, gomock.Any()).Do(func(_ context.Context, key runtimeclient.ObjectKey, obj runtime.Object) {
+		obj.(*kedav1alpha1.ScaledJob).ObjectMeta = metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}
+	}).
+		Return(nil).Times(1)
+	r := &ScaledJobReconciler{
+		Client:       client,
+		Log:          logf.Log,
+		scaleHandler: &fakeScaleHandler{order: &triggered},
+	}
+
+	// the job completed, the scale loop of its owner is triggered, the owner isn't reconciled
+	update := event.UpdateEvent{MetaOld: runningJob, ObjectOld: runningJob, MetaNew: finishedJob, ObjectNew: finishedJob}
+	r.triggerScaleLoopOnJobFinished(update, nil)
+	assert.Equal(t, []string{"triggerScaleLoop default/consumer"}, triggered)
+	assert.False(t, ownedJobPredicate.Update(update))
+
+	// the updates of a running or finished job don't trigger the scale loop
+	update = event.UpdateEvent{MetaOld: runningJob, ObjectOld: runningJob, MetaNew: runningJob, ObjectNew: runningJob}
+	r.triggerScaleLoopOnJobFinished(update, nil)
+	update = event.UpdateEvent{MetaOld: finishedJob, ObjectOld: finishedJob, MetaNew: finishedJob, ObjectNew: finishedJob}
+	r.triggerScaleLoopOnJobFinished(update, nil)
+
+	// neither does a job not controlled by a ScaledJob
+	ownerless := runningJob.DeepCopy()
+	ownerless.OwnerReferences = nil
+	finishedOwnerless := finishedJob.DeepCopy()
+	finishedOwnerless.OwnerReferences = nil
+	update = event.UpdateEvent{MetaOld: ownerless, ObjectOld: ownerless, MetaNew: finishedOwnerless, ObjectNew: finishedOwnerless}
+	r.triggerScaleLoopOnJobFinished(update, nil)
+	assert.Equal(t, 1, len(triggered))
+}
+
+func TestJobFinishedPredicate(t *testing.T) {
+	isController := true
+	runningJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "consumer-abcde",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: kedav1alpha1.GroupVersion.String(),
+				Kind:       "ScaledJob",
+				Name:       "consumer",
+				Controller: &isController,
+			}},
+		},
+	}
+	finishedJob := runningJob.DeepCopy()
+	finishedJob.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+
+	// the updates of a running job are filtered out
+	update := event.UpdateEvent{MetaOld: runningJob, ObjectOld: runningJob, MetaNew: runningJob, ObjectNew: runningJob}
+	assert.False(t, jobFinishedPredicate.Update(update))
+
+	// the job completed
+	update = event.UpdateEvent{MetaOld: runningJob, ObjectOld: runningJob, MetaNew: finishedJob, ObjectNew: finishedJob}
+	assert.True(t, jobFinishedPredicate.Update(update))
+
+	// the updates of a finished job are filtered out
+	update = event.UpdateEvent{MetaOld: finishedJob, ObjectOld: finishedJob, MetaNew: finishedJob, ObjectNew: finishedJob}
+	assert.False(t, jobFinishedPredicate.Update(update))
+	assert.False(t, jobFinishedPredicate.Create(event.CreateEvent{Meta: runningJob, Object: runningJob}))
+
+	// the main container of the job terminated, its sidecars keep the job running
+	sidecarJob := runningJob.DeepCopy()
+	sidecarJob.Labels = map[string]string{executor.MainContainerTerminatedLabel: string(batchv1.JobComplete)}
+	update = event.UpdateEvent{MetaOld: runningJob, ObjectOld: runningJob, MetaNew: sidecarJob, ObjectNew: sidecarJob}
+	assert.True(t, jobFinishedPredicate.Update(update))
+}
+
+func TestOwnedJobPredicate(t *testing.T) {
+	isController := true
+	labeledJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "consumer-abcde",
+			Namespace: "default",
+			Labels:    map[string]string{"scaledjob": "consumer"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: kedav1alpha1.GroupVersion.String(),
+				Kind:       "ScaledJob",
+				Name:       "consumer",
+				Controller: &isController,
+			}},
+		},
+	}
+	strippedJob := labeledJob.DeepCopy()
+	strippedJob.Labels = nil
+	finishedJob := labeledJob.DeepCopy()
+	finishedJob.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+
+	assert.False(t, ownedJobPredicate.Create(event.CreateEvent{Meta: labeledJob, Object: labeledJob}))
+	// the label was stripped on creation
+	assert.True(t, ownedJobPredicate.Create(event.CreateEvent{Meta: strippedJob, Object: strippedJob}))
+
+	update := event.UpdateEvent{MetaOld: labeledJob, ObjectOld: labeledJob, MetaNew: labeledJob, ObjectNew: labeledJob}
+	assert.False(t, ownedJobPredicate.Update(update))
+	update = event.UpdateEvent{MetaOld: labeledJob, ObjectOld: labeledJob, MetaNew: strippedJob, ObjectNew: strippedJob}
+	assert.True(t, ownedJobPredicate.Update(update))
+	// the finished jobs trigger the scale loop, not a reconcile
+	update = event.UpdateEvent{MetaOld: labeledJob, ObjectOld: labeledJob, MetaNew: finishedJob, ObjectNew: finishedJob}
+	assert.False(t, ownedJobPredicate.Update(update))
+}
+
+func TestScaledJobPredicate(t *testing.T) {
+	scaledJob := &kedav1alpha1.ScaledJob{ObjectMeta: metav1.ObjectMeta{Name: "consumer", Namespace: "default", Generation: 1}}
+
+	// status updates are filtered out
+	update := event.UpdateEvent{MetaOld: scaledJob, ObjectOld: scaledJob, MetaNew: scaledJob, ObjectNew: scaledJob}
+	assert.False(t, scaledJobPredicate.Update(update))
+
+	// the spec changed
+	changed := scaledJob.DeepCopy()
+	changed.Generation = 2
+	update = event.UpdateEvent{MetaOld: scaledJob, ObjectOld: scaledJob, MetaNew: changed, ObjectNew: changed}
+	assert.True(t, scaledJobPredicate.Update(update))
+
+	// a cleanup is requested
+	cleanupRequested := scaledJob.DeepCopy()
+	cleanupRequested.Annotations = map[string]string{executor.CleanupNowAnnotation: "true"}
+	update = event.UpdateEvent{MetaOld: scaledJob, ObjectOld: scaledJob, MetaNew: cleanupRequested, ObjectNew: cleanupRequested}
+	assert.True(t, scaledJobPredicate.Update(update))
+
+	assert.True(t, scaledJobPredicate.Create(event.CreateEvent{Meta: scaledJob, Object: scaledJob}))
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+	"github.com/kedacore/keda/pkg/scalers"
+)
+
+func TestEnsureFinalizerAddsFinalizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	r := &ScaledJobReconciler{Client: client}
+	scaledJob := &kedav1alpha1.ScaledJob{}
+
+	err := r.ensureFinalizer(logf.Log, scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{scaledJobFinalizer}, scaledJob.GetFinalizers())
+}
+
+func TestEnsureFinalizerAlreadyPresent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no Update is expected when the finalizer is already present
+	client := mock_client.NewMockClient(ctrl)
+
+	r := &ScaledJobReconciler{Client: client}
+	scaledJob := &kedav1alpha1.ScaledJob{}
+	scaledJob.SetFinalizers([]string{scaledJobFinalizer})
+
+	err := r.ensureFinalizer(logf.Log, scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{scaledJobFinalizer}, scaledJob.GetFinalizers())
+}
+
+func TestFinalizeScaledJobOrderedCleanup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var order []string
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.UpdateOption) {
+		order = append(order, "removeFinalizer")
+	}).Return(nil).Times(1)
+
+	r := &ScaledJobReconciler{
+		Client:        client,
+		scaleHandler:  &fakeScaleHandler{order: &order},
+		scaleExecutor: &fakeJobScaleExecutor{order: &order},
+	}
+	scaledJob := &kedav1alpha1.ScaledJob{}
+	scaledJob.SetFinalizers([]string{scaledJobFinalizer})
+
+	err := r.finalizeScaledJob(logf.Log, scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"stopScaleLoop", "finalizeJobs", "removeFinalizer"}, order)
+	assert.Empty(t, scaledJob.GetFinalizers())
+}
+
+func TestFinalizeScaledJobKeepsFinalizerOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no Update is expected, the finalizer has to stay so the cleanup is retried
+	client := mock_client.NewMockClient(ctrl)
+
+	var order []string
+	r := &ScaledJobReconciler{
+		Client:        client,
+		scaleHandler:  &fakeScaleHandler{order: &order},
+		scaleExecutor: &fakeJobScaleExecutor{order: &order, err: assert.AnError},
+	}
+	scaledJob := &kedav1alpha1.ScaledJob{}
+	scaledJob.SetFinalizers([]string{scaledJobFinalizer})
+
+	err := r.finalizeScaledJob(logf.Log, scaledJob)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{scaledJobFinalizer}, scaledJob.GetFinalizers())
+}
+
+type fakeScaleHandler struct {
+	order *[]string
+}
+
+func (h *fakeScaleHandler) HandleScalableObject(scalableObject interface{}) error {
+	return nil
+}
+
+func (h *fakeScaleHandler) DeleteScalableObject(scalableObject interface{}) error {
+	*h.order = append(*h.order, "stopScaleLoop")
+	return nil
+}
+
+func (h *fakeScaleHandler) TriggerScaleLoop(scalableObject interface{}) {
+	scaledJob := scalableObject.(*kedav1alpha1.ScaledJob)
+	*h.order = append(*h.order, "triggerScaleLoop "+scaledJob.Namespace+"/"+scaledJob.Name)
+}
+
+func (h *fakeScaleHandler) GetScalers(scalableObject interface{}) ([]scalers.Scaler, error) {
+	return nil, nil
+}
+
+type fakeJobScaleExecutor struct {
+	order *[]string
+	err   error
+}
+
+func (e *fakeJobScaleExecutor) RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64, backlogAge time.Duration, scaledAt time.Time) (time.Duration, error) {
+	return 0, nil
+}
+
+func (e *fakeJobScaleExecutor) FinalizeScaledJob(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error {
+	*e.order = append(*e.order, "finalizeJobs")
+	return e.err
+}
+
+func (e *fakeJobScaleExecutor) CleanUpNow(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error {
+	*e.order = append(*e.order, "cleanUpNow")
+	return e.err
+}
+
+func (e *fakeJobScaleExecutor) RestoreJobLabels(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error {
+	*e.order = append(*e.order, "restoreJobLabels")
+	return e.err
+}
@@ -26,6 +26,7 @@ import (
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
 	kedacontrollerutil "github.com/kedacore/keda/controllers/util"
 	"github.com/kedacore/keda/pkg/scaling"
+	"github.com/kedacore/keda/pkg/scaling/executor"
 	kedautil "github.com/kedacore/keda/pkg/util"
 )
 
@@ -80,7 +81,7 @@ func (r *ScaledObjectReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Init the rest of ScaledObjectReconciler
 	r.restMapper = mgr.GetRESTMapper()
 	r.scaledObjectsGenerations = &sync.Map{}
-	r.scaleHandler = scaling.NewScaleHandler(mgr.GetClient(), r.scaleClient, mgr.GetScheme())
+	r.scaleHandler = scaling.NewScaleHandler(mgr.GetClient(), r.scaleClient, mgr.GetScheme(), mgr.GetEventRecorderFor("keda-operator"), executor.DefaultConfig())
 
 	// Start controller
 	return ctrl.NewControllerManagedBy(mgr).
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// NewClientUncachedFor returns the function creating the client of the manager, the passed objects are read from
+// the API server and all the other objects from the informer cache. The informers of the manager watch the whole cluster,
+// the objects only listed with label selectors, eg. the pods of the Jobs, would be cached for the whole cluster otherwise
+func NewClientUncachedFor(objects ...runtime.Object) manager.NewClientFunc {
+	return func(cache cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
+		c, err := client.New(config, options)
+		if err != nil {
+			return nil, err
+		}
+
+		uncached := map[schema.GroupVersionKind]bool{}
+		for _, obj := range objects {
+			gvk, err := apiutil.GVKForObject(obj, options.Scheme)
+			if err != nil {
+				return nil, err
+			}
+			uncached[gvk] = true
+		}
+
+		return &client.DelegatingClient{
+			Reader: &uncachedReader{
+				cacheReader:  cache,
+				clientReader: c,
+				scheme:       options.Scheme,
+				uncached:     uncached,
+			},
+			Writer:       c,
+			StatusClient: c,
+		}, nil
+	}
+}
+
+// uncachedReader reads the uncached objects, and the unstructured ones like the default client of the manager,
+// from the API server
+type uncachedReader struct {
+	cacheReader  client.Reader
+	clientReader client.Reader
+	scheme       *runtime.Scheme
+	uncached     map[schema.GroupVersionKind]bool
+}
+
+func (r *uncachedReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if r.isUncached(obj) {
+		return r.clientReader.Get(ctx, key, obj)
+	}
+	return r.cacheReader.Get(ctx, key, obj)
+}
+
+func (r *uncachedReader) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if r.isUncached(list) {
+		return r.clientReader.List(ctx, list, opts...)
+	}
+	return r.cacheReader.List(ctx, list, opts...)
+}
+
+// isUncached returns true if the object, or the items of the list, are read from the API server
+func (r *uncachedReader) isUncached(obj runtime.Object) bool {
+	switch obj.(type) {
+	case *unstructured.Unstructured, *unstructured.UnstructuredList:
+		return true
+	}
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return false
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	return r.uncached[gvk]
+}
@@ -46,6 +46,7 @@ require (
 	k8s.io/metrics v0.18.8
 	knative.dev/pkg v0.0.0-20200911145400-2d4efecc6bc1
 	sigs.k8s.io/controller-runtime v0.6.2
+	sigs.k8s.io/yaml v1.2.0
 )
 
 replace (
@@ -17,11 +17,16 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"runtime"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -29,9 +34,12 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
 	"github.com/kedacore/keda/controllers"
+	kedacontrollerutil "github.com/kedacore/keda/controllers/util"
+	"github.com/kedacore/keda/pkg/scaling/executor"
 	"github.com/kedacore/keda/version"
 	// +kubebuilder:scaffold:imports
 )
@@ -50,10 +58,54 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var operatorID string
+	var jobScaleStateAddr string
+	var defaultScalingStrategy string
+	var maxTotalJobs int64
+	var jobCreationBudget time.Duration
+	var pendingPodsThreshold int64
+	var externalQuotaAllowedHosts string
+	var semaphoreNamespaces string
+	var allowedJobNamespaces string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&operatorID, "operator-id", os.Getenv("KEDA_OPERATOR_ID"),
+		"The id of this KEDA instance, Jobs created by this instance are labeled with it. "+
+			"Defaults to the value of KEDA_OPERATOR_ID environment variable.")
+
+	flag.StringVar(&jobScaleStateAddr, "job-scale-state-addr", "",
+		"The loopback address the endpoints serving the last scaling decision and the in-memory state of ScaledJobs bind to, eg. 127.0.0.1:8082. "+
+			"The endpoints are served without authentication, they are reachable with kubectl port-forward. The endpoints are disabled if empty.")
+
+	flag.StringVar(&defaultScalingStrategy, "default-scaling-strategy", "",
+		"The scalingStrategy, in JSON, of the ScaledJobs that don't define one, eg. {\"strategy\": \"percentage\", \"percentage\": 50}. "+
+			"The "+executor.DefaultScalingStrategyAnnotation+" annotation of a Namespace takes precedence.")
+
+	flag.Int64Var(&maxTotalJobs, "max-total-jobs", 0,
+		"The maximum number of Jobs running for all ScaledJobs, no Jobs are created once it is reached. "+
+			"The limit is disabled if 0.")
+
+	flag.DurationVar(&jobCreationBudget, "job-creation-budget", 20*time.Second,
+		"The time the creation of the Jobs of a single scaling of a ScaledJob may take, the remaining Jobs are created by the next scaling. "+
+			"The budget is disabled if 0.")
+
+	flag.Int64Var(&pendingPodsThreshold, "pending-pods-threshold", 100,
+		"The number of the unscheduled pods of the Jobs of a ScaledJob with respectPendingPressure no more of its Jobs are created at. "+
+			"The throttling is disabled if 0.")
+
+	flag.StringVar(&externalQuotaAllowedHosts, "external-quota-allowed-hosts", "",
+		"The comma-separated hosts, with an optional port, the external quota services of the ScaledJobs can run on, eg. quota.quota-system.svc:8080. "+
+			"No external quota service can be used if empty.")
+
+	flag.StringVar(&semaphoreNamespaces, "semaphore-namespaces", "",
+		"The comma-separated namespaces the semaphore Leases shared by the ScaledJobs of several namespaces can be in. "+
+			"The ScaledJobs can only use a semaphore in their own namespace if empty.")
+
+	flag.StringVar(&allowedJobNamespaces, "allowed-job-namespaces", "",
+		"The comma-separated namespaces the ScaledJobs can spread their Jobs across with jobNamespaces, besides their own. "+
+			"The ScaledJobs can only create Jobs in their own namespace if empty.")
 
 	// Add the zap logger flag set to the CLI.
 	opts := zap.Options{}
@@ -71,12 +123,94 @@ func main() {
 		Port:                   9443,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "operator.keda.sh",
+		// the pods of the Jobs and the nodes are read from the API server, they would be cached for the whole cluster otherwise
+		NewClient: kedacontrollerutil.NewClientUncachedFor(&corev1.Pod{}, &corev1.Node{}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	jobScaleConfig := executor.Config{
+		OperatorID:           operatorID,
+		MaxTotalJobs:         maxTotalJobs,
+		JobCreationBudget:    jobCreationBudget,
+		PendingPodsThreshold: pendingPodsThreshold,
+	}
+	if externalQuotaAllowedHosts != "" {
+		jobScaleConfig.ExternalQuotaAllowedHosts = strings.Split(externalQuotaAllowedHosts, ",")
+	}
+	if semaphoreNamespaces != "" {
+		jobScaleConfig.SemaphoreNamespaces = strings.Split(semaphoreNamespaces, ",")
+	}
+	if allowedJobNamespaces != "" {
+		jobScaleConfig.AllowedJobNamespaces = strings.Split(allowedJobNamespaces, ",")
+	}
+
+	if defaultScalingStrategy != "" {
+		strategy, err := executor.ParseScalingStrategy(defaultScalingStrategy)
+		if err != nil {
+			setupLog.Error(err, "Invalid default scaling strategy")
+			os.Exit(1)
+		}
+		jobScaleConfig.DefaultScalingStrategy = strategy
+	}
+
+	// Jobs are created by the elected leader only
+	if enableLeaderElection {
+		executor.SetLeaderElected(false)
+		err = mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+			executor.SetLeaderElected(true)
+			<-stop
+			executor.SetLeaderElected(false)
+			return nil
+		}))
+		if err != nil {
+			setupLog.Error(err, "Unable to add a leader election runnable")
+			os.Exit(1)
+		}
+	}
+
+	// Jobs are created only once the cache holds the existing Jobs, otherwise they are not counted after a restart
+	executor.SetCacheSynced(false)
+	err = mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		if mgr.GetCache().WaitForCacheSync(stop) {
+			executor.SetCacheSynced(true)
+		}
+		return nil
+	}))
+	if err != nil {
+		setupLog.Error(err, "Unable to add a cache sync runnable")
+		os.Exit(1)
+	}
+
+	// Serve the last scaling decision of ScaledJobs, eg. /scaledjobs?namespace=default&name=consumer,
+	// and the in-memory state of the executor for support, eg. /debug/caches?namespace=default
+	if jobScaleStateAddr != "" {
+		if err := executor.ValidateJobScaleStateAddr(jobScaleStateAddr); err != nil {
+			setupLog.Error(err, "Invalid job scale state address")
+			os.Exit(1)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/scaledjobs", executor.JobScaleStateHandler)
+		mux.HandleFunc("/debug/caches", executor.JobScaleCachesHandler)
+		server := &http.Server{Addr: jobScaleStateAddr, Handler: mux}
+		err = mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+			go func() {
+				<-stop
+				_ = server.Shutdown(context.Background())
+			}()
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				return err
+			}
+			return nil
+		}))
+		if err != nil {
+			setupLog.Error(err, "Unable to add the job scale state endpoint")
+			os.Exit(1)
+		}
+	}
+
 	// Add readiness probe
 	err = mgr.AddReadyzCheck("ready-ping", healthz.Ping)
 	if err != nil {
@@ -84,6 +218,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Add readiness probe reflecting whether the ScaledJob executor keeps up
+	err = mgr.AddReadyzCheck("job-scale", executor.JobScaleHealthCheck)
+	if err != nil {
+		setupLog.Error(err, "Unable to add a job scale readiness check")
+		os.Exit(1)
+	}
+
 	// Add liveness probe
 	err = mgr.AddHealthzCheck("health-ping", healthz.Ping)
 	if err != nil {
@@ -100,9 +241,10 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controllers.ScaledJobReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ScaledJob"),
-		Scheme: mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		Log:            ctrl.Log.WithName("controllers").WithName("ScaledJob"),
+		Scheme:         mgr.GetScheme(),
+		JobScaleConfig: jobScaleConfig,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ScaledJob")
 		os.Exit(1)
@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Backpressure after the first failed or slow RequestJobScale call of a ScaledJob, it doubles with every further one
+	defaultBackpressureBase = 10 * time.Second
+	// Upper bound of the backpressure of a ScaledJob
+	defaultBackpressureMax = 5 * time.Minute
+)
+
+// jobScaleBackpressure counts the consecutive failed or slow RequestJobScale calls of every ScaledJob
+// by its namespace and name, the scaling loop slows down the polling of a ScaledJob under backpressure
+// so the API server isn't hammered with operations that keep failing
+type jobScaleBackpressure struct {
+	counts sync.Map
+}
+
+var backpressure = &jobScaleBackpressure{}
+
+// record records the outcome of a RequestJobScale call of the ScaledJob, a failed call or a call exceeding
+// the budget of the executor increases the backpressure, any other call resets it
+func (b *jobScaleBackpressure) record(namespace string, name string, failed bool, duration time.Duration) {
+	key := jobScaleStateKey(namespace, name)
+	if !failed && duration <= defaultRequestJobScaleBudget {
+		b.counts.Delete(key)
+		backpressureSeconds.DeleteLabelValues(namespace, name)
+		return
+	}
+
+	count := 1
+	if existing, ok := b.counts.Load(key); ok {
+		count = existing.(int) + 1
+	}
+	b.counts.Store(key, count)
+	backpressureSeconds.WithLabelValues(namespace, name).Set(getBackpressureDelay(count).Seconds())
+}
+
+func (b *jobScaleBackpressure) get(namespace string, name string) time.Duration {
+	count, ok := b.counts.Load(jobScaleStateKey(namespace, name))
+	if !ok {
+		return 0
+	}
+	return getBackpressureDelay(count.(int))
+}
+
+func (b *jobScaleBackpressure) remove(namespace string, name string) {
+	b.counts.Delete(jobScaleStateKey(namespace, name))
+	backpressureSeconds.DeleteLabelValues(namespace, name)
+}
+
+// getBackpressureDelay returns the delay after count consecutive failed or slow calls
+func getBackpressureDelay(count int) time.Duration {
+	delay := defaultBackpressureBase
+	for i := 1; i < count; i++ {
+		delay *= 2
+		if delay >= defaultBackpressureMax {
+			return defaultBackpressureMax
+		}
+	}
+	return delay
+}
+
+// GetJobScaleBackpressure returns the delay the scaling loop should add to the pollingInterval of the ScaledJob,
+// 0 means the ScaledJob is not under backpressure. The delay grows while the creations or deletions of its Jobs
+// keep failing or the scaling keeps exceeding its budget
+func GetJobScaleBackpressure(namespace string, name string) time.Duration {
+	return backpressure.get(namespace, name)
+}
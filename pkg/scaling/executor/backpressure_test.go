@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestRequestJobScaleBackpressure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "backpressure"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	defer backpressure.remove(scaledJob.Namespace, scaledJob.Name)
+
+	// the creations of the Jobs fail
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	client.EXPECT().Create(gomock.Any(), gomock.Any()).Return(fmt.Errorf("etcdserver: request timed out")).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Error(t, err)
+	assert.Equal(t, 10*time.Second, GetJobScaleBackpressure(scaledJob.Namespace, scaledJob.Name))
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Error(t, err)
+	assert.Equal(t, 20*time.Second, GetJobScaleBackpressure(scaledJob.Namespace, scaledJob.Name))
+
+	// the Jobs can be created again
+	var createdJobs []*batchv1.Job
+	client = getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor = getMockScaleExecutor(client)
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), GetJobScaleBackpressure(scaledJob.Namespace, scaledJob.Name))
+}
+
+func TestRequestJobScaleBackpressureOnFailedDeletion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(0, 0)
+	scaledJob.Name = "backpressure-deletion"
+	defer backpressure.remove(scaledJob.Namespace, scaledJob.Name)
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		if j, ok := list.(*batchv1.JobList); ok {
+			j.Items = []batchv1.Job{*getJob(t, "success1", "2020-07-29T15:31:00Z", batchv1.JobComplete)}
+		}
+	}).
+		Return(nil).AnyTimes()
+	client.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("etcdserver: request timed out")).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 10, 0, time.Time{})
+
+	assert.Error(t, err)
+	assert.Equal(t, 10*time.Second, GetJobScaleBackpressure(scaledJob.Namespace, scaledJob.Name))
+}
+
+func TestBackpressureOnSlowRequests(t *testing.T) {
+	defer backpressure.remove("default", "slow")
+
+	backpressure.record("default", "slow", false, defaultRequestJobScaleBudget+time.Second)
+	assert.Equal(t, defaultBackpressureBase, GetJobScaleBackpressure("default", "slow"))
+
+	backpressure.record("default", "slow", false, time.Second)
+	assert.Equal(t, time.Duration(0), GetJobScaleBackpressure("default", "slow"))
+}
+
+func TestGetBackpressureDelay(t *testing.T) {
+	assert.Equal(t, 10*time.Second, getBackpressureDelay(1))
+	assert.Equal(t, 40*time.Second, getBackpressureDelay(3))
+	// the delay is capped
+	assert.Equal(t, defaultBackpressureMax, getBackpressureDelay(10))
+}
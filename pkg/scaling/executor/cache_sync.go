@@ -0,0 +1,23 @@
+package executor
+
+import (
+	"sync/atomic"
+)
+
+// cacheSynced is set to 1 once the cache of the client holds the existing Jobs, right after a restart
+// the cache is cold and the running Jobs would be counted as 0, creating a burst of new Jobs
+var cacheSynced int32 = 1
+
+// SetCacheSynced sets whether the cache used to count the running Jobs is synced
+func SetCacheSynced(synced bool) {
+	if synced {
+		atomic.StoreInt32(&cacheSynced, 1)
+	} else {
+		atomic.StoreInt32(&cacheSynced, 0)
+	}
+}
+
+// IsCacheSynced returns true if the cache used to count the running Jobs is synced
+func IsCacheSynced() bool {
+	return atomic.LoadInt32(&cacheSynced) == 1
+}
@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// ProbeCompletedLabel is set to "true" on the Jobs whose completion probe succeeded,
+	// such Jobs are considered complete even though the Job controller didn't complete them
+	ProbeCompletedLabel = "keda.sh/probe-completed"
+
+	// Default timeout of the request to the completion probe of a pod
+	defaultCompletionProbeTimeoutSeconds = 1
+	// Number of the Jobs whose pods are probed at the same time
+	maxConcurrentCompletionProbes = 10
+	// Duration all the probes of a scaling must complete within, the Jobs not probed by then are probed
+	// by the next scaling, so a lot of unresponsive pods don't delay the creation of the Jobs
+	completionProbesDeadline = 10 * time.Second
+)
+
+// detectProbeCompletedJobs probes the running pods of the running Jobs of the ScaledJob concurrently and labels
+// the Jobs whose pod reports the work as done with the ProbeCompletedLabel, so they no longer count as running
+func (e *scaleExecutor) detectProbeCompletedJobs(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+	probe := scaledJob.Spec.CompletionProbe
+
+	jobs, err := e.listJobs(ctx, scaledJob)
+	if err != nil {
+		return err
+	}
+
+	pods, err := e.listJobPods(ctx, scaledJob)
+	if err != nil {
+		return err
+	}
+
+	jobPods := make(map[string][]*corev1.Pod)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" {
+			jobName := getPodJobName(pod)
+			jobPods[jobName] = append(jobPods[jobName], pod)
+		}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, completionProbesDeadline)
+	defer cancel()
+	slots := make(chan struct{}, maxConcurrentCompletionProbes)
+	// the pod reporting the work as done, by the index of its Job
+	completedPods := make([]*corev1.Pod, len(jobs.Items))
+	var wg sync.WaitGroup
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if IsJobFinished(job) || isJobSuspended(job) || len(jobPods[job.GetName()]) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, job *batchv1.Job) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-probeCtx.Done():
+				return
+			}
+
+			for _, pod := range jobPods[job.GetName()] {
+				completed, err := probeJobCompletion(probeCtx, probe, pod.Status.PodIP)
+				if err != nil {
+					logger.V(1).Info("Failed to probe the completion of the pod", "job.Name", job.GetName(), "pod.Name", pod.GetName(), "error", err.Error())
+					continue
+				}
+				if completed {
+					completedPods[i] = pod
+					return
+				}
+			}
+		}(i, job)
+	}
+	wg.Wait()
+
+	for i, pod := range completedPods {
+		if pod == nil {
+			continue
+		}
+		job := &jobs.Items[i]
+		patch := client.MergeFrom(job.DeepCopy())
+		if job.Labels == nil {
+			job.Labels = map[string]string{}
+		}
+		job.Labels[ProbeCompletedLabel] = "true"
+		err = e.client.Patch(ctx, job, patch)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Job completed by the completion probe", "job.Name", job.GetName(), "pod.Name", pod.GetName())
+	}
+	return nil
+}
+
+// probeJobCompletion requests the completion probe endpoint of the pod, the work of the pod is done
+// if the endpoint responds with a 2xx status
+func probeJobCompletion(ctx context.Context, probe *kedav1alpha1.CompletionProbe, podIP string) (bool, error) {
+	timeout := time.Second * defaultCompletionProbeTimeoutSeconds
+	if probe.TimeoutSeconds != nil {
+		timeout = time.Second * time.Duration(*probe.TimeoutSeconds)
+	}
+	path := probe.Path
+	if path == "" {
+		path = "/"
+	}
+
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(podIP, strconv.Itoa(int(probe.Port))), path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices, nil
+}
+
+// isJobCompletedByProbe returns true if the completion probe of the Job succeeded
+func isJobCompletedByProbe(job *batchv1.Job) bool {
+	return job.Labels[ProbeCompletedLabel] == "true"
+}
@@ -0,0 +1,197 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestDetectProbeCompletedJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var requestedPaths []string
+	statusCode := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+	host, port := getStubProbeAddress(t, server)
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.CompletionProbe = &kedav1alpha1.CompletionProbe{Port: port, Path: "/done"}
+
+	finishedJob := *getJob(t, "finished", "2020-07-29T15:31:00Z", batchv1.JobComplete)
+	jobs := []batchv1.Job{
+		{ObjectMeta: metav1.ObjectMeta{Name: "lingering"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pending"}},
+		finishedJob,
+	}
+	pods := []corev1.Pod{
+		getCompletionProbePod("lingering", corev1.PodRunning, host),
+		getCompletionProbePod("pending", corev1.PodPending, ""),
+		getCompletionProbePod("finished", corev1.PodRunning, host),
+	}
+
+	var patchedJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		switch l := list.(type) {
+		case *batchv1.JobList:
+			for _, job := range jobs {
+				l.Items = append(l.Items, *job.DeepCopy())
+			}
+		case *corev1.PodList:
+			l.Items = pods
+		}
+	}).
+		Return(nil).AnyTimes()
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchedJobs = append(patchedJobs, obj.(*batchv1.Job))
+	}).
+		Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// the pod is still working, only the running pod of the running job is probed
+	err := scaleExecutor.detectProbeCompletedJobs(context.TODO(), scaleExecutor.logger, scaledJob)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/done"}, requestedPaths)
+	assert.Equal(t, 0, len(patchedJobs))
+
+	// the work is done, the job is labeled as completed
+	statusCode = http.StatusOK
+	err = scaleExecutor.detectProbeCompletedJobs(context.TODO(), scaleExecutor.logger, scaledJob)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(patchedJobs))
+	assert.Equal(t, "lingering", patchedJobs[0].Name)
+	assert.Equal(t, "true", patchedJobs[0].Labels[ProbeCompletedLabel])
+
+	// the job completed by the probe is finished and no longer counts as running
+	assert.True(t, IsJobFinished(patchedJobs[0]))
+	assert.Equal(t, batchv1.JobComplete, scaleExecutor.getFinishedJobConditionType(patchedJobs[0]))
+	assert.Equal(t, int64(1), scaleExecutor.countRunningJobs([]batchv1.Job{jobs[1], *patchedJobs[0]}))
+}
+
+func TestDetectProbeCompletedJobsConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the probes only succeed once the pods of all the jobs are probed at the same time
+	var mutex sync.Mutex
+	probing := 0
+	allProbing := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		probing++
+		if probing == 3 {
+			close(allProbing)
+		}
+		mutex.Unlock()
+		select {
+		case <-allProbing:
+			w.WriteHeader(http.StatusOK)
+		case <-time.After(500 * time.Millisecond):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	host, port := getStubProbeAddress(t, server)
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.CompletionProbe = &kedav1alpha1.CompletionProbe{Port: port}
+
+	var jobs []batchv1.Job
+	var pods []corev1.Pod
+	for _, name := range []string{"first", "second", "third"} {
+		jobs = append(jobs, batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		pods = append(pods, getCompletionProbePod(name, corev1.PodRunning, host))
+	}
+
+	var patchedJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		switch l := list.(type) {
+		case *batchv1.JobList:
+			l.Items = jobs
+		case *corev1.PodList:
+			l.Items = pods
+		}
+	}).
+		Return(nil).Times(2)
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchedJobs = append(patchedJobs, obj.(*batchv1.Job))
+	}).
+		Return(nil).Times(3)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.detectProbeCompletedJobs(context.TODO(), scaleExecutor.logger, scaledJob)
+
+	assert.Nil(t, err)
+	// the jobs are labeled in their order
+	assert.Equal(t, 3, len(patchedJobs))
+	assert.Equal(t, "first", patchedJobs[0].Name)
+	assert.Equal(t, "third", patchedJobs[2].Name)
+}
+
+func TestProbeJobCompletionUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	host, port := getStubProbeAddress(t, server)
+	server.Close()
+
+	completed, err := probeJobCompletion(context.TODO(), &kedav1alpha1.CompletionProbe{Port: port}, host)
+	assert.NotNil(t, err)
+	assert.False(t, completed)
+}
+
+// getStubProbeAddress returns the host and port of the stub probe server
+func getStubProbeAddress(t *testing.T, server *httptest.Server) (string, int32) {
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, portString, err := net.SplitHostPort(serverURL.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(portString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return host, int32(port)
+}
+
+// getCompletionProbePod returns a pod of the Job in the phase with the IP
+func getCompletionProbePod(jobName string, phase corev1.PodPhase, podIP string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   jobName + "-pod",
+			Labels: map[string]string{"job-name": jobName},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+			PodIP: podIP,
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// Config is the operator-level configuration of the scaling of the ScaledJobs, it is set by the flags of the operator
+// and held by the scale executor
+type Config struct {
+	// OperatorID identifies this KEDA instance, Jobs are labeled with it and only Jobs carrying the same id
+	// are counted and cleaned up by this instance. An empty id disables the labeling and filtering of Jobs
+	OperatorID string
+	// MaxTotalJobs is the limit of the Jobs running for all ScaledJobs, 0 disables the limit
+	MaxTotalJobs int64
+	// JobCreationBudget is the time the creation of the Jobs of a single scaling may take, the remaining Jobs
+	// are created by the next scaling. 0 disables the budget
+	JobCreationBudget time.Duration
+	// PendingPodsThreshold is the number of the unscheduled pods of the Jobs of a ScaledJob with respectPendingPressure
+	// no more of its Jobs are created at, 0 disables the throttling
+	PendingPodsThreshold int64
+	// DefaultScalingStrategy is the ScalingStrategy of the ScaledJobs that don't define one, unless their Namespace
+	// defines a default, nil keeps the "default" strategy
+	DefaultScalingStrategy *kedav1alpha1.ScalingStrategy
+	// ExternalQuotaAllowedHosts are the hosts, with an optional port, the external quota services of the ScaledJobs
+	// can run on, eg. quota.quota-system.svc:8080. No external quota service can be used if empty
+	ExternalQuotaAllowedHosts []string
+	// SemaphoreNamespaces are the namespaces, besides their own, the ScaledJobs can share a semaphore Lease in
+	SemaphoreNamespaces []string
+	// AllowedJobNamespaces are the namespaces, besides their own, the ScaledJobs can create their Jobs in with jobNamespaces
+	AllowedJobNamespaces []string
+}
+
+// DefaultConfig returns the Config with the default job creation budget and pending pods threshold,
+// the other limits are disabled
+func DefaultConfig() Config {
+	return Config{
+		JobCreationBudget:    defaultJobCreationBudget,
+		PendingPodsThreshold: defaultPendingPodsThreshold,
+	}
+}
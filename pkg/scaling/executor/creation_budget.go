@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"sync"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// Time the creation of the Jobs of a single scaling may take, the remaining Jobs are created by the next scaling.
+	// It stays below the budget of RequestJobScale, so the cleanup still fits in
+	defaultJobCreationBudget = 20 * time.Second
+)
+
+// deferredJobCounts stores the number of Jobs the last scaling of every ScaledJob deferred by its namespace and name,
+// the Jobs are deferred by the creation rate limit or the creation budget
+type deferredJobCounts struct {
+	counts sync.Map
+}
+
+var deferredJobs = &deferredJobCounts{}
+
+func (d *deferredJobCounts) record(namespace string, name string, count int64) {
+	key := jobScaleStateKey(namespace, name)
+	if count <= 0 {
+		d.counts.Delete(key)
+		return
+	}
+	d.counts.Store(key, count)
+}
+
+func (d *deferredJobCounts) remove(namespace string, name string) {
+	d.counts.Delete(jobScaleStateKey(namespace, name))
+}
+
+// GetDeferredJobCount returns the number of Jobs the last scaling of the ScaledJob deferred to the next scaling
+func GetDeferredJobCount(namespace string, name string) int64 {
+	count, ok := deferredJobs.counts.Load(jobScaleStateKey(namespace, name))
+	if !ok {
+		return 0
+	}
+	return count.(int64)
+}
+
+// isCreationDeferred returns true if the last scaling of the ScaledJob deferred the creation of some of its Jobs
+func isCreationDeferred(scaledJob *kedav1alpha1.ScaledJob) bool {
+	return GetDeferredJobCount(scaledJob.Namespace, scaledJob.Name) > 0
+}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+func TestRequestJobScaleWithCreationBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "creation-budget"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	defer deferredJobs.remove(scaledJob.Namespace, scaledJob.Name)
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	// the budget is exceeded once the first job is created
+	scaleExecutor.config.JobCreationBudget = time.Nanosecond
+
+	// the remaining jobs are deferred to the next scaling
+	requeueAfter, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(createdJobs))
+	assert.Equal(t, int64(4), GetDeferredJobCount(scaledJob.Namespace, scaledJob.Name))
+	assert.Equal(t, getRequeueInterval(scaledJob), requeueAfter)
+
+	// the budget is disabled, the remaining jobs are created
+	scaleExecutor.config.JobCreationBudget = 0
+	requeueAfter, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 4, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(createdJobs))
+	assert.Equal(t, int64(0), GetDeferredJobCount(scaledJob.Namespace, scaledJob.Name))
+	assert.Equal(t, time.Duration(0), requeueAfter)
+}
+
+func TestCreateJobsWithinCreationBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "within-creation-budget"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	defer deferredJobs.remove(scaledJob.Namespace, scaledJob.Name)
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 20, 20, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20), count)
+	assert.Equal(t, 20, len(createdJobs))
+	assert.False(t, isCreationDeferred(scaledJob))
+}
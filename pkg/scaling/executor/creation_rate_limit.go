@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"sync"
+
+	"k8s.io/client-go/util/flowcontrol"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// creationRateLimiter is the token bucket limiting the creation of the Jobs of a ScaledJob
+type creationRateLimiter struct {
+	limiter flowcontrol.RateLimiter
+	spec    kedav1alpha1.CreationRateLimit
+}
+
+// creationRateLimiters stores the creationRateLimiter of every ScaledJob by its namespace and name,
+// the buckets outlive a single scaling so the rate applies across the polling intervals
+type creationRateLimiters struct {
+	limiters sync.Map
+}
+
+var rateLimiters = &creationRateLimiters{}
+
+// get returns the creationRateLimiter of the ScaledJob, nil if the creation of its Jobs is not limited.
+// The bucket is replaced when the rate or burst of the ScaledJob changes
+func (l *creationRateLimiters) get(scaledJob *kedav1alpha1.ScaledJob) *creationRateLimiter {
+	key := jobScaleStateKey(scaledJob.Namespace, scaledJob.Name)
+	spec := scaledJob.Spec.CreationRateLimit
+	if spec == nil || spec.JobsPerMinute <= 0 {
+		l.limiters.Delete(key)
+		return nil
+	}
+
+	if existing, ok := l.limiters.Load(key); ok {
+		limiter := existing.(*creationRateLimiter)
+		if limiter.spec.JobsPerMinute == spec.JobsPerMinute && int32PtrEqual(limiter.spec.Burst, spec.Burst) {
+			return limiter
+		}
+	}
+
+	burst := int(spec.JobsPerMinute)
+	if spec.Burst != nil && *spec.Burst > 0 {
+		burst = int(*spec.Burst)
+	}
+	limiter := &creationRateLimiter{
+		limiter: flowcontrol.NewTokenBucketRateLimiter(float32(spec.JobsPerMinute)/60, burst),
+		spec:    *spec.DeepCopy(),
+	}
+	l.limiters.Store(key, limiter)
+	return limiter
+}
+
+func (l *creationRateLimiters) remove(namespace string, name string) {
+	l.limiters.Delete(jobScaleStateKey(namespace, name))
+}
+
+func int32PtrEqual(a *int32, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestRequestJobScaleWithCreationRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "creation-rate-limit"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	burst := int32(2)
+	scaledJob.Spec.CreationRateLimit = &kedav1alpha1.CreationRateLimit{JobsPerMinute: 1, Burst: &burst}
+	defer rateLimiters.remove(scaledJob.Namespace, scaledJob.Name)
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// the burst is created, the remaining jobs are deferred to the next scaling
+	requeueAfter, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	assert.Equal(t, getRequeueInterval(scaledJob), requeueAfter)
+
+	// the bucket is empty
+	requeueAfter, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	assert.Equal(t, getRequeueInterval(scaledJob), requeueAfter)
+
+	// a changed limit replaces the bucket
+	burst = int32(3)
+	scaledJob.Spec.CreationRateLimit = &kedav1alpha1.CreationRateLimit{JobsPerMinute: 1, Burst: &burst}
+	requeueAfter, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 3, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(createdJobs))
+	assert.Equal(t, time.Duration(0), requeueAfter)
+
+	// no limit
+	scaledJob.Spec.CreationRateLimit = nil
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(createdJobs))
+}
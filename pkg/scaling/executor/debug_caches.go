@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// JobScaleCaches holds the in-memory state the executor keeps for a ScaledJob between its scalings,
+// the state is lost on restart and isn't shared between KEDA instances
+type JobScaleCaches struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// ScaleState is the last scaling decision
+	ScaleState *JobScaleState `json:"scaleState,omitempty"`
+	// BackpressureCount is the number of consecutive failed or slow scalings
+	BackpressureCount int `json:"backpressureCount,omitempty"`
+	// BackpressureSeconds is the delay added to the pollingInterval because of them
+	BackpressureSeconds float64 `json:"backpressureSeconds,omitempty"`
+	// ConsecutiveSlowScalings is the number of the consecutive scalings exceeding the budget of the executor
+	ConsecutiveSlowScalings int `json:"consecutiveSlowScalings,omitempty"`
+	// ConsecutiveFailedCreations is the number of the Job creations failed since the last successful one
+	ConsecutiveFailedCreations int `json:"consecutiveFailedCreations,omitempty"`
+	// DeferredJobCount is the number of the Jobs the last scaling deferred to the next one
+	DeferredJobCount int64 `json:"deferredJobCount,omitempty"`
+	// CreationRateLimit is the rate the token bucket limiting the creation of the Jobs was created for
+	CreationRateLimit *kedav1alpha1.CreationRateLimit `json:"creationRateLimit,omitempty"`
+	// ScaleDownPeak is the peak scaleTo kept within the scale down stabilization window
+	ScaleDownPeak *int64 `json:"scaleDownPeak,omitempty"`
+	// ScaleDownLowSince is the time scaleTo dropped below the peak
+	ScaleDownLowSince *time.Time `json:"scaleDownLowSince,omitempty"`
+	// JobNamespaceCursor is the index of the jobNamespaces entry the next Job is created in
+	JobNamespaceCursor *int `json:"jobNamespaceCursor,omitempty"`
+}
+
+// getJobScaleCaches returns the in-memory state of every ScaledJob known to any of the caches, sorted by namespace and name
+func getJobScaleCaches() []JobScaleCaches {
+	caches := map[string]*JobScaleCaches{}
+	get := func(key interface{}) *JobScaleCaches {
+		k := key.(string)
+		if c, ok := caches[k]; ok {
+			return c
+		}
+		c := &JobScaleCaches{}
+		if parts := strings.SplitN(k, "/", 2); len(parts) == 2 {
+			c.Namespace, c.Name = parts[0], parts[1]
+		}
+		caches[k] = c
+		return c
+	}
+
+	scaleStates.states.Range(func(key, value interface{}) bool {
+		state := value.(JobScaleState)
+		get(key).ScaleState = &state
+		return true
+	})
+	backpressure.counts.Range(func(key, value interface{}) bool {
+		c := get(key)
+		c.BackpressureCount = value.(int)
+		c.BackpressureSeconds = getBackpressureDelay(c.BackpressureCount).Seconds()
+		return true
+	})
+	health.states.Range(func(key, value interface{}) bool {
+		state := value.(jobScaleHealthState)
+		c := get(key)
+		c.ConsecutiveSlowScalings = state.slowRequests
+		c.ConsecutiveFailedCreations = state.failedCreations
+		return true
+	})
+	deferredJobs.counts.Range(func(key, value interface{}) bool {
+		get(key).DeferredJobCount = value.(int64)
+		return true
+	})
+	rateLimiters.limiters.Range(func(key, value interface{}) bool {
+		get(key).CreationRateLimit = value.(*creationRateLimiter).spec.DeepCopy()
+		return true
+	})
+	scaleDowns.states.Range(func(key, value interface{}) bool {
+		state := value.(scaleDownStabilization)
+		c := get(key)
+		c.ScaleDownPeak = &state.peak
+		if !state.lowSince.IsZero() {
+			c.ScaleDownLowSince = &state.lowSince
+		}
+		return true
+	})
+	jobNamespaces.cursors.Range(func(key, value interface{}) bool {
+		cursor := value.(int)
+		get(key).JobNamespaceCursor = &cursor
+		return true
+	})
+
+	result := make([]JobScaleCaches, 0, len(caches))
+	for _, c := range caches {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return jobScaleStateKey(result[i].Namespace, result[i].Name) < jobScaleStateKey(result[j].Namespace, result[j].Name)
+	})
+	return result
+}
+
+// JobScaleCachesHandler serves the JobScaleCaches of all ScaledJobs as JSON, for support. The optional "namespace"
+// and "name" query parameters select the ScaledJobs of a namespace or a single ScaledJob
+func JobScaleCachesHandler(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	caches := []JobScaleCaches{}
+	for _, c := range getJobScaleCaches() {
+		if (namespace == "" || c.Namespace == namespace) && (name == "" || c.Name == name) {
+			caches = append(caches, c)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(caches); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
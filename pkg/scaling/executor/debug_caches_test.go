@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestJobScaleCachesHandler(t *testing.T) {
+	now := time.Date(2020, 7, 29, 15, 37, 0, 0, time.UTC)
+	scaleStates.record(JobScaleState{Namespace: "caches", Name: "consumer", IsActive: true, ScaleTo: 12, MaxScale: 10, ScaledAt: now})
+	defer scaleStates.remove("caches", "consumer")
+	backpressure.record("caches", "consumer", true, 0)
+	backpressure.record("caches", "consumer", true, 0)
+	defer backpressure.remove("caches", "consumer")
+	health.record("caches", "consumer", time.Second, 0, 4)
+	defer health.remove("caches", "consumer")
+	deferredJobs.record("caches", "consumer", 3)
+	defer deferredJobs.remove("caches", "consumer")
+	scaleDowns.stabilize("caches", "consumer", 8, time.Minute, now)
+	scaleDowns.stabilize("caches", "consumer", 2, time.Minute, now)
+	defer scaleDowns.remove("caches", "consumer")
+
+	scaledJob := getMockScaledJobWithJobNamespaces()
+	scaledJob.Namespace = "caches"
+	scaledJob.Spec.CreationRateLimit = &kedav1alpha1.CreationRateLimit{JobsPerMinute: 30}
+	rateLimiters.get(scaledJob)
+	defer rateLimiters.remove(scaledJob.Namespace, scaledJob.Name)
+	jobNamespaces.next(scaledJob, scaledJob.Spec.JobNamespaces)
+	defer jobNamespaces.remove(scaledJob.Namespace, scaledJob.Name)
+
+	recorder := httptest.NewRecorder()
+	JobScaleCachesHandler(recorder, httptest.NewRequest(http.MethodGet, "/debug/caches?namespace=caches", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var caches []JobScaleCaches
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &caches))
+	assert.Equal(t, 2, len(caches))
+
+	// sorted by namespace and name
+	limited := caches[0]
+	assert.Equal(t, "caches", limited.Namespace)
+	assert.Equal(t, "azure-storage-queue-consumer", limited.Name)
+	assert.Nil(t, limited.ScaleState)
+	assert.Equal(t, int32(30), limited.CreationRateLimit.JobsPerMinute)
+	assert.Equal(t, 1, *limited.JobNamespaceCursor)
+
+	consumer := caches[1]
+	assert.Equal(t, "caches", consumer.Namespace)
+	assert.Equal(t, "consumer", consumer.Name)
+	assert.Equal(t, int64(12), consumer.ScaleState.ScaleTo)
+	assert.Equal(t, 2, consumer.BackpressureCount)
+	assert.Equal(t, float64(20), consumer.BackpressureSeconds)
+	assert.Equal(t, 4, consumer.ConsecutiveFailedCreations)
+	assert.Equal(t, int64(3), consumer.DeferredJobCount)
+	assert.Equal(t, int64(8), *consumer.ScaleDownPeak)
+	assert.True(t, now.Equal(*consumer.ScaleDownLowSince))
+	assert.Nil(t, consumer.CreationRateLimit)
+	assert.Nil(t, consumer.JobNamespaceCursor)
+
+	// the caches of a single scaled job
+	recorder = httptest.NewRecorder()
+	JobScaleCachesHandler(recorder, httptest.NewRequest(http.MethodGet, "/debug/caches?namespace=caches&name=consumer", nil))
+	caches = nil
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &caches))
+	assert.Equal(t, 1, len(caches))
+	assert.Equal(t, "consumer", caches[0].Name)
+}
+
+func TestJobScaleCachesHandlerEmpty(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	JobScaleCachesHandler(recorder, httptest.NewRequest(http.MethodGet, "/debug/caches?namespace=unknown", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "[]\n", recorder.Body.String())
+}
@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// DebugSnapshotAnnotation set to "true" on a ScaledJob requests a snapshot of its Jobs, eg. for a support bundle.
+	// The snapshot is written to the "<scaledjob>-debug-snapshot" ConfigMap and the annotation is removed
+	DebugSnapshotAnnotation = "keda.sh/debug-snapshot"
+
+	debugSnapshotJobsKey      = "jobs.json"
+	debugSnapshotCreatedAtKey = "createdAt"
+)
+
+// debugSnapshotJob is the state of a Job in the debug snapshot
+type debugSnapshotJob struct {
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	AgeSeconds int64  `json:"ageSeconds"`
+}
+
+// writeDebugSnapshot writes the names, states and ages of the Jobs of the ScaledJob to its debug snapshot ConfigMap,
+// an existing snapshot is replaced. The DebugSnapshotAnnotation is removed once the snapshot is written
+func (e *scaleExecutor) writeDebugSnapshot(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, now time.Time) error {
+	jobs, err := e.listJobs(ctx, scaledJob)
+	if err != nil {
+		return err
+	}
+	snapshot, err := json.Marshal(e.getDebugSnapshotJobs(jobs.Items, now))
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getDebugSnapshotName(scaledJob),
+			Namespace: scaledJob.GetNamespace(),
+			Labels: map[string]string{
+				"scaledjob": GetScaledJobLabelValue(scaledJob),
+			},
+		},
+		Data: map[string]string{
+			debugSnapshotJobsKey:      string(snapshot),
+			debugSnapshotCreatedAtKey: now.UTC().Format(time.RFC3339),
+		},
+	}
+	if err := controllerutil.SetControllerReference(scaledJob, configMap, e.reconcilerScheme); err != nil {
+		logger.Error(err, "Failed to set ScaledJob as the owner of the debug snapshot ConfigMap")
+	}
+	err = e.client.Create(ctx, configMap)
+	if errors.IsAlreadyExists(err) {
+		err = e.client.Update(ctx, configMap)
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("Wrote the debug snapshot of the jobs", "configMap.Name", configMap.GetName(), "Number of jobs", len(jobs.Items))
+
+	patch := client.MergeFrom(scaledJob.DeepCopy())
+	delete(scaledJob.Annotations, DebugSnapshotAnnotation)
+	return e.client.Patch(ctx, scaledJob, patch)
+}
+
+// getDebugSnapshotJobs returns the state of the jobs for the debug snapshot
+func (e *scaleExecutor) getDebugSnapshotJobs(jobs []batchv1.Job, now time.Time) []debugSnapshotJob {
+	snapshotJobs := make([]debugSnapshotJob, 0, len(jobs))
+	for i := range jobs {
+		job := &jobs[i]
+		state := "Running"
+		switch e.getFinishedJobConditionType(job) {
+		case batchv1.JobComplete:
+			state = "Succeeded"
+		case batchv1.JobFailed:
+			state = "Failed"
+		default:
+			if isJobSuspended(job) {
+				state = "Suspended"
+			}
+		}
+		snapshotJobs = append(snapshotJobs, debugSnapshotJob{
+			Name:       job.GetName(),
+			State:      state,
+			AgeSeconds: int64(now.Sub(job.CreationTimestamp.Time).Seconds()),
+		})
+	}
+	return snapshotJobs
+}
+
+// getDebugSnapshotName returns the name of the ConfigMap with the debug snapshot of the ScaledJob
+func getDebugSnapshotName(scaledJob *kedav1alpha1.ScaledJob) string {
+	return fmt.Sprintf("%s-debug-snapshot", scaledJob.GetName())
+}
@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestWriteDebugSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Annotations = map[string]string{DebugSnapshotAnnotation: "true", "team": "orders"}
+
+	now := time.Now()
+	jobs := []batchv1.Job{
+		*getJob(t, "succeeded", "2020-07-29T15:31:00Z", batchv1.JobComplete),
+		*getJob(t, "failed", "2020-07-29T15:32:00Z", batchv1.JobFailed),
+		getRunningJob("running", now, nil),
+		getSuspendedJob("suspended", now),
+	}
+	for i := range jobs {
+		jobs[i].CreationTimestamp = metav1.NewTime(now.Add(-time.Duration(i+1) * time.Minute))
+	}
+
+	var configMaps []*corev1.ConfigMap
+	var patchedScaledJobs []*kedav1alpha1.ScaledJob
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = jobs
+	}).
+		Return(nil).Times(2)
+	client.EXPECT().
+		Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.CreateOption) {
+		configMaps = append(configMaps, obj.(*corev1.ConfigMap))
+	}).
+		Return(nil)
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchedScaledJobs = append(patchedScaledJobs, obj.(*kedav1alpha1.ScaledJob))
+	}).
+		Return(nil).Times(2)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.writeDebugSnapshot(context.TODO(), scaleExecutor.logger, scaledJob, now)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(configMaps))
+	configMap := configMaps[0]
+	assert.Equal(t, "azure-storage-queue-consumer-debug-snapshot", configMap.Name)
+	assert.Equal(t, scaledJob.Name, configMap.Labels["scaledjob"])
+	assert.Equal(t, now.UTC().Format(time.RFC3339), configMap.Data[debugSnapshotCreatedAtKey])
+	var snapshot []debugSnapshotJob
+	assert.Nil(t, json.Unmarshal([]byte(configMap.Data[debugSnapshotJobsKey]), &snapshot))
+	assert.Equal(t, []debugSnapshotJob{
+		{Name: "succeeded", State: "Succeeded", AgeSeconds: 60},
+		{Name: "failed", State: "Failed", AgeSeconds: 120},
+		{Name: "running", State: "Running", AgeSeconds: 180},
+		{Name: "suspended", State: "Suspended", AgeSeconds: 240},
+	}, snapshot)
+
+	// the annotation is removed, the snapshot is taken once per request
+	assert.Equal(t, 1, len(patchedScaledJobs))
+	_, ok := patchedScaledJobs[0].Annotations[DebugSnapshotAnnotation]
+	assert.False(t, ok)
+	assert.Equal(t, "orders", patchedScaledJobs[0].Annotations["team"])
+
+	// an existing snapshot is replaced
+	client.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(errors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, configMap.Name))
+	client.EXPECT().
+		Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.UpdateOption) {
+		configMaps = append(configMaps, obj.(*corev1.ConfigMap))
+	}).
+		Return(nil)
+
+	err = scaleExecutor.writeDebugSnapshot(context.TODO(), scaleExecutor.logger, scaledJob, now)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(configMaps))
+}
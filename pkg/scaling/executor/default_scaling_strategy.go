@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// DefaultScalingStrategyAnnotation on a Namespace holds the ScalingStrategy of the ScaledJobs in the Namespace
+	// that don't define one, in JSON, eg. {"strategy": "percentage", "percentage": 50}
+	DefaultScalingStrategyAnnotation = "keda.sh/default-scaling-strategy"
+)
+
+// ParseScalingStrategy parses the JSON representation of a default ScalingStrategy and validates it, the defaults
+// don't go through the validation of the ScaledJobs
+func ParseScalingStrategy(value string) (*kedav1alpha1.ScalingStrategy, error) {
+	strategy := &kedav1alpha1.ScalingStrategy{}
+	if err := json.Unmarshal([]byte(value), strategy); err != nil {
+		return nil, err
+	}
+	if err := ValidateScalingStrategy(strategy); err != nil {
+		return nil, err
+	}
+	if len(strategy.TriggerWeights) > 0 {
+		return nil, fmt.Errorf("triggerWeights refer to the triggers of a ScaledJob, they can't be set in a default scaling strategy")
+	}
+	return strategy, nil
+}
+
+// getDefaultScalingStrategy returns the ScalingStrategy of a ScaledJob that doesn't define one, the default of the
+// Namespace takes precedence over the operator-level default, nil is returned if neither is set.
+// The defaults are validated against the other fields of the ScaledJob, as its own strategy is by the controller,
+// a default conflicting with the ScaledJob is ignored
+func (e *scaleExecutor) getDefaultScalingStrategy(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) *kedav1alpha1.ScalingStrategy {
+	ns := &corev1.Namespace{}
+	err := e.client.Get(ctx, types.NamespacedName{Name: scaledJob.Namespace}, ns)
+	if err != nil {
+		logger.V(1).Info("Failed to get the Namespace, using the operator-level default scaling strategy", "error", err.Error())
+	} else if value, ok := ns.Annotations[DefaultScalingStrategyAnnotation]; ok {
+		strategy, err := ParseScalingStrategy(value)
+		if err == nil {
+			err = ValidateScalingStrategyForScaledJob(scaledJob, strategy)
+		}
+		if err == nil {
+			return strategy
+		}
+		logger.Error(err, "Invalid default scaling strategy of the Namespace, using the operator-level default", "annotation", DefaultScalingStrategyAnnotation)
+	}
+
+	if e.config.DefaultScalingStrategy == nil {
+		return nil
+	}
+	if err := ValidateScalingStrategyForScaledJob(scaledJob, e.config.DefaultScalingStrategy); err != nil {
+		logger.Error(err, "Invalid operator-level default scaling strategy, using the default strategy")
+		return nil
+	}
+	return e.config.DefaultScalingStrategy.DeepCopy()
+}
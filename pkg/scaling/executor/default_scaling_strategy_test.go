@@ -0,0 +1,159 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestRequestJobScaleWithDefaultScalingStrategy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	// the operator-level default is used
+	var createdJobs []*batchv1.Job
+	client := getMockClientForDefaultScalingStrategy(ctrl, "")
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.DefaultScalingStrategy = &kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.PercentageScalingStrategy, Percentage: int32Ptr(50)}
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 100, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(createdJobs))
+	assert.Nil(t, scaledJob.Spec.ScalingStrategy)
+
+	// the default of the Namespace takes precedence
+	createdJobs = nil
+	client = getMockClientForDefaultScalingStrategy(ctrl, `{"strategy": "percentage", "percentage": 20}`)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor = getMockScaleExecutor(client)
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 100, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+
+	// an invalid default of the Namespace is ignored
+	createdJobs = nil
+	client = getMockClientForDefaultScalingStrategy(ctrl, `percentage`)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor = getMockScaleExecutor(client)
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 100, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(createdJobs))
+
+	// a default of the Namespace failing the validation is ignored, it would create no Jobs
+	createdJobs = nil
+	client = getMockClientForDefaultScalingStrategy(ctrl, `{"strategy": "percentage", "percentage": 0}`)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor = getMockScaleExecutor(client)
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 100, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(createdJobs))
+
+	// the strategy of the ScaledJob takes precedence over the defaults
+	createdJobs = nil
+	scaledJob.Spec.ScalingStrategy = &kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.DefaultScalingStrategy}
+	client = getMockClientForDefaultScalingStrategy(ctrl, `{"strategy": "percentage", "percentage": 20}`)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor = getMockScaleExecutor(client)
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 100, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(createdJobs))
+}
+
+func TestRequestJobScaleWithConflictingDefaultScalingStrategy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.ReplaceFailedJobs = true
+
+	// the default of the Namespace can't be combined with replaceFailedJobs, the operator-level default is used
+	var createdJobs []*batchv1.Job
+	client := getMockClientForDefaultScalingStrategy(ctrl, `{"completionsDivisor": 2}`)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.DefaultScalingStrategy = &kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.PercentageScalingStrategy, Percentage: int32Ptr(50)}
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 100, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(createdJobs))
+	assert.Nil(t, createdJobs[0].Spec.Completions)
+
+	// neither can the operator-level default, the default strategy is used
+	createdJobs = nil
+	client = getMockClientForDefaultScalingStrategy(ctrl, "")
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor = getMockScaleExecutor(client)
+	scaleExecutor.config.DefaultScalingStrategy = &kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.PercentageScalingStrategy, Percentage: int32Ptr(50), CompletionsDivisor: int32Ptr(2)}
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 100, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(createdJobs))
+	assert.Nil(t, createdJobs[0].Spec.Completions)
+}
+
+func TestValidateScalingStrategyForScaledJob(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	strategy := &kedav1alpha1.ScalingStrategy{CompletionsDivisor: int32Ptr(2)}
+	assert.NoError(t, ValidateScalingStrategyForScaledJob(scaledJob, strategy))
+	assert.NoError(t, ValidateScalingStrategyForScaledJob(scaledJob, nil))
+
+	scaledJob.Spec.ReplaceFailedJobs = true
+	assert.Error(t, ValidateScalingStrategyForScaledJob(scaledJob, strategy))
+	assert.NoError(t, ValidateScalingStrategyForScaledJob(scaledJob, nil))
+}
+
+func TestParseScalingStrategy(t *testing.T) {
+	strategy, err := ParseScalingStrategy(`{"strategy": "percentage", "percentage": 50}`)
+	assert.NoError(t, err)
+	assert.Equal(t, kedav1alpha1.PercentageScalingStrategy, strategy.Strategy)
+	assert.Equal(t, int32(50), *strategy.Percentage)
+
+	_, err = ParseScalingStrategy(`percentage`)
+	assert.Error(t, err)
+
+	// the defaults are validated like the strategies of the ScaledJobs
+	_, err = ParseScalingStrategy(`{"strategy": "percentage", "percentage": 0}`)
+	assert.Error(t, err)
+	_, err = ParseScalingStrategy(`{"strategy": "unknown"}`)
+	assert.Error(t, err)
+	_, err = ParseScalingStrategy(`{"triggerWeights": [{"trigger": "priority", "weight": 200}]}`)
+	assert.Error(t, err)
+}
+
+// getMockClientForDefaultScalingStrategy returns a client without any Jobs, the Namespace
+// is annotated with the default scaling strategy unless it is empty
+func getMockClientForDefaultScalingStrategy(ctrl *gomock.Controller, namespaceDefault string) *mock_client.MockClient {
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ runtimeclient.ObjectKey, obj runtime.Object) {
+		if ns, ok := obj.(*corev1.Namespace); ok && namespaceDefault != "" {
+			ns.Annotations = map[string]string{DefaultScalingStrategyAnnotation: namespaceDefault}
+		}
+	}).
+		Return(nil).AnyTimes()
+
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	return client
+}
@@ -0,0 +1,51 @@
+package executor
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// deletionBudget is the number of the Jobs a single cleanup of a ScaledJob may still delete, so a large backlog
+// of finished Jobs is deleted over several cleanups instead of flooding the API server. A nil budget is unlimited
+type deletionBudget struct {
+	remaining         int64
+	deferredCompleted int
+	deferredFailed    int
+}
+
+// newDeletionBudget returns the budget of a cleanup of the ScaledJob from its maxDeletesPerReconcile,
+// nil if it is not set
+func newDeletionBudget(scaledJob *kedav1alpha1.ScaledJob) *deletionBudget {
+	max := scaledJob.Spec.MaxDeletesPerReconcile
+	if max == nil || *max <= 0 {
+		return nil
+	}
+	return &deletionBudget{remaining: int64(*max)}
+}
+
+// take returns true if one more Job can be deleted, the Jobs that can't are counted as deferred by their condition
+func (b *deletionBudget) take(conditionType batchv1.JobConditionType) bool {
+	if b == nil {
+		return true
+	}
+	if b.remaining <= 0 {
+		if conditionType == batchv1.JobFailed {
+			b.deferredFailed++
+		} else {
+			b.deferredCompleted++
+		}
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+// getDeferred returns the numbers of the completed and failed Jobs whose deletion was deferred to the next cleanup,
+// they are still retained
+func (b *deletionBudget) getDeferred() (int, int) {
+	if b == nil {
+		return 0, 0
+	}
+	return b.deferredCompleted, b.deferredFailed
+}
@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestCleanUpWithMaxDeletesPerReconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(0, 0)
+	maxDeletes := int32(3)
+	scaledJob.Spec.MaxDeletesPerReconcile = &maxDeletes
+
+	var actualDeletedJobName = make(map[string]string)
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:31:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:34:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "fail1", CompletionTime: "2020-07-29T15:32:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	// the budget is shared by the limits, the deletion of fail2 is deferred to the next cleanup
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"success1": "success1", "success2": "success2", "fail1": "fail1"}, actualDeletedJobName)
+	assert.Equal(t, &kedav1alpha1.CleanupDeletedCount{Completed: 2, Failed: 1}, scaledJob.Status.LastCleanupDeletedCount)
+	assert.Equal(t, float64(1), getHistoryRetained(t, scaledJob.Namespace, scaledJob.Name, historyCategoryFailed))
+}
+
+func TestCleanUpWithMaxDeletesPerReconcileReplacesDeletedJobsOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(2, 0)
+	scaledJob.Spec.ReplaceFailedJobs = true
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	maxDeletes := int32(1)
+	scaledJob.Spec.MaxDeletesPerReconcile = &maxDeletes
+
+	var actualDeletedJobName = make(map[string]string)
+	var createdJobs []*batchv1.Job
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "fail1", CompletionTime: "2020-07-29T15:32:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.cleanUp(scaledJob, 10, false)
+
+	// fail2 is deferred to the next cleanup, it is replaced once it is deleted
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"fail1": "fail1"}, actualDeletedJobName)
+	assert.Equal(t, 1, len(createdJobs))
+}
+
+func TestDeletionBudget(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+
+	// not set, unlimited
+	budget := newDeletionBudget(scaledJob)
+	for i := 0; i < 100; i++ {
+		assert.True(t, budget.take(batchv1.JobComplete))
+	}
+	deferredCompleted, deferredFailed := budget.getDeferred()
+	assert.Equal(t, 0, deferredCompleted)
+	assert.Equal(t, 0, deferredFailed)
+
+	maxDeletes := int32(2)
+	scaledJob.Spec.MaxDeletesPerReconcile = &maxDeletes
+	budget = newDeletionBudget(scaledJob)
+	assert.True(t, budget.take(batchv1.JobComplete))
+	assert.True(t, budget.take(batchv1.JobFailed))
+	assert.False(t, budget.take(batchv1.JobComplete))
+	assert.False(t, budget.take(batchv1.JobFailed))
+	assert.False(t, budget.take(batchv1.JobFailed))
+	deferredCompleted, deferredFailed = budget.getDeferred()
+	assert.Equal(t, 1, deferredCompleted)
+	assert.Equal(t, 2, deferredFailed)
+}
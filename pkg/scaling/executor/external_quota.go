@@ -0,0 +1,156 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// Default timeout of the request to the external quota service
+	defaultExternalQuotaTimeoutSeconds = 3
+	// maxExternalQuotaResponseBytes is the size of the response of the external quota service read at most
+	maxExternalQuotaResponseBytes = 64 * 1024
+)
+
+// ValidateExternalQuotaRef checks the URL of the external quota service is an http(s) URL on one of the allowed hosts,
+// the URLs are set by the users creating ScaledJobs, the operator must not send requests to arbitrary hosts on their behalf
+func (c Config) ValidateExternalQuotaRef(quotaRef *kedav1alpha1.ExternalQuotaRef) error {
+	if quotaRef == nil {
+		return nil
+	}
+	u, err := url.Parse(quotaRef.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url of the external quota service: %s", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("the url of the external quota service must be an http or https url, got %q", quotaRef.URL)
+	}
+	if !c.isExternalQuotaHostAllowed(u) {
+		return fmt.Errorf("the host %s of the external quota service is not allowed by the operator, allowed hosts are: %s", u.Host, strings.Join(c.ExternalQuotaAllowedHosts, ", "))
+	}
+	return nil
+}
+
+// isExternalQuotaHostAllowed returns true if the host of the URL, with or without its port, is an allowed host,
+// an allowed host without a port allows all its ports
+func (c Config) isExternalQuotaHostAllowed(u *url.URL) bool {
+	for _, host := range c.ExternalQuotaAllowedHosts {
+		host = strings.TrimSpace(host)
+		if host != "" && (strings.EqualFold(host, u.Host) || strings.EqualFold(host, u.Hostname())) {
+			return true
+		}
+	}
+	return false
+}
+
+// externalQuotaRequest is POSTed to the external quota service before Jobs are created
+type externalQuotaRequest struct {
+	Namespace string `json:"namespace"`
+	ScaledJob string `json:"scaledJob"`
+	Count     int64  `json:"count"`
+}
+
+// externalQuotaResponse is the permission of the external quota service to create up to Count Jobs
+type externalQuotaResponse struct {
+	Allowed bool  `json:"allowed"`
+	Count   int64 `json:"count"`
+}
+
+// getScaleToByExternalQuota reduces scaleTo to the number of Jobs allowed by the external quota service of the ScaledJob,
+// the Jobs within maxScale are requested. When the service fails, either no Jobs or all of them are created
+// depending on the failOpen setting
+func (e *scaleExecutor) getScaleToByExternalQuota(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, scaleTo int64, maxScale int64) int64 {
+	quotaRef := scaledJob.Spec.ExternalQuotaRef
+	if quotaRef == nil {
+		return scaleTo
+	}
+
+	requested := scaleTo
+	if requested > maxScale {
+		requested = maxScale
+	}
+	if requested <= 0 {
+		return scaleTo
+	}
+
+	allowed, err := requestExternalQuota(ctx, scaledJob, requested)
+	if err != nil {
+		if quotaRef.FailOpen {
+			logger.Error(err, "Failed to get the quota from the external quota service, creating Jobs without a quota")
+			return scaleTo
+		}
+		logger.Error(err, "Failed to get the quota from the external quota service, not creating Jobs")
+		return 0
+	}
+
+	if allowed < requested {
+		logger.Info("Number of Jobs reduced by the external quota service", "requested", requested, "allowed", allowed)
+		return allowed
+	}
+	return scaleTo
+}
+
+// requestExternalQuota asks the external quota service of the ScaledJob for the permission to create count Jobs,
+// it returns the number of Jobs allowed
+func requestExternalQuota(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, count int64) (int64, error) {
+	quotaRef := scaledJob.Spec.ExternalQuotaRef
+	if err := ValidateExternalQuotaRef(quotaRef); err != nil {
+		return 0, err
+	}
+	timeout := time.Second * defaultExternalQuotaTimeoutSeconds
+	if quotaRef.TimeoutSeconds != nil {
+		timeout = time.Second * time.Duration(*quotaRef.TimeoutSeconds)
+	}
+
+	body, err := json.Marshal(externalQuotaRequest{
+		Namespace: scaledJob.Namespace,
+		ScaledJob: scaledJob.Name,
+		Count:     count,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, quotaRef.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{
+		Timeout: timeout,
+		// a redirect could point to a host that isn't allowed
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("external quota service responded with status %d", resp.StatusCode)
+	}
+
+	response := externalQuotaResponse{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxExternalQuotaResponseBytes)).Decode(&response); err != nil {
+		return 0, fmt.Errorf("invalid response of the external quota service: %s", err)
+	}
+	if !response.Allowed || response.Count < 0 {
+		return 0, nil
+	}
+	return response.Count, nil
+}
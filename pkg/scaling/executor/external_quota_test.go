@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestGetScaleToByExternalQuota(t *testing.T) {
+	var requests []externalQuotaRequest
+	response := externalQuotaResponse{}
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := externalQuotaRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Error(err)
+		}
+		requests = append(requests, request)
+		w.WriteHeader(statusCode)
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	scaleExecutor := getMockScaleExecutor(nil)
+	scaleExecutor.config.ExternalQuotaAllowedHosts = []string{server.Listener.Addr().String()}
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.ExternalQuotaRef = &kedav1alpha1.ExternalQuotaRef{URL: server.URL}
+
+	// the jobs within maxScale are requested, the allowed count reduces scaleTo
+	response = externalQuotaResponse{Allowed: true, Count: 2}
+	scaleTo := scaleExecutor.getScaleToByExternalQuota(context.TODO(), scaleExecutor.logger, scaledJob, 8, 5)
+	assert.Equal(t, int64(2), scaleTo)
+	assert.Equal(t, externalQuotaRequest{Namespace: scaledJob.Namespace, ScaledJob: scaledJob.Name, Count: 5}, requests[0])
+
+	// all the requested jobs are allowed, scaleTo is left to maxScale
+	response = externalQuotaResponse{Allowed: true, Count: 5}
+	assert.Equal(t, int64(8), scaleExecutor.getScaleToByExternalQuota(context.TODO(), scaleExecutor.logger, scaledJob, 8, 5))
+
+	// a larger allowed count doesn't increase scaleTo
+	response = externalQuotaResponse{Allowed: true, Count: 10}
+	assert.Equal(t, int64(4), scaleExecutor.getScaleToByExternalQuota(context.TODO(), scaleExecutor.logger, scaledJob, 4, 5))
+
+	// not allowed
+	response = externalQuotaResponse{Allowed: false, Count: 10}
+	assert.Equal(t, int64(0), scaleExecutor.getScaleToByExternalQuota(context.TODO(), scaleExecutor.logger, scaledJob, 4, 5))
+
+	// the service fails, closed by default
+	statusCode = http.StatusInternalServerError
+	assert.Equal(t, int64(0), scaleExecutor.getScaleToByExternalQuota(context.TODO(), scaleExecutor.logger, scaledJob, 4, 5))
+	scaledJob.Spec.ExternalQuotaRef.FailOpen = true
+	assert.Equal(t, int64(4), scaleExecutor.getScaleToByExternalQuota(context.TODO(), scaleExecutor.logger, scaledJob, 4, 5))
+
+	// nothing is requested without jobs to create
+	requestCount := len(requests)
+	assert.Equal(t, int64(3), scaleExecutor.getScaleToByExternalQuota(context.TODO(), scaleExecutor.logger, scaledJob, 3, 0))
+	assert.Equal(t, requestCount, len(requests))
+
+	// the host isn't allowed, nothing is requested and the service is considered failed
+	scaleExecutor.config.ExternalQuotaAllowedHosts = []string{"quota.quota-system.svc"}
+	scaledJob.Spec.ExternalQuotaRef.FailOpen = false
+	statusCode = http.StatusOK
+	response = externalQuotaResponse{Allowed: true, Count: 10}
+	assert.Equal(t, int64(0), scaleExecutor.getScaleToByExternalQuota(context.TODO(), scaleExecutor.logger, scaledJob, 4, 5))
+	assert.Equal(t, requestCount, len(requests))
+}
+
+func TestValidateExternalQuotaRef(t *testing.T) {
+	config := Config{ExternalQuotaAllowedHosts: []string{"quota.quota-system.svc", "10.0.0.1:8080"}}
+
+	assert.NoError(t, config.ValidateExternalQuotaRef(nil))
+	assert.NoError(t, config.ValidateExternalQuotaRef(&kedav1alpha1.ExternalQuotaRef{URL: "http://quota.quota-system.svc/quota"}))
+	assert.NoError(t, config.ValidateExternalQuotaRef(&kedav1alpha1.ExternalQuotaRef{URL: "https://quota.quota-system.svc:8443/quota"}))
+	assert.NoError(t, config.ValidateExternalQuotaRef(&kedav1alpha1.ExternalQuotaRef{URL: "http://10.0.0.1:8080/quota"}))
+
+	assert.Error(t, config.ValidateExternalQuotaRef(&kedav1alpha1.ExternalQuotaRef{URL: "http://10.0.0.1:9090/quota"}))
+	assert.Error(t, config.ValidateExternalQuotaRef(&kedav1alpha1.ExternalQuotaRef{URL: "http://169.254.169.254/latest/meta-data"}))
+	assert.Error(t, config.ValidateExternalQuotaRef(&kedav1alpha1.ExternalQuotaRef{URL: "file:///etc/passwd"}))
+
+	// no host is allowed by default
+	assert.Error(t, DefaultConfig().ValidateExternalQuotaRef(&kedav1alpha1.ExternalQuotaRef{URL: "http://quota.quota-system.svc/quota"}))
+}
+
+func TestGetScaleToByExternalQuotaTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the response never comes before the timeout
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	scaleExecutor := getMockScaleExecutor(nil)
+	scaleExecutor.config.ExternalQuotaAllowedHosts = []string{server.Listener.Addr().String()}
+	scaledJob := getMockScaledJobWithDefault()
+	timeoutSeconds := int32(1)
+	scaledJob.Spec.ExternalQuotaRef = &kedav1alpha1.ExternalQuotaRef{URL: server.URL, TimeoutSeconds: &timeoutSeconds}
+
+	start := time.Now()
+	scaleTo := scaleExecutor.getScaleToByExternalQuota(context.TODO(), scaleExecutor.logger, scaledJob, 4, 5)
+	assert.Equal(t, int64(0), scaleTo)
+	assert.Less(t, int64(time.Since(start)), int64(3*time.Second))
+}
+
+func TestRequestJobScaleWithExternalQuota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(externalQuotaResponse{Allowed: true, Count: 3})
+	}))
+	defer server.Close()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.ExternalQuotaRef = &kedav1alpha1.ExternalQuotaRef{URL: server.URL}
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForDefaultScalingStrategy(ctrl, "")
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.ExternalQuotaAllowedHosts = []string{server.Listener.Addr().String()}
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(createdJobs))
+}
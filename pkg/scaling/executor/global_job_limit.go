@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getGlobalJobBudget returns the number of Jobs that can be created within the operator-level limit,
+// false is returned if the limit is disabled.
+// If the running Jobs can't be counted, no Jobs can be created.
+func (e *scaleExecutor) getGlobalJobBudget(ctx context.Context, logger logr.Logger) (int64, bool) {
+	max := e.config.MaxTotalJobs
+	if max <= 0 {
+		return 0, false
+	}
+
+	// the Jobs of all ScaledJobs carry the scaledjob label
+	requirement, err := labels.NewRequirement("scaledjob", selection.Exists, nil)
+	if err != nil {
+		logger.Error(err, "Failed to select the Jobs of all ScaledJobs")
+		return 0, true
+	}
+	selector := labels.NewSelector().Add(*requirement)
+	if e.config.OperatorID != "" {
+		requirement, err = labels.NewRequirement(OperatorIDLabel, selection.Equals, []string{e.config.OperatorID})
+		if err != nil {
+			logger.Error(err, "Failed to select the Jobs of all ScaledJobs")
+			return 0, true
+		}
+		selector = selector.Add(*requirement)
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := e.client.List(ctx, jobs, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		logger.Error(err, "Failed to count the running Jobs of all ScaledJobs, not creating Jobs")
+		return 0, true
+	}
+
+	total := e.countRunningJobs(jobs.Items)
+	logger.V(1).Info("Jobs running for all ScaledJobs", "Number of running Jobs", total, "limit", max)
+	if total >= max {
+		return 0, true
+	}
+	return max - total, true
+}
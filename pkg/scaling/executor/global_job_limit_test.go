@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestRequestJobScaleWithGlobalJobLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJobA := getMockScaledJobWithDefault()
+	scaledJobA.Name = "consumer-a"
+	scaledJobA.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJobB := getMockScaledJobWithDefault()
+	scaledJobB.Name = "consumer-b"
+	scaledJobB.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	// 2 jobs of consumer-a and 1 job of consumer-b are running
+	runningJobs := []batchv1.Job{
+		getRunningJob("consumer-a-1", time.Now(), nil),
+		getRunningJob("consumer-a-2", time.Now(), nil),
+		getRunningJob("consumer-b-1", time.Now(), nil),
+	}
+	runningJobs[0].Labels = map[string]string{"scaledjob": "consumer-a"}
+	runningJobs[1].Labels = map[string]string{"scaledjob": "consumer-a"}
+	runningJobs[2].Labels = map[string]string{"scaledjob": "consumer-b"}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, opts ...runtimeclient.ListOption) {
+		listOpts := &runtimeclient.ListOptions{}
+		listOpts.ApplyOptions(opts)
+		// the fake API server applies the label selector
+		j := list.(*batchv1.JobList)
+		for _, job := range runningJobs {
+			if listOpts.LabelSelector.Matches(labels.Set(job.Labels)) {
+				j.Items = append(j.Items, job)
+			}
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	scaleExecutor.config.MaxTotalJobs = 3
+
+	// the limit is reached, no jobs are created for either ScaledJob
+	for _, scaledJob := range []*kedav1alpha1.ScaledJob{scaledJobA, scaledJobB} {
+		_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+		assert.Nil(t, err)
+		condition := scaledJob.Status.Conditions.GetGlobalJobLimitReachedCondition()
+		assert.True(t, condition.IsTrue())
+	}
+	assert.Equal(t, 0, len(createdJobs))
+
+	// the jobs created are capped by the limit
+	scaleExecutor.config.MaxTotalJobs = 5
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJobB, true, 5, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	condition := scaledJobB.Status.Conditions.GetGlobalJobLimitReachedCondition()
+	assert.True(t, condition.IsFalse())
+}
+
+func TestGetGlobalJobBudgetDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no jobs are listed while the limit is disabled
+	scaleExecutor := getMockScaleExecutor(mock_client.NewMockClient(ctrl))
+
+	_, limited := scaleExecutor.getGlobalJobBudget(context.TODO(), scaleExecutor.logger)
+	assert.False(t, limited)
+}
+
+func TestSetGlobalJobLimitReachedConditionPatchedOnChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	client := mock_client.NewMockClient(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	// reached, the limit raised, back within the limit
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(3)
+	client.EXPECT().Status().Return(statusWriter).Times(3)
+	scaleExecutor := getMockScaleExecutor(client)
+	setReached := func(reached bool) {
+		original := scaledJob.DeepCopy()
+		scaleExecutor.setGlobalJobLimitReachedCondition(scaledJob, reached)
+		assert.Nil(t, scaleExecutor.patchScaledJobStatus(context.TODO(), scaleExecutor.logger, scaledJob, original))
+	}
+
+	scaleExecutor.config.MaxTotalJobs = 3
+
+	// the status isn't patched again while the limit stays reached
+	setReached(true)
+	setReached(true)
+	assert.Equal(t, "Jobs running for all ScaledJobs reach the limit of 3", scaledJob.Status.Conditions.GetGlobalJobLimitReachedCondition().Message)
+
+	// the message follows the limit
+	scaleExecutor.config.MaxTotalJobs = 5
+	setReached(true)
+	assert.Equal(t, "Jobs running for all ScaledJobs reach the limit of 5", scaledJob.Status.Conditions.GetGlobalJobLimitReachedCondition().Message)
+
+	setReached(false)
+	condition := scaledJob.Status.Conditions.GetGlobalJobLimitReachedCondition()
+	assert.True(t, condition.IsFalse())
+}
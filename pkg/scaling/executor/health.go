@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// Duration a RequestJobScale call is expected to complete within
+	defaultRequestJobScaleBudget = 30 * time.Second
+	// Number of consecutive RequestJobScale calls exceeding the budget, after which a ScaledJob falls behind
+	defaultMaxSlowRequests = 3
+	// Number of consecutive failed Job creations, after which a ScaledJob falls behind
+	defaultMaxFailedCreations = 10
+)
+
+// jobScaleHealthState holds the consecutive RequestJobScale calls of a ScaledJob exceeding the budget
+// and the consecutive failed creations of its Jobs
+type jobScaleHealthState struct {
+	slowRequests    int
+	failedCreations int
+}
+
+// fallsBehind returns true if the scalings of the ScaledJob keep exceeding the budget or the creations of its Jobs keep failing
+func (s jobScaleHealthState) fallsBehind() bool {
+	return s.slowRequests >= defaultMaxSlowRequests || s.failedCreations >= defaultMaxFailedCreations
+}
+
+// jobScaleHealth tracks whether the executor keeps up with the RequestJobScale calls of every ScaledJob
+// by its namespace and name, the counts are exposed as metrics of the ScaledJob
+type jobScaleHealth struct {
+	states sync.Map
+}
+
+var (
+	health                  = &jobScaleHealth{}
+	consecutiveSlowScalings = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "consecutive_slow_scalings",
+			Help:      "Number of the consecutive scalings of the ScaledJob exceeding the budget of the executor",
+		},
+		scaledJobLabels,
+	)
+	consecutiveFailedCreations = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "consecutive_failed_creations",
+			Help:      "Number of the Job creations of the ScaledJob that failed since the last successful one",
+		},
+		scaledJobLabels,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(consecutiveSlowScalings)
+	metrics.Registry.MustRegister(consecutiveFailedCreations)
+}
+
+func jobScaleStateKey(namespace string, name string) string {
+	return namespace + "/" + name
+}
+
+// record records the duration of a RequestJobScale call of the ScaledJob and the number of Job creations that
+// succeeded and failed in it, any successful creation resets the count of failed creations
+func (h *jobScaleHealth) record(namespace string, name string, duration time.Duration, createdJobs int, failedJobs int) {
+	key := jobScaleStateKey(namespace, name)
+	state := jobScaleHealthState{}
+	if existing, ok := h.states.Load(key); ok {
+		state = existing.(jobScaleHealthState)
+	}
+
+	if duration > defaultRequestJobScaleBudget {
+		state.slowRequests++
+	} else {
+		state.slowRequests = 0
+	}
+
+	if createdJobs > 0 {
+		state.failedCreations = 0
+	}
+	state.failedCreations += failedJobs
+
+	h.states.Store(key, state)
+	consecutiveSlowScalings.WithLabelValues(namespace, name).Set(float64(state.slowRequests))
+	consecutiveFailedCreations.WithLabelValues(namespace, name).Set(float64(state.failedCreations))
+}
+
+func (h *jobScaleHealth) get(namespace string, name string) jobScaleHealthState {
+	state, ok := h.states.Load(jobScaleStateKey(namespace, name))
+	if !ok {
+		return jobScaleHealthState{}
+	}
+	return state.(jobScaleHealthState)
+}
+
+// check returns an error if every ScaledJob scaled by the executor falls behind, this points at the executor or
+// the cluster rather than at a ScaledJob, a single failing ScaledJob is reported by its metrics instead
+func (h *jobScaleHealth) check() error {
+	var total, behind int
+	var last jobScaleHealthState
+	h.states.Range(func(_, value interface{}) bool {
+		state := value.(jobScaleHealthState)
+		total++
+		if state.fallsBehind() {
+			behind++
+			last = state
+		}
+		return true
+	})
+
+	if total == 0 || behind < total {
+		return nil
+	}
+	return fmt.Errorf("all %d ScaledJobs fall behind, eg. %d consecutive scalings exceeded the budget of %s and %d consecutive job creations failed",
+		total, last.slowRequests, defaultRequestJobScaleBudget, last.failedCreations)
+}
+
+// JobScaleHealthCheck is a health checker reporting whether the ScaledJob executor keeps up,
+// it fails when the scalings of every ScaledJob take too long or the creations of their Jobs keep failing
+func JobScaleHealthCheck(_ *http.Request) error {
+	return health.check()
+}
+
+func (h *jobScaleHealth) remove(namespace string, name string) {
+	h.states.Delete(jobScaleStateKey(namespace, name))
+	consecutiveSlowScalings.DeleteLabelValues(namespace, name)
+	consecutiveFailedCreations.DeleteLabelValues(namespace, name)
+}
@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobScaleHealthSlowRequests(t *testing.T) {
+	defer health.remove("health", "slow")
+	assert.Equal(t, 0, health.get("health", "slow").slowRequests)
+
+	health.record("health", "slow", time.Minute, 1, 0)
+	health.record("health", "slow", time.Minute, 1, 0)
+	assert.Equal(t, 2, health.get("health", "slow").slowRequests)
+	assert.Equal(t, float64(2), testutil.ToFloat64(consecutiveSlowScalings.WithLabelValues("health", "slow")))
+
+	// a request within the budget resets the count
+	health.record("health", "slow", time.Second, 1, 0)
+	assert.Equal(t, 0, health.get("health", "slow").slowRequests)
+	assert.Equal(t, float64(0), testutil.ToFloat64(consecutiveSlowScalings.WithLabelValues("health", "slow")))
+}
+
+func TestJobScaleHealthFailedCreations(t *testing.T) {
+	defer health.remove("health", "failing")
+	defer health.remove("health", "other")
+
+	health.record("health", "failing", time.Second, 0, 3)
+	health.record("health", "failing", time.Second, 0, 1)
+	assert.Equal(t, 4, health.get("health", "failing").failedCreations)
+	assert.Equal(t, float64(4), testutil.ToFloat64(consecutiveFailedCreations.WithLabelValues("health", "failing")))
+
+	// the counts are kept per ScaledJob
+	health.record("health", "other", time.Second, 1, 0)
+	assert.Equal(t, 0, health.get("health", "other").failedCreations)
+	assert.Equal(t, 4, health.get("health", "failing").failedCreations)
+
+	// a successful creation resets the count, failures in the same request are still counted
+	health.record("health", "failing", time.Second, 1, 2)
+	assert.Equal(t, 2, health.get("health", "failing").failedCreations)
+
+	health.remove("health", "failing")
+	assert.Equal(t, jobScaleHealthState{}, health.get("health", "failing"))
+}
+
+func TestJobScaleHealthCheck(t *testing.T) {
+	h := &jobScaleHealth{}
+	defer h.remove("health", "failing")
+	defer h.remove("health", "healthy")
+	assert.Nil(t, h.check())
+
+	// a single ScaledJob falling behind doesn't fail the check while the others keep up
+	h.record("health", "failing", time.Second, 0, defaultMaxFailedCreations)
+	h.record("health", "healthy", time.Second, 1, 0)
+	assert.Nil(t, h.check())
+
+	// every ScaledJob falls behind
+	for i := 0; i < defaultMaxSlowRequests; i++ {
+		h.record("health", "healthy", time.Minute, 1, 0)
+	}
+	assert.NotNil(t, h.check())
+
+	// the ScaledJob keeps up again
+	h.record("health", "healthy", time.Second, 1, 0)
+	assert.Nil(t, h.check())
+
+	// the only ScaledJob left falls behind
+	h.remove("health", "healthy")
+	assert.NotNil(t, h.check())
+}
@@ -0,0 +1,85 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// ImagePullBackOffJobFailed is the reason of the event emitted when a Job stuck in ImagePullBackOff is failed
+	ImagePullBackOffJobFailed = "ImagePullBackOffJobFailed"
+
+	imagePullBackOffReason = "ImagePullBackOff"
+	// Age of a pod stuck in ImagePullBackOff its Job is failed at, the pull may succeed once eg. the registry
+	// recovers or the pull secret is fixed
+	defaultImagePullBackOffGracePeriod = 5 * time.Minute
+	// activeDeadlineSeconds the Jobs stuck in ImagePullBackOff are failed with
+	imagePullBackOffDeadlineSeconds = 1
+)
+
+// failImagePullBackOffJobs fails the running Jobs whose pods can't pull their image for longer than the grace
+// period, such Jobs never finish and would count as running forever. The activeDeadlineSeconds of the Job is
+// lowered, so the Job controller fails the Job with the DeadlineExceeded reason and removes its pods
+func (e *scaleExecutor) failImagePullBackOffJobs(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+	jobs, err := e.listJobs(ctx, scaledJob)
+	if err != nil {
+		return err
+	}
+
+	pods, err := e.listJobPods(ctx, scaledJob)
+	if err != nil {
+		return err
+	}
+
+	stuckPods := make(map[string]*corev1.Pod)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if isPodInImagePullBackOff(pod) && time.Since(pod.CreationTimestamp.Time) >= defaultImagePullBackOffGracePeriod {
+			stuckPods[getPodJobName(pod)] = pod
+		}
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		pod, ok := stuckPods[job.GetName()]
+		if !ok || IsJobFinished(job) {
+			continue
+		}
+		// the Job was failed by a previous scaling already and waits for the Job controller
+		if job.Spec.ActiveDeadlineSeconds != nil && *job.Spec.ActiveDeadlineSeconds == imagePullBackOffDeadlineSeconds {
+			continue
+		}
+
+		patch := client.MergeFrom(job.DeepCopy())
+		deadline := int64(imagePullBackOffDeadlineSeconds)
+		job.Spec.ActiveDeadlineSeconds = &deadline
+		err = e.client.Patch(ctx, job, patch)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Fail a Job stuck in ImagePullBackOff", "job.Name", job.GetName(), "pod.Name", pod.GetName())
+		e.recordEvent(scaledJob, corev1.EventTypeWarning, ImagePullBackOffJobFailed,
+			"Failed Job %s, its pod %s is stuck in ImagePullBackOff", job.GetName(), pod.GetName())
+	}
+	return nil
+}
+
+// isPodInImagePullBackOff returns true if a container of the pod is waiting to pull its image again
+func isPodInImagePullBackOff(pod *corev1.Pod) bool {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason == imagePullBackOffReason {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestFailImagePullBackOffJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.FailImagePullBackOffJobs = true
+
+	deadline := int64(imagePullBackOffDeadlineSeconds)
+	jobs := []batchv1.Job{
+		getRunningJob("stuck", time.Now(), nil),
+		getRunningJob("running", time.Now(), nil),
+		getRunningJob("pulling", time.Now(), nil),
+		// failed by a previous scaling already
+		getRunningJob("failed", time.Now(), &deadline),
+	}
+	backOff := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}
+	pods := []corev1.Pod{
+		getJobPod("stuck-abcde", "stuck", backOff),
+		getJobPod("running-abcde", "running", corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}),
+		// still within the grace period
+		getJobPod("pulling-abcde", "pulling", backOff),
+		getJobPod("failed-abcde", "failed", backOff),
+	}
+	pods[0].CreationTimestamp = metav1.NewTime(time.Now().Add(-defaultImagePullBackOffGracePeriod))
+	pods[2].CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+	pods[3].CreationTimestamp = pods[0].CreationTimestamp
+
+	var patchedJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		switch l := list.(type) {
+		case *batchv1.JobList:
+			l.Items = jobs
+		case *corev1.PodList:
+			l.Items = pods
+		}
+	}).
+		Return(nil).AnyTimes()
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchedJobs = append(patchedJobs, obj.(*batchv1.Job))
+	}).
+		Return(nil)
+	scaleExecutor := getMockScaleExecutor(client)
+	recorder := record.NewFakeRecorder(10)
+	scaleExecutor.recorder = recorder
+
+	err := scaleExecutor.failImagePullBackOffJobs(context.TODO(), scaleExecutor.logger, scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(patchedJobs))
+	assert.Equal(t, "stuck", patchedJobs[0].Name)
+	assert.Equal(t, int64(1), *patchedJobs[0].Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, 1, len(recorder.Events))
+	assert.Contains(t, <-recorder.Events, "Warning ImagePullBackOffJobFailed Failed Job stuck")
+}
+
+func TestIsPodInImagePullBackOff(t *testing.T) {
+	pod := getJobPod("pod", "job", corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+	assert.False(t, isPodInImagePullBackOff(&pod))
+
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+	}
+	assert.True(t, isPodInImagePullBackOff(&pod))
+}
+
+func getJobPod(name string, jobName string, state corev1.ContainerState) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"job-name": jobName},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{State: state}},
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package executor
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// splitIncidentJobs returns the jobs without the incidentRetentionAnnotation of the ScaledJob and the jobs
+// with it. An incident system sets the annotation on the jobs it references, eg. to the incident id, so they are
+// kept regardless of the history limits until it removes the annotation once the incident clears
+func splitIncidentJobs(scaledJob *kedav1alpha1.ScaledJob, jobs []batchv1.Job) ([]batchv1.Job, []batchv1.Job) {
+	annotation := scaledJob.Spec.IncidentRetentionAnnotation
+	if annotation == "" {
+		return jobs, nil
+	}
+
+	remaining := make([]batchv1.Job, 0, len(jobs))
+	var held []batchv1.Job
+	for _, job := range jobs {
+		if job.Annotations[annotation] != "" {
+			held = append(held, job)
+		} else {
+			remaining = append(remaining, job)
+		}
+	}
+	return remaining, held
+}
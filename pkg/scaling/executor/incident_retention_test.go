@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestCleanUpRetainsIncidentJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(1, 0)
+	scaledJob.Spec.IncidentRetentionAnnotation = "incidents.example.com/id"
+
+	incident := func(job *batchv1.Job, id string) batchv1.Job {
+		job.Annotations = map[string]string{"incidents.example.com/id": id}
+		return *job
+	}
+	jobs := []batchv1.Job{
+		incident(getJob(t, "success1", "2020-07-29T15:31:00Z", batchv1.JobComplete), "INC-1"),
+		*getJob(t, "success2", "2020-07-29T15:34:00Z", batchv1.JobComplete),
+		*getJob(t, "success3", "2020-07-29T15:36:00Z", batchv1.JobComplete),
+		incident(getJob(t, "fail1", "2020-07-29T15:32:00Z", batchv1.JobFailed), "INC-2"),
+		*getJob(t, "fail2", "2020-07-29T15:35:00Z", batchv1.JobFailed),
+		// the incident cleared
+		incident(getJob(t, "fail3", "2020-07-29T15:37:00Z", batchv1.JobFailed), ""),
+	}
+
+	var actualDeletedJobName = make(map[string]string)
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = jobs
+	}).
+		Return(nil)
+	expectDelete(t, client, &actualDeletedJobName)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	// the jobs referenced by an incident don't count towards the limits
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"success2": "success2", "fail2": "fail2", "fail3": "fail3"}, actualDeletedJobName)
+	assert.Equal(t, &kedav1alpha1.CleanupDeletedCount{Completed: 1, Failed: 2}, scaledJob.Status.LastCleanupDeletedCount)
+	assert.Equal(t, float64(2), getHistoryRetained(t, scaledJob.Namespace, scaledJob.Name, historyCategoryCompleted))
+	assert.Equal(t, float64(1), getHistoryRetained(t, scaledJob.Namespace, scaledJob.Name, historyCategoryFailed))
+}
+
+func TestSplitIncidentJobsWithoutAnnotation(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	job := *getJob(t, "success1", "2020-07-29T15:31:00Z", batchv1.JobComplete)
+	job.Annotations = map[string]string{"incidents.example.com/id": "INC-1"}
+
+	// not set, no job is held
+	remaining, held := splitIncidentJobs(scaledJob, []batchv1.Job{job})
+	assert.Equal(t, 1, len(remaining))
+	assert.Equal(t, 0, len(held))
+}
@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// Length of the hash suffix of the hashed label values
+	labelValueHashLength = 16
+)
+
+// GetScaledJobLabelValue returns the value of the "scaledjob" label the Jobs of the ScaledJob are selected by,
+// the value recorded in the status is used once set, so the Jobs stay selected if the hashing changes
+func GetScaledJobLabelValue(scaledJob *kedav1alpha1.ScaledJob) string {
+	if scaledJob.Status.JobLabelValue != "" {
+		return scaledJob.Status.JobLabelValue
+	}
+	return getLabelValue(scaledJob.GetName())
+}
+
+// getLabelValue returns the name if it is a valid label value, so the existing Jobs labeled with the plain name
+// are still selected. Longer names are truncated and suffixed with the hash of the whole name, the Jobs of
+// ScaledJobs whose names share the truncated prefix would be miscounted as each other's Jobs otherwise
+func getLabelValue(name string) string {
+	if len(validation.IsValidLabelValue(name)) == 0 {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(hash[:])[:labelValueHashLength]
+	prefix := name
+	if maxPrefixLength := validation.LabelValueMaxLength - labelValueHashLength - 1; len(prefix) > maxPrefixLength {
+		prefix = prefix[:maxPrefixLength]
+	}
+	// the label value has to end with an alphanumeric character before the suffix is appended
+	prefix = strings.TrimRight(prefix, "-_.")
+	return prefix + "-" + suffix
+}
+
+// IsJobLabelMissing returns true if the Job is controlled by a ScaledJob but doesn't have the "scaledjob" label
+// with the label value of the ScaledJob, eg. because the label was stripped by an admission controller.
+// The Jobs without the label are not selected when the Jobs of the ScaledJob are counted and cleaned up
+func IsJobLabelMissing(job metav1.Object) bool {
+	owner := metav1.GetControllerOf(job)
+	if owner == nil || owner.Kind != "ScaledJob" {
+		return false
+	}
+	return job.GetLabels()["scaledjob"] != getLabelValue(owner.Name)
+}
+
+// RestoreJobLabels sets the "scaledjob" label again on the Jobs controlled by the ScaledJob that are missing it,
+// the Jobs are matched by the UID of their owner reference as they can't be selected by the label. The Jobs spread
+// across the jobNamespaces have no owner reference, they are matched by the ScaledJobUIDAnnotation instead
+// and get their ScaledJobNamespaceLabel restored too
+func (e *scaleExecutor) RestoreJobLabels(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error {
+	logger := e.logger.WithValues("scaledJob.Name", scaledJob.Name, "scaledJob.Namespace", scaledJob.Namespace)
+
+	restored := 0
+	for _, namespace := range e.config.getJobListNamespaces(scaledJob) {
+		jobs := &batchv1.JobList{}
+		err := e.client.List(ctx, jobs, client.InNamespace(namespace))
+		if err != nil {
+			return err
+		}
+
+		for i := range jobs.Items {
+			job := &jobs.Items[i]
+			if !isJobOfScaledJobMissingLabels(job, scaledJob) {
+				continue
+			}
+
+			patch := client.MergeFrom(job.DeepCopy())
+			if job.Labels == nil {
+				job.Labels = map[string]string{}
+			}
+			job.Labels["scaledjob"] = GetScaledJobLabelValue(scaledJob)
+			if len(scaledJob.Spec.JobNamespaces) > 0 {
+				job.Labels[ScaledJobNamespaceLabel] = scaledJob.GetNamespace()
+			}
+			err = e.client.Patch(ctx, job, patch)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			logger.V(1).Info("Restored the scaledjob label of the job", "job", job.Name, "job.Namespace", job.Namespace)
+			restored++
+		}
+	}
+	if restored > 0 {
+		logger.Info("Restored the scaledjob label of jobs missing it", "Number of jobs", restored)
+	}
+	return nil
+}
+
+// isJobOfScaledJobMissingLabels returns true if the Job belongs to the ScaledJob but misses one of the labels
+// its Jobs are selected by
+func isJobOfScaledJobMissingLabels(job *batchv1.Job, scaledJob *kedav1alpha1.ScaledJob) bool {
+	if isSpreadJob(job, scaledJob) {
+		return job.Labels["scaledjob"] != GetScaledJobLabelValue(scaledJob) ||
+			job.Labels[ScaledJobNamespaceLabel] != scaledJob.GetNamespace()
+	}
+
+	owner := metav1.GetControllerOf(job)
+	if owner == nil || owner.UID != scaledJob.GetUID() {
+		return false
+	}
+	if len(scaledJob.Spec.JobNamespaces) > 0 && job.Labels[ScaledJobNamespaceLabel] != scaledJob.GetNamespace() {
+		return true
+	}
+	return IsJobLabelMissing(job)
+}
@@ -0,0 +1,149 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestIsJobLabelMissing(t *testing.T) {
+	// not owned by a ScaledJob
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	assert.False(t, IsJobLabelMissing(job))
+
+	job = getOwnedJob("consumer-1", "consumer", "uid-1")
+	assert.True(t, IsJobLabelMissing(job))
+
+	job.Labels = map[string]string{"scaledjob": "consumer"}
+	assert.False(t, IsJobLabelMissing(job))
+
+	job.Labels = map[string]string{"scaledjob": "another-consumer"}
+	assert.True(t, IsJobLabelMissing(job))
+}
+
+func TestGetScaledJobLabelValue(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+
+	// valid label values are kept, so the existing Jobs are still selected
+	scaledJob.Name = "azure-storage-queue-consumer"
+	assert.Equal(t, "azure-storage-queue-consumer", GetScaledJobLabelValue(scaledJob))
+	scaledJob.Name = strings.Repeat("a", 63)
+	assert.Equal(t, scaledJob.Name, GetScaledJobLabelValue(scaledJob))
+
+	// names sharing the first 63 characters get distinct hashed values
+	prefix := strings.Repeat("a", 63)
+	scaledJob.Name = prefix + "-orders"
+	orders := GetScaledJobLabelValue(scaledJob)
+	scaledJob.Name = prefix + "-payments"
+	payments := GetScaledJobLabelValue(scaledJob)
+	assert.NotEqual(t, orders, payments)
+	for _, value := range []string{orders, payments} {
+		assert.Empty(t, validation.IsValidLabelValue(value))
+		assert.Equal(t, validation.LabelValueMaxLength, len(value))
+		assert.True(t, strings.HasPrefix(value, strings.Repeat("a", 46)+"-"))
+	}
+	assert.Equal(t, payments, GetScaledJobLabelValue(scaledJob))
+
+	// the truncated name doesn't end with a dash before the hash
+	scaledJob.Name = strings.Repeat("a", 45) + "-" + strings.Repeat("b", 30)
+	value := GetScaledJobLabelValue(scaledJob)
+	assert.Empty(t, validation.IsValidLabelValue(value))
+	assert.True(t, strings.HasPrefix(value, strings.Repeat("a", 45)+"-"))
+	assert.False(t, strings.Contains(value, "--"))
+
+	// the value recorded in the status is used
+	scaledJob.Status.JobLabelValue = "recorded"
+	assert.Equal(t, "recorded", GetScaledJobLabelValue(scaledJob))
+}
+
+func TestHashedJobLabelValueSelectsJobs(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = strings.Repeat("consumer-", 10) + "orders"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	value := GetScaledJobLabelValue(scaledJob)
+
+	scaleExecutor := getMockScaleExecutor(nil)
+	job := scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	assert.Equal(t, value, job.Labels["scaledjob"])
+	assert.Equal(t, value, job.Spec.Template.Labels["scaledjob"])
+	assert.Equal(t, value, job.Labels["app.kubernetes.io/name"])
+
+	listOptions := &runtimeclient.ListOptions{}
+	for _, opt := range scaleExecutor.getJobListOptions(scaledJob, scaledJob.Namespace) {
+		opt.ApplyToList(listOptions)
+	}
+	assert.True(t, listOptions.LabelSelector.Matches(labels.Set(job.Labels)))
+
+	// the Job created with the hashed value is not missing the label
+	job.OwnerReferences = getOwnedJob(job.Name, scaledJob.Name, "uid-1").OwnerReferences
+	assert.False(t, IsJobLabelMissing(job))
+}
+
+func TestRestoreJobLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "consumer"
+	scaledJob.UID = "uid-1"
+
+	labeled := getOwnedJob("consumer-labeled", "consumer", "uid-1")
+	labeled.Labels = map[string]string{"scaledjob": "consumer"}
+	jobs := []batchv1.Job{
+		// the label was stripped
+		*getOwnedJob("consumer-stripped", "consumer", "uid-1"),
+		*labeled,
+		// owned by a previous ScaledJob with the same name
+		*getOwnedJob("consumer-previous", "consumer", "uid-0"),
+		{ObjectMeta: metav1.ObjectMeta{Name: "ownerless"}},
+	}
+
+	var patchedJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = jobs
+	}).
+		Return(nil)
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchedJobs = append(patchedJobs, obj.(*batchv1.Job))
+	}).
+		Return(nil)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.RestoreJobLabels(context.TODO(), scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(patchedJobs))
+	assert.Equal(t, "consumer-stripped", patchedJobs[0].Name)
+	assert.Equal(t, "consumer", patchedJobs[0].Labels["scaledjob"])
+}
+
+// getOwnedJob returns a Job without labels controlled by the ScaledJob with the name and UID
+func getOwnedJob(name string, scaledJobName string, uid types.UID) *batchv1.Job {
+	isController := true
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			OwnerReferences: []metav1.OwnerReference{{
+				Kind:       "ScaledJob",
+				Name:       scaledJobName,
+				UID:        uid,
+				Controller: &isController,
+			}},
+		},
+	}
+}
@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// ScaledJobNamespaceLabel is set to the namespace of the ScaledJob on its Jobs (and their pods) when they are spread
+	// across the jobNamespaces, the Jobs of the ScaledJob are listed in all namespaces by this label and the scaledjob label
+	ScaledJobNamespaceLabel = "keda.sh/scaledjob-namespace"
+	// ScaledJobUIDAnnotation is set to the UID of the ScaledJob on its Jobs (and their pods) created outside of its namespace,
+	// they have no owner reference and anyone able to create Jobs there could set the labels, so they are only counted
+	// and cleaned up if they carry the UID
+	ScaledJobUIDAnnotation = "keda.sh/scaledjob-uid"
+)
+
+// isJobNamespaceAllowed returns true if the Jobs of the ScaledJob can be created in the namespace, the Jobs of the
+// namespaces not allowed by the operator must not be created or deleted on behalf of the users creating ScaledJobs
+func (c Config) isJobNamespaceAllowed(scaledJob *kedav1alpha1.ScaledJob, namespace string) bool {
+	if namespace == scaledJob.Namespace {
+		return true
+	}
+	for _, allowed := range c.AllowedJobNamespaces {
+		if strings.TrimSpace(allowed) == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// jobNamespaceCursors holds the index of the namespace the next Job of every ScaledJob is created in
+type jobNamespaceCursors struct {
+	cursors sync.Map
+}
+
+var jobNamespaces = &jobNamespaceCursors{}
+
+// next returns the namespace of the namespaces to create the next Job of the ScaledJob in, round-robin
+func (c *jobNamespaceCursors) next(scaledJob *kedav1alpha1.ScaledJob, namespaces []string) string {
+	key := jobScaleStateKey(scaledJob.Namespace, scaledJob.Name)
+
+	index := 0
+	if cursor, ok := c.cursors.Load(key); ok {
+		// the list may have been shortened meanwhile
+		index = cursor.(int) % len(namespaces)
+	}
+	c.cursors.Store(key, (index+1)%len(namespaces))
+	return namespaces[index]
+}
+
+func (c *jobNamespaceCursors) remove(namespace string, name string) {
+	c.cursors.Delete(jobScaleStateKey(namespace, name))
+}
+
+// assignJobNamespace moves the Job generated for the ScaledJob into the namespace and labels it with the namespace
+// of the ScaledJob. Owner references can't cross namespaces, the garbage collector would delete the Job right away,
+// so the Jobs created outside the namespace of the ScaledJob carry its UID instead and are deleted by its finalizer
+func assignJobNamespace(job *batchv1.Job, scaledJob *kedav1alpha1.ScaledJob, namespace string) {
+	job.Labels[ScaledJobNamespaceLabel] = scaledJob.Namespace
+	if job.Spec.Template.Labels == nil {
+		job.Spec.Template.Labels = map[string]string{}
+	}
+	job.Spec.Template.Labels[ScaledJobNamespaceLabel] = scaledJob.Namespace
+
+	if namespace != scaledJob.Namespace {
+		job.Namespace = namespace
+		job.OwnerReferences = nil
+		if job.Annotations == nil {
+			job.Annotations = map[string]string{}
+		}
+		job.Annotations[ScaledJobUIDAnnotation] = string(scaledJob.UID)
+		if job.Spec.Template.Annotations == nil {
+			job.Spec.Template.Annotations = map[string]string{}
+		}
+		job.Spec.Template.Annotations[ScaledJobUIDAnnotation] = string(scaledJob.UID)
+	}
+}
+
+// getScaledJobNamespaces returns the namespaces the Jobs of the ScaledJob are created in, its own namespace
+// unless the jobNamespaces are set. The jobNamespaces not allowed by the operator are skipped
+func (c Config) getScaledJobNamespaces(scaledJob *kedav1alpha1.ScaledJob) []string {
+	var namespaces []string
+	for _, namespace := range scaledJob.Spec.JobNamespaces {
+		if c.isJobNamespaceAllowed(scaledJob, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		return []string{scaledJob.Namespace}
+	}
+	return namespaces
+}
+
+// getJobListNamespaces returns the namespaces the Jobs of the ScaledJob are listed in, its own namespace first
+func (c Config) getJobListNamespaces(scaledJob *kedav1alpha1.ScaledJob) []string {
+	namespaces := []string{scaledJob.Namespace}
+	for _, namespace := range c.getScaledJobNamespaces(scaledJob) {
+		if namespace != scaledJob.Namespace {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// hasScaledJobUID returns true if the annotations of a Job, or of its pod, hold the UID of the ScaledJob
+func hasScaledJobUID(annotations map[string]string, scaledJob *kedav1alpha1.ScaledJob) bool {
+	return scaledJob.UID != "" && annotations[ScaledJobUIDAnnotation] == string(scaledJob.UID)
+}
+
+// isSpreadJob returns true if the Job was created for the ScaledJob outside of its namespace, such a Job has no
+// owner reference and is matched by the UID of the ScaledJob it carries
+func isSpreadJob(job *batchv1.Job, scaledJob *kedav1alpha1.ScaledJob) bool {
+	return job.Namespace != scaledJob.Namespace && hasScaledJobUID(job.Annotations, scaledJob)
+}
+
+// listJobs lists the Jobs of the ScaledJob in its namespace and in each of its jobNamespaces, the Jobs listed outside
+// of its namespace are skipped unless they carry its UID
+func (e *scaleExecutor) listJobs(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) (*batchv1.JobList, error) {
+	jobs := &batchv1.JobList{}
+	for _, namespace := range e.config.getJobListNamespaces(scaledJob) {
+		list := &batchv1.JobList{}
+		if err := e.client.List(ctx, list, e.getJobListOptions(scaledJob, namespace)...); err != nil {
+			return nil, err
+		}
+		for _, job := range list.Items {
+			if namespace == scaledJob.Namespace || hasScaledJobUID(job.Annotations, scaledJob) {
+				jobs.Items = append(jobs.Items, job)
+			}
+		}
+	}
+	return jobs, nil
+}
+
+// listJobPods lists the pods of the Jobs of the ScaledJob like listJobs
+func (e *scaleExecutor) listJobPods(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) (*corev1.PodList, error) {
+	pods := &corev1.PodList{}
+	for _, namespace := range e.config.getJobListNamespaces(scaledJob) {
+		list := &corev1.PodList{}
+		if err := e.client.List(ctx, list, e.getJobListOptions(scaledJob, namespace)...); err != nil {
+			return nil, err
+		}
+		for _, pod := range list.Items {
+			if namespace == scaledJob.Namespace || hasScaledJobUID(pod.Annotations, scaledJob) {
+				pods.Items = append(pods.Items, pod)
+			}
+		}
+	}
+	return pods, nil
+}
+
+// ValidateJobNamespaces checks the jobNamespaces are valid namespace names allowed by the operator and the other
+// settings of the ScaledJob don't require its Jobs to run in its own namespace
+func (c Config) ValidateJobNamespaces(scaledJob *kedav1alpha1.ScaledJob) error {
+	if len(scaledJob.Spec.JobNamespaces) == 0 {
+		return nil
+	}
+
+	for _, namespace := range scaledJob.Spec.JobNamespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return fmt.Errorf("jobNamespaces entry %q is not valid: %s", namespace, strings.Join(errs, ", "))
+		}
+		if !c.isJobNamespaceAllowed(scaledJob, namespace) {
+			return fmt.Errorf("jobs can't be created in the namespace %s, only the namespace of the ScaledJob and the namespaces allowed by the operator are, allowed namespaces are: %s", namespace, strings.Join(c.AllowedJobNamespaces, ", "))
+		}
+	}
+	if scaledJob.Spec.EmitScaleConfigMap {
+		// the pods can only mount a ConfigMap of their own namespace
+		return fmt.Errorf("jobNamespaces can't be combined with emitScaleConfigMap, the ConfigMap is created in the namespace of the ScaledJob")
+	}
+	if scaledJob.Spec.AdoptOwnerlessJobs {
+		// the Jobs outside the namespace of the ScaledJob have no owner reference by design, adopting them would
+		// get them deleted by the garbage collector
+		return fmt.Errorf("jobNamespaces can't be combined with adoptOwnerlessJobs, the Jobs outside the namespace of the ScaledJob can't be owned by it")
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func getMockScaledJobWithJobNamespaces() *kedav1alpha1.ScaledJob {
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Namespace = "tenants"
+	scaledJob.UID = "uid-1"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.JobNamespaces = []string{"tenant-a", "tenant-b", "tenants"}
+	return scaledJob
+}
+
+func TestCreateJobsInJobNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithJobNamespaces()
+	defer jobNamespaces.remove(scaledJob.Namespace, scaledJob.Name)
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.AllowedJobNamespaces = []string{"tenant-a", "tenant-b"}
+
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 4, 10, time.Time{}, nil)
+	assert.Nil(t, err)
+	// the next scaling continues with the next namespace
+	_, err = scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 1, 10, time.Time{}, nil)
+	assert.Nil(t, err)
+
+	var namespaces []string
+	for _, job := range createdJobs {
+		namespaces = append(namespaces, job.Namespace)
+		assert.Equal(t, "tenants", job.Labels[ScaledJobNamespaceLabel])
+		assert.Equal(t, "tenants", job.Spec.Template.Labels[ScaledJobNamespaceLabel])
+		if job.Namespace == scaledJob.Namespace {
+			assert.Equal(t, 1, len(job.OwnerReferences))
+			assert.Equal(t, "", job.Annotations[ScaledJobUIDAnnotation])
+		} else {
+			// the garbage collector would delete the jobs owned across namespaces
+			assert.Equal(t, 0, len(job.OwnerReferences))
+			assert.Equal(t, "uid-1", job.Annotations[ScaledJobUIDAnnotation])
+			assert.Equal(t, "uid-1", job.Spec.Template.Annotations[ScaledJobUIDAnnotation])
+		}
+	}
+	assert.Equal(t, []string{"tenant-a", "tenant-b", "tenants", "tenant-a", "tenant-b"}, namespaces)
+}
+
+func TestCreateJobsInJobNamespacesNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithJobNamespaces()
+	defer jobNamespaces.remove(scaledJob.Namespace, scaledJob.Name)
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.AllowedJobNamespaces = []string{"tenant-b"}
+
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 3, 10, time.Time{}, nil)
+	assert.Nil(t, err)
+
+	// no jobs are created in the namespace not allowed by the operator
+	var namespaces []string
+	for _, job := range createdJobs {
+		namespaces = append(namespaces, job.Namespace)
+	}
+	assert.Equal(t, []string{"tenant-b", "tenants", "tenant-b"}, namespaces)
+}
+
+func TestGetRunningJobCountAcrossJobNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithJobNamespaces()
+
+	uid := map[string]string{ScaledJobUIDAnnotation: "uid-1"}
+	finished := getJob(t, "finished", "2020-07-29T15:31:00Z", batchv1.JobComplete)
+	finished.Namespace = "tenants"
+	jobs := map[string][]batchv1.Job{
+		"tenants":  {{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "tenants"}}, *finished},
+		"tenant-a": {{ObjectMeta: metav1.ObjectMeta{Name: "running-a", Namespace: "tenant-a", Annotations: uid}}},
+		// labeled like the jobs of the scaled job by someone able to create jobs in the namespace
+		"tenant-b": {
+			{ObjectMeta: metav1.ObjectMeta{Name: "running-b", Namespace: "tenant-b", Annotations: uid}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "spoofed", Namespace: "tenant-b"}},
+		},
+	}
+
+	var listOptions []runtimeclient.ListOptions
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, opts ...runtimeclient.ListOption) {
+		options := runtimeclient.ListOptions{}
+		options.ApplyOptions(opts)
+		listOptions = append(listOptions, options)
+		list.(*batchv1.JobList).Items = jobs[options.Namespace]
+	}).
+		Return(nil).Times(3)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.AllowedJobNamespaces = []string{"tenant-a", "tenant-b"}
+
+	count, err := scaleExecutor.getRunningJobCount(scaledJob, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// the jobs are listed in each namespace by the namespace of the scaled job
+	var namespaces []string
+	for _, options := range listOptions {
+		namespaces = append(namespaces, options.Namespace)
+		assert.Equal(t, "keda.sh/scaledjob-namespace=tenants,scaledjob=azure-storage-queue-consumer", options.LabelSelector.String())
+	}
+	assert.Equal(t, []string{"tenants", "tenant-a", "tenant-b"}, namespaces)
+}
+
+func TestDeleteJobsInAllowedJobNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithJobNamespaces()
+	// the jobs created in tenant-a before it was removed from the jobNamespaces are deleted too
+	scaledJob.Spec.JobNamespaces = []string{"tenant-b"}
+
+	uid := map[string]string{ScaledJobUIDAnnotation: "uid-1"}
+	jobs := map[string][]batchv1.Job{
+		"tenants":  {{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "tenants"}}},
+		"tenant-a": {{ObjectMeta: metav1.ObjectMeta{Name: "spread-a", Namespace: "tenant-a", Annotations: uid}}},
+		"tenant-b": {
+			{ObjectMeta: metav1.ObjectMeta{Name: "spread-b", Namespace: "tenant-b", Annotations: uid}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "tenant-b"}},
+		},
+	}
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, opts ...runtimeclient.ListOption) {
+		options := runtimeclient.ListOptions{}
+		options.ApplyOptions(opts)
+		list.(*batchv1.JobList).Items = jobs[options.Namespace]
+	}).
+		Return(nil).Times(3)
+	var deletedJobs []string
+	client.EXPECT().
+		Delete(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.DeleteOption) {
+		deletedJobs = append(deletedJobs, obj.(*batchv1.Job).Name)
+	}).
+		Return(nil).Times(3)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.AllowedJobNamespaces = []string{"tenant-a", "tenant-b"}
+
+	err := scaleExecutor.deleteJobs(context.TODO(), scaleExecutor.logger, scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"owned", "spread-a", "spread-b"}, deletedJobs)
+}
+
+func TestValidateJobNamespaces(t *testing.T) {
+	config := Config{AllowedJobNamespaces: []string{"tenant-a", "tenant-b"}}
+
+	scaledJob := getMockScaledJobWithJobNamespaces()
+	assert.Nil(t, config.ValidateJobNamespaces(scaledJob))
+
+	// only the namespaces allowed by the operator
+	scaledJob.Spec.JobNamespaces = []string{"tenant-a", "kube-system"}
+	assert.NotNil(t, config.ValidateJobNamespaces(scaledJob))
+
+	scaledJob.Spec.JobNamespaces = []string{"Tenant_A"}
+	assert.NotNil(t, config.ValidateJobNamespaces(scaledJob))
+
+	scaledJob.Spec.JobNamespaces = []string{"tenant-a"}
+	scaledJob.Spec.EmitScaleConfigMap = true
+	assert.NotNil(t, config.ValidateJobNamespaces(scaledJob))
+
+	scaledJob.Spec.EmitScaleConfigMap = false
+	scaledJob.Spec.AdoptOwnerlessJobs = true
+	assert.NotNil(t, config.ValidateJobNamespaces(scaledJob))
+}
+
+func TestRestoreJobLabelsInJobNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithJobNamespaces()
+	defer jobNamespaces.remove(scaledJob.Namespace, scaledJob.Name)
+	scaleExecutor := getMockScaleExecutor(nil)
+	scaleExecutor.config.AllowedJobNamespaces = []string{"tenant-a", "tenant-b"}
+
+	owned := scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	owned.Namespace = "tenants"
+	owned.OwnerReferences = getOwnedJob(owned.Name, scaledJob.Name, "uid-1").OwnerReferences
+	delete(owned.Labels, ScaledJobNamespaceLabel)
+	stripped := scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	stripped.Namespace = "tenant-a"
+	stripped.Labels = nil
+	labeled := scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	labeled.Namespace = "tenant-a"
+	other := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "tenant-b"}}
+	jobs := map[string][]batchv1.Job{
+		"tenants":  {*owned},
+		"tenant-a": {*stripped, *labeled},
+		"tenant-b": {*other},
+	}
+
+	var listedNamespaces []string
+	var patchedJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, opts ...runtimeclient.ListOption) {
+		listOptions := &runtimeclient.ListOptions{}
+		for _, opt := range opts {
+			opt.ApplyToList(listOptions)
+		}
+		listedNamespaces = append(listedNamespaces, listOptions.Namespace)
+		list.(*batchv1.JobList).Items = jobs[listOptions.Namespace]
+	}).
+		Return(nil).Times(3)
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchedJobs = append(patchedJobs, obj.(*batchv1.Job))
+	}).
+		Return(nil).Times(2)
+	scaleExecutor = getMockScaleExecutor(client)
+
+	err := scaleExecutor.RestoreJobLabels(context.TODO(), scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"tenants", "tenant-a", "tenant-b"}, listedNamespaces)
+	assert.Equal(t, 2, len(patchedJobs))
+	for _, job := range patchedJobs {
+		assert.Equal(t, "azure-storage-queue-consumer", job.Labels["scaledjob"])
+		assert.Equal(t, "tenants", job.Labels[ScaledJobNamespaceLabel])
+	}
+	assert.Equal(t, "tenants", patchedJobs[0].Namespace)
+	assert.Equal(t, "tenant-a", patchedJobs[1].Namespace)
+}
+
+func TestGetMaxScaleByPendingPressureInJobNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithJobNamespaces()
+	scaledJob.Spec.RespectPendingPressure = true
+
+	pending := map[string]int{"tenant-a": 1, "tenant-b": 3, "tenants": 0}
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, opts ...runtimeclient.ListOption) {
+		listOptions := &runtimeclient.ListOptions{}
+		for _, opt := range opts {
+			opt.ApplyToList(listOptions)
+		}
+		pods := list.(*corev1.PodList)
+		for i := 0; i < pending[listOptions.Namespace]; i++ {
+			pod := getPod(corev1.PodPending, "")
+			pod.Annotations = map[string]string{ScaledJobUIDAnnotation: "uid-1"}
+			pods.Items = append(pods.Items, pod)
+		}
+		if listOptions.Namespace != "tenants" {
+			// not a pod of the jobs of the scaled job, its labels are set by someone able to create pods in the namespace
+			pods.Items = append(pods.Items, getPod(corev1.PodPending, ""))
+		}
+	}).
+		Return(nil).Times(3)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.AllowedJobNamespaces = []string{"tenant-a", "tenant-b"}
+	scaleExecutor.config.PendingPodsThreshold = 5
+
+	// capped by the unscheduled pods of the jobs of all namespaces
+	assert.Equal(t, int64(1), scaleExecutor.getMaxScaleByPendingPressure(context.TODO(), scaleExecutor.logger, scaledJob, 10))
+}
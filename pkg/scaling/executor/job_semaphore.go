@@ -0,0 +1,258 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// SemaphoreHoldersAnnotation holds the permits of every ScaledJob sharing the semaphore Lease as a JSON object
+	// of the ScaledJob namespace/name to its number of permits
+	SemaphoreHoldersAnnotation = "keda.sh/semaphore-holders"
+)
+
+// ValidateSemaphoreRef checks the semaphore Lease of the ScaledJob is in its own namespace or in a namespace allowed by the operator,
+// the Leases of the other namespaces must not be created or updated on behalf of the users creating ScaledJobs
+func (c Config) ValidateSemaphoreRef(scaledJob *kedav1alpha1.ScaledJob) error {
+	ref := scaledJob.Spec.SemaphoreRef
+	if ref == nil || ref.Namespace == "" || ref.Namespace == scaledJob.Namespace {
+		return nil
+	}
+	for _, namespace := range c.SemaphoreNamespaces {
+		if strings.TrimSpace(namespace) == ref.Namespace {
+			return nil
+		}
+	}
+	return fmt.Errorf("the semaphore can't be in the namespace %s, only the namespace of the ScaledJob and the namespaces allowed by the operator are, allowed namespaces are: %s", ref.Namespace, strings.Join(c.SemaphoreNamespaces, ", "))
+}
+
+// errNoJobSemaphore is logged instead of creating Jobs when the executor was created without a semaphore
+var errNoJobSemaphore = fmt.Errorf("scale executor has no semaphore, the permits can't be acquired")
+
+// JobSemaphore is a distributed semaphore shared by ScaledJobs, every running Job of a holder holds a permit
+type JobSemaphore interface {
+	// Acquire sets the permits of the holder to its running Jobs, the permits of the finished Jobs are released,
+	// and acquires up to count more permits, the number of the acquired permits is returned
+	Acquire(ctx context.Context, key types.NamespacedName, permits int64, holder string, running int64, count int64) (int64, error)
+	// Release releases the permits of the holder beyond remaining, eg. acquired for the Jobs that weren't created,
+	// the holder is removed once it has no permits
+	Release(ctx context.Context, key types.NamespacedName, holder string, remaining int64) error
+}
+
+// leaseJobSemaphore stores the permits of the holders in an annotation of a Lease,
+// the Lease is created on the first acquire and updated with optimistic concurrency
+type leaseJobSemaphore struct {
+	client client.Client
+}
+
+func newLeaseJobSemaphore(client client.Client) JobSemaphore {
+	return &leaseJobSemaphore{client: client}
+}
+
+func (s *leaseJobSemaphore) Acquire(ctx context.Context, key types.NamespacedName, permits int64, holder string, running int64, count int64) (int64, error) {
+	var acquired int64
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lease, holders, exists, err := s.getLease(ctx, key)
+		if err != nil {
+			return err
+		}
+		acquired = acquirePermits(holders, permits, holder, running, count)
+		return s.updateLease(ctx, lease, holders, exists)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return acquired, nil
+}
+
+func (s *leaseJobSemaphore) Release(ctx context.Context, key types.NamespacedName, holder string, remaining int64) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lease, holders, exists, err := s.getLease(ctx, key)
+		if err != nil || !exists {
+			return err
+		}
+		if !releasePermits(holders, holder, remaining) {
+			return nil
+		}
+		return s.updateLease(ctx, lease, holders, exists)
+	})
+}
+
+// getLease returns the Lease and the permits of its holders, a new Lease is returned if it doesn't exist yet
+func (s *leaseJobSemaphore) getLease(ctx context.Context, key types.NamespacedName) (*coordinationv1.Lease, map[string]int64, bool, error) {
+	lease := &coordinationv1.Lease{}
+	err := s.client.Get(ctx, key, lease)
+	if errors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+		}
+		return lease, map[string]int64{}, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	holders := map[string]int64{}
+	if value, ok := lease.Annotations[SemaphoreHoldersAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &holders); err != nil {
+			return nil, nil, false, fmt.Errorf("invalid %s annotation of the Lease %s: %s", SemaphoreHoldersAnnotation, key, err)
+		}
+	}
+	return lease, holders, true, nil
+}
+
+func (s *leaseJobSemaphore) updateLease(ctx context.Context, lease *coordinationv1.Lease, holders map[string]int64, exists bool) error {
+	value, err := json.Marshal(holders)
+	if err != nil {
+		return err
+	}
+	if lease.Annotations == nil {
+		lease.Annotations = map[string]string{}
+	}
+	lease.Annotations[SemaphoreHoldersAnnotation] = string(value)
+
+	if exists {
+		return s.client.Update(ctx, lease)
+	}
+	err = s.client.Create(ctx, lease)
+	// another instance created the Lease in the meantime, it is read again
+	if errors.IsAlreadyExists(err) {
+		return errors.NewConflict(coordinationv1.Resource("leases"), lease.Name, err)
+	}
+	return err
+}
+
+// acquirePermits sets the permits of the holder to running and adds up to count of the permits
+// not held by the other holders, the number of the added permits is returned
+func acquirePermits(holders map[string]int64, permits int64, holder string, running int64, count int64) int64 {
+	var held int64
+	for name, n := range holders {
+		if name != holder {
+			held += n
+		}
+	}
+
+	free := permits - held - running
+	if free < 0 {
+		free = 0
+	}
+	if count > free {
+		count = free
+	}
+	if count < 0 {
+		count = 0
+	}
+
+	if running+count > 0 {
+		holders[holder] = running + count
+	} else {
+		delete(holders, holder)
+	}
+	return count
+}
+
+// releasePermits lowers the permits of the holder to remaining, it returns whether any permit was released
+func releasePermits(holders map[string]int64, holder string, remaining int64) bool {
+	held, ok := holders[holder]
+	if !ok || held <= remaining {
+		return false
+	}
+	if remaining > 0 {
+		holders[holder] = remaining
+	} else {
+		delete(holders, holder)
+	}
+	return true
+}
+
+// getSemaphoreKey returns the key of the semaphore Lease of the ScaledJob, an error is returned if the namespace of the Lease isn't allowed
+func (c Config) getSemaphoreKey(scaledJob *kedav1alpha1.ScaledJob) (types.NamespacedName, error) {
+	if err := c.ValidateSemaphoreRef(scaledJob); err != nil {
+		return types.NamespacedName{}, err
+	}
+	ref := scaledJob.Spec.SemaphoreRef
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = scaledJob.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}, nil
+}
+
+// getMaxScaleBySemaphore acquires the permits for the Jobs to create, maxScale is capped to the acquired permits.
+// No Jobs are created if the permits can't be acquired, the semaphore would be exceeded otherwise
+func (e *scaleExecutor) getMaxScaleBySemaphore(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, scaleTo int64, maxScale int64, runningJobCount int64) int64 {
+	ref := scaledJob.Spec.SemaphoreRef
+	if ref == nil {
+		return maxScale
+	}
+	if e.semaphore == nil {
+		logger.Error(errNoJobSemaphore, "Failed to acquire the semaphore permits, not creating Jobs")
+		return 0
+	}
+
+	requested := scaleTo
+	if requested > maxScale {
+		requested = maxScale
+	}
+	key, err := e.config.getSemaphoreKey(scaledJob)
+	if err != nil {
+		logger.Error(err, "Failed to acquire the semaphore permits, not creating Jobs")
+		return 0
+	}
+	holder := jobScaleStateKey(scaledJob.Namespace, scaledJob.Name)
+	acquired, err := e.semaphore.Acquire(ctx, key, int64(ref.Permits), holder, runningJobCount, requested)
+	if err != nil {
+		logger.Error(err, "Failed to acquire the semaphore permits, not creating Jobs", "lease", key)
+		return 0
+	}
+	if acquired < requested {
+		logger.Info("Number of Jobs reduced by the semaphore", "lease", key, "requested", requested, "acquired", acquired)
+	}
+	return acquired
+}
+
+// releaseSemaphorePermits releases the permits of the ScaledJob beyond its running Jobs,
+// eg. of the Jobs that finished or failed to be created
+func (e *scaleExecutor) releaseSemaphorePermits(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, runningJobCount int64) {
+	if scaledJob.Spec.SemaphoreRef == nil || e.semaphore == nil {
+		return
+	}
+
+	// no permits were acquired in a Lease whose namespace isn't allowed
+	key, err := e.config.getSemaphoreKey(scaledJob)
+	if err != nil {
+		return
+	}
+	holder := jobScaleStateKey(scaledJob.Namespace, scaledJob.Name)
+	if err := e.semaphore.Release(ctx, key, holder, runningJobCount); err != nil {
+		logger.Error(err, "Failed to release the semaphore permits", "lease", key)
+	}
+}
+
+// releaseAllSemaphorePermits removes the ScaledJob being deleted from the holders of the semaphore, its Jobs are deleted
+func (e *scaleExecutor) releaseAllSemaphorePermits(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+	if scaledJob.Spec.SemaphoreRef == nil || e.semaphore == nil {
+		return nil
+	}
+	key, err := e.config.getSemaphoreKey(scaledJob)
+	if err != nil {
+		logger.V(1).Info("Namespace of the semaphore is not allowed, no permits to release", "error", err.Error())
+		return nil
+	}
+	return e.semaphore.Release(ctx, key, jobScaleStateKey(scaledJob.Namespace, scaledJob.Name), 0)
+}
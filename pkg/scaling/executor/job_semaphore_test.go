@@ -0,0 +1,243 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+type semaphoreAcquire struct {
+	key     types.NamespacedName
+	permits int64
+	holder  string
+	running int64
+	count   int64
+}
+
+// fakeJobSemaphore hands out up to free permits and records the calls
+type fakeJobSemaphore struct {
+	free     int64
+	err      error
+	acquires []semaphoreAcquire
+	releases []int64
+}
+
+func (s *fakeJobSemaphore) Acquire(ctx context.Context, key types.NamespacedName, permits int64, holder string, running int64, count int64) (int64, error) {
+	s.acquires = append(s.acquires, semaphoreAcquire{key: key, permits: permits, holder: holder, running: running, count: count})
+	if s.err != nil {
+		return 0, s.err
+	}
+	if count > s.free {
+		count = s.free
+	}
+	s.free -= count
+	return count, nil
+}
+
+func (s *fakeJobSemaphore) Release(ctx context.Context, key types.NamespacedName, holder string, remaining int64) error {
+	s.releases = append(s.releases, remaining)
+	return nil
+}
+
+func getMockScaledJobWithSemaphore() *kedav1alpha1.ScaledJob {
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Namespace = "default"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.SemaphoreRef = &kedav1alpha1.SemaphoreRef{Name: "licensed-connector", Namespace: "shared", Permits: 10}
+	return scaledJob
+}
+
+func TestRequestJobScaleWithSemaphore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithSemaphore()
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.SemaphoreNamespaces = []string{"shared"}
+	semaphore := &fakeJobSemaphore{free: 3}
+	scaleExecutor.semaphore = semaphore
+
+	// only the jobs holding a permit are created
+	requeueAfter, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(createdJobs))
+	assert.Equal(t, getRequeueInterval(scaledJob), requeueAfter)
+	assert.Equal(t, []semaphoreAcquire{{
+		key:     types.NamespacedName{Namespace: "shared", Name: "licensed-connector"},
+		permits: 10,
+		holder:  "default/azure-storage-queue-consumer",
+		running: 0,
+		count:   5,
+	}}, semaphore.acquires)
+	assert.Equal(t, []int64{3}, semaphore.releases)
+}
+
+func TestRequestJobScaleWithSemaphoreError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithSemaphore()
+
+	// no job is created without the permits
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.SemaphoreNamespaces = []string{"shared"}
+	semaphore := &fakeJobSemaphore{free: 3, err: fmt.Errorf("lease unavailable")}
+	scaleExecutor.semaphore = semaphore
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(semaphore.acquires))
+	assert.Equal(t, []int64{0}, semaphore.releases)
+}
+
+func TestRequestJobScaleInactiveReleasesSemaphore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithSemaphore()
+
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.SemaphoreNamespaces = []string{"shared"}
+	semaphore := &fakeJobSemaphore{free: 3}
+	scaleExecutor.semaphore = semaphore
+
+	// the permits of the finished jobs are released without acquiring new ones
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(semaphore.acquires))
+	assert.Equal(t, []int64{0}, semaphore.releases)
+}
+
+func TestRequestJobScaleWithSemaphoreInNamespaceNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the shared namespace isn't allowed by the operator
+	scaledJob := getMockScaledJobWithSemaphore()
+
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	scaleExecutor := getMockScaleExecutor(client)
+	semaphore := &fakeJobSemaphore{free: 3}
+	scaleExecutor.semaphore = semaphore
+
+	// no job is created and the lease is never touched
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(semaphore.acquires))
+	assert.Equal(t, 0, len(semaphore.releases))
+}
+
+func TestValidateSemaphoreRef(t *testing.T) {
+	scaledJob := getMockScaledJobWithSemaphore()
+	assert.Error(t, DefaultConfig().ValidateSemaphoreRef(scaledJob))
+
+	config := Config{SemaphoreNamespaces: []string{"shared"}}
+	assert.NoError(t, config.ValidateSemaphoreRef(scaledJob))
+
+	// the namespace of the ScaledJob is always allowed
+	scaledJob.Spec.SemaphoreRef.Namespace = ""
+	assert.NoError(t, config.ValidateSemaphoreRef(scaledJob))
+	scaledJob.Spec.SemaphoreRef.Namespace = scaledJob.Namespace
+	assert.NoError(t, config.ValidateSemaphoreRef(scaledJob))
+
+	scaledJob.Spec.SemaphoreRef.Namespace = "kube-system"
+	assert.Error(t, config.ValidateSemaphoreRef(scaledJob))
+}
+
+func TestAcquirePermits(t *testing.T) {
+	holders := map[string]int64{"default/other": 6}
+
+	// 4 permits are free, one of them is held by the running job
+	assert.Equal(t, int64(3), acquirePermits(holders, 10, "default/job", 1, 5))
+	assert.Equal(t, map[string]int64{"default/other": 6, "default/job": 4}, holders)
+
+	// the finished jobs gave back their permits
+	assert.Equal(t, int64(2), acquirePermits(holders, 10, "default/job", 2, 2))
+	assert.Equal(t, map[string]int64{"default/other": 6, "default/job": 4}, holders)
+
+	// the other holder exceeds the permits, eg. they were lowered
+	assert.Equal(t, int64(0), acquirePermits(holders, 5, "default/job", 0, 2))
+	assert.Equal(t, map[string]int64{"default/other": 6}, holders)
+}
+
+func TestReleasePermits(t *testing.T) {
+	holders := map[string]int64{"default/job": 4}
+
+	assert.False(t, releasePermits(holders, "default/job", 4))
+	assert.False(t, releasePermits(holders, "default/unknown", 0))
+	assert.True(t, releasePermits(holders, "default/job", 1))
+	assert.Equal(t, map[string]int64{"default/job": 1}, holders)
+	assert.True(t, releasePermits(holders, "default/job", 0))
+	assert.Equal(t, map[string]int64{}, holders)
+}
+
+func TestLeaseJobSemaphoreCreatesLease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := types.NamespacedName{Namespace: "shared", Name: "licensed-connector"}
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().Get(gomock.Any(), key, gomock.Any()).
+		Return(errors.NewNotFound(coordinationv1.Resource("leases"), key.Name))
+	var created *coordinationv1.Lease
+	client.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.CreateOption) {
+		created = obj.(*coordinationv1.Lease)
+	}).Return(nil)
+
+	acquired, err := newLeaseJobSemaphore(client).Acquire(context.TODO(), key, 10, "default/job", 0, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), acquired)
+	assert.Equal(t, "licensed-connector", created.Name)
+	assert.Equal(t, "shared", created.Namespace)
+	assert.Equal(t, `{"default/job":3}`, created.Annotations[SemaphoreHoldersAnnotation])
+}
+
+func TestLeaseJobSemaphoreUpdatesLease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := types.NamespacedName{Namespace: "shared", Name: "licensed-connector"}
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().Get(gomock.Any(), key, gomock.Any()).Do(func(_ context.Context, _ types.NamespacedName, obj runtime.Object) {
+		lease := obj.(*coordinationv1.Lease)
+		lease.ObjectMeta = metav1.ObjectMeta{
+			Name:        key.Name,
+			Namespace:   key.Namespace,
+			Annotations: map[string]string{SemaphoreHoldersAnnotation: `{"default/job":3,"default/other":6}`},
+		}
+	}).Return(nil).Times(2)
+	var updated []string
+	client.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.UpdateOption) {
+		updated = append(updated, obj.(*coordinationv1.Lease).Annotations[SemaphoreHoldersAnnotation])
+	}).Return(nil).Times(2)
+	semaphore := newLeaseJobSemaphore(client)
+
+	// one job of the holder is still running
+	acquired, err := semaphore.Acquire(context.TODO(), key, 10, "default/job", 1, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), acquired)
+
+	err = semaphore.Release(context.TODO(), key, "default/job", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`{"default/job":4,"default/other":6}`, `{"default/other":6}`}, updated)
+}
@@ -0,0 +1,23 @@
+package executor
+
+import (
+	"sync/atomic"
+)
+
+// leaderElected is set to 1 if this KEDA instance holds the leader election lease
+// (or the leader election is disabled), it guards the creation of Jobs in HA setups
+var leaderElected int32 = 1
+
+// SetLeaderElected sets whether this KEDA instance is the elected leader
+func SetLeaderElected(elected bool) {
+	if elected {
+		atomic.StoreInt32(&leaderElected, 1)
+	} else {
+		atomic.StoreInt32(&leaderElected, 0)
+	}
+}
+
+// IsLeaderElected returns true if this KEDA instance is the elected leader
+func IsLeaderElected() bool {
+	return atomic.LoadInt32(&leaderElected) == 1
+}
@@ -0,0 +1,253 @@
+package executor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+
+	"github.com/go-logr/logr"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// Variables available in the maxFormula of a ScaledJob
+const (
+	maxFormulaQueueLength     = "queueLength"
+	maxFormulaMaxReplicaCount = "maxReplicaCount"
+	maxFormulaRunningJobCount = "runningJobCount"
+)
+
+// maxFormulaFunctions are the functions available in the maxFormula of a ScaledJob
+var maxFormulaFunctions = map[string]func(args []float64) (float64, error){
+	"min": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("min requires at least 1 argument")
+		}
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = math.Min(result, arg)
+		}
+		return result, nil
+	},
+	"max": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("max requires at least 1 argument")
+		}
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = math.Max(result, arg)
+		}
+		return result, nil
+	},
+	"ceil": func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("ceil requires 1 argument")
+		}
+		return math.Ceil(args[0]), nil
+	},
+	"floor": func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("floor requires 1 argument")
+		}
+		return math.Floor(args[0]), nil
+	},
+}
+
+// ValidateMaxFormula checks the maxFormula only uses numbers, the variables, the operators + - * / and the
+// maxFormulaFunctions, eg. "min(maxReplicaCount, ceil(queueLength / 10))". The formula is not evaluated,
+// a division by zero depends on the values of the variables and is reported by the evaluation
+func ValidateMaxFormula(formula string) error {
+	expr, err := parser.ParseExpr(formula)
+	if err != nil {
+		return fmt.Errorf("error parsing maxFormula %s: %s", formula, err)
+	}
+	if err := checkMaxFormula(expr); err != nil {
+		return fmt.Errorf("error checking maxFormula %s: %s", formula, err)
+	}
+	return nil
+}
+
+// getMaxScaleByFormula returns the maxScale computed by the ScaledJob's maxFormula, the maxScale of the triggers
+// is returned when the formula is not set or can't be evaluated. The maxReplicaCount variable is the maxReplicaCount
+// of the ScaledJob, the result is rounded down and kept between 0 and maxReplicaCount.
+// The evaluation is returned for the status of the ScaledJob, nil if the formula is not set
+func getMaxScaleByFormula(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, queueLength int64, maxScale int64, runningJobCount int64) (int64, *kedav1alpha1.FormulaEvaluation) {
+	modifiers := scaledJob.Spec.ScalingModifiers
+	if modifiers == nil || modifiers.MaxFormula == "" {
+		return maxScale, nil
+	}
+
+	maxReplicaCount := GetMaxReplicaCount(scaledJob)
+	evaluation := &kedav1alpha1.FormulaEvaluation{
+		Formula:         modifiers.MaxFormula,
+		QueueLength:     queueLength,
+		MaxReplicaCount: maxReplicaCount,
+		RunningJobCount: runningJobCount,
+		Result:          maxScale,
+	}
+	expr, err := parser.ParseExpr(modifiers.MaxFormula)
+	if err != nil {
+		logger.Error(err, "Failed to parse maxFormula, using maxScale", "maxFormula", modifiers.MaxFormula)
+		evaluation.Error = err.Error()
+		return maxScale, evaluation
+	}
+	result, err := evaluateMaxFormula(expr, map[string]float64{
+		maxFormulaQueueLength:     float64(queueLength),
+		maxFormulaMaxReplicaCount: float64(maxReplicaCount),
+		maxFormulaRunningJobCount: float64(runningJobCount),
+	})
+	if err != nil {
+		logger.Error(err, "Failed to evaluate maxFormula, using maxScale", "maxFormula", modifiers.MaxFormula)
+		evaluation.Error = err.Error()
+		return maxScale, evaluation
+	}
+
+	switch {
+	case result < 0:
+		evaluation.Result = 0
+	case result > float64(maxReplicaCount):
+		evaluation.Result = maxReplicaCount
+	default:
+		evaluation.Result = int64(result)
+	}
+	logger.V(1).Info("Computed maxScale by maxFormula", "maxFormula", modifiers.MaxFormula, "maxScale", evaluation.Result)
+	return evaluation.Result, evaluation
+}
+
+// setLastFormulaEval records the evaluation of the maxFormula in the status, it is only updated when it changed
+func setLastFormulaEval(scaledJob *kedav1alpha1.ScaledJob, evaluation *kedav1alpha1.FormulaEvaluation) {
+	last := scaledJob.Status.LastFormulaEval
+	if last == evaluation || (last != nil && evaluation != nil && *last == *evaluation) {
+		return
+	}
+	scaledJob.Status.LastFormulaEval = evaluation
+}
+
+// checkMaxFormula checks the expression is one evaluateMaxFormula supports without evaluating it,
+// the number of the arguments of the functions is checked by calling them with zeros
+func checkMaxFormula(expr ast.Expr) error {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT {
+			return fmt.Errorf("unsupported literal %s", e.Value)
+		}
+		return nil
+	case *ast.Ident:
+		switch e.Name {
+		case maxFormulaQueueLength, maxFormulaMaxReplicaCount, maxFormulaRunningJobCount:
+			return nil
+		}
+		return fmt.Errorf("unknown variable %s", e.Name)
+	case *ast.ParenExpr:
+		return checkMaxFormula(e.X)
+	case *ast.UnaryExpr:
+		if e.Op != token.SUB && e.Op != token.ADD {
+			return fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		return checkMaxFormula(e.X)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO:
+		default:
+			return fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		if err := checkMaxFormula(e.X); err != nil {
+			return err
+		}
+		return checkMaxFormula(e.Y)
+	case *ast.CallExpr:
+		ident, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return fmt.Errorf("unsupported function call")
+		}
+		function, ok := maxFormulaFunctions[ident.Name]
+		if !ok {
+			return fmt.Errorf("unknown function %s", ident.Name)
+		}
+		for _, arg := range e.Args {
+			if err := checkMaxFormula(arg); err != nil {
+				return err
+			}
+		}
+		_, err := function(make([]float64, len(e.Args)))
+		return err
+	}
+	return fmt.Errorf("unsupported expression")
+}
+
+// evaluateMaxFormula evaluates the arithmetic expression with the variables, only numbers, the variables,
+// the operators + - * / and the maxFormulaFunctions are allowed
+func evaluateMaxFormula(expr ast.Expr, variables map[string]float64) (float64, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT {
+			return 0, fmt.Errorf("unsupported literal %s", e.Value)
+		}
+		return strconv.ParseFloat(e.Value, 64)
+	case *ast.Ident:
+		value, ok := variables[e.Name]
+		if !ok {
+			return 0, fmt.Errorf("unknown variable %s", e.Name)
+		}
+		return value, nil
+	case *ast.ParenExpr:
+		return evaluateMaxFormula(e.X, variables)
+	case *ast.UnaryExpr:
+		x, err := evaluateMaxFormula(e.X, variables)
+		if err != nil {
+			return 0, err
+		}
+		switch e.Op {
+		case token.SUB:
+			return -x, nil
+		case token.ADD:
+			return x, nil
+		}
+		return 0, fmt.Errorf("unsupported operator %s", e.Op)
+	case *ast.BinaryExpr:
+		x, err := evaluateMaxFormula(e.X, variables)
+		if err != nil {
+			return 0, err
+		}
+		y, err := evaluateMaxFormula(e.Y, variables)
+		if err != nil {
+			return 0, err
+		}
+		switch e.Op {
+		case token.ADD:
+			return x + y, nil
+		case token.SUB:
+			return x - y, nil
+		case token.MUL:
+			return x * y, nil
+		case token.QUO:
+			if y == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return x / y, nil
+		}
+		return 0, fmt.Errorf("unsupported operator %s", e.Op)
+	case *ast.CallExpr:
+		ident, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return 0, fmt.Errorf("unsupported function call")
+		}
+		function, ok := maxFormulaFunctions[ident.Name]
+		if !ok {
+			return 0, fmt.Errorf("unknown function %s", ident.Name)
+		}
+		args := make([]float64, 0, len(e.Args))
+		for _, arg := range e.Args {
+			value, err := evaluateMaxFormula(arg, variables)
+			if err != nil {
+				return 0, err
+			}
+			args = append(args, value)
+		}
+		return function(args)
+	}
+	return 0, fmt.Errorf("unsupported expression")
+}
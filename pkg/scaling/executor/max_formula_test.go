@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestGetMaxScaleByFormula(t *testing.T) {
+	logger := logf.Log.WithName("test")
+
+	tests := []struct {
+		formula         string
+		queueLength     int64
+		maxScale        int64
+		maxReplicaCount int32
+		runningJobCount int64
+		expected        int64
+	}{
+		// not set, maxScale of the triggers
+		{formula: "", queueLength: 100, maxScale: 10, maxReplicaCount: 20, expected: 10},
+		// batches of 20 items
+		{formula: "min(maxReplicaCount, ceil(queueLength / 20))", queueLength: 50, maxScale: 10, maxReplicaCount: 10, expected: 3},
+		{formula: "min(maxReplicaCount, ceil(queueLength / 20))", queueLength: 500, maxScale: 10, maxReplicaCount: 10, expected: 10},
+		{formula: "min(maxReplicaCount, ceil(queueLength / 20))", queueLength: 0, maxScale: 0, maxReplicaCount: 10, expected: 0},
+		// maxReplicaCount is the one of the ScaledJob, not the maxScale of the triggers
+		{formula: "min(maxReplicaCount, ceil(queueLength / 20))", queueLength: 100, maxScale: 2, maxReplicaCount: 10, expected: 5},
+		{formula: "maxReplicaCount - runningJobCount", queueLength: 1, maxScale: 1, maxReplicaCount: 10, runningJobCount: 4, expected: 6},
+		// rounded down
+		{formula: "queueLength / 4", queueLength: 10, maxScale: 10, maxReplicaCount: 10, expected: 2},
+		// not negative
+		{formula: "maxReplicaCount - runningJobCount * 2", queueLength: 10, maxScale: 10, maxReplicaCount: 10, runningJobCount: 6, expected: 0},
+		{formula: "max(2, -(runningJobCount) + maxReplicaCount)", queueLength: 10, maxScale: 10, maxReplicaCount: 10, runningJobCount: 9, expected: 2},
+		// capped to maxReplicaCount
+		{formula: "queueLength", queueLength: 500, maxScale: 10, maxReplicaCount: 10, expected: 10},
+		{formula: "queueLength / (maxReplicaCount - 10)", queueLength: 500, maxScale: 10, maxReplicaCount: 11, expected: 11},
+		// can't be evaluated, maxScale of the triggers
+		{formula: "queueLength / runningJobCount", queueLength: 10, maxScale: 10, maxReplicaCount: 20, expected: 10},
+		{formula: "unknown(queueLength)", queueLength: 10, maxScale: 10, maxReplicaCount: 20, expected: 10},
+	}
+
+	for _, test := range tests {
+		scaledJob := getMockScaledJobWithDefault()
+		scaledJob.Spec.MaxReplicaCount = int32Ptr(test.maxReplicaCount)
+		scaledJob.Spec.ScalingModifiers = &kedav1alpha1.ScalingModifiers{MaxFormula: test.formula}
+		actual, _ := getMaxScaleByFormula(logger, scaledJob, test.queueLength, test.maxScale, test.runningJobCount)
+		assert.Equal(t, test.expected, actual, "formula %q", test.formula)
+	}
+}
+
+func TestRequestJobScaleRecordsFormulaEval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.MaxReplicaCount = int32Ptr(10)
+	scaledJob.Spec.ScalingModifiers = &kedav1alpha1.ScalingModifiers{MaxFormula: "min(maxReplicaCount, ceil(queueLength / 20))"}
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 50, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(createdJobs))
+	assert.Equal(t, &kedav1alpha1.FormulaEvaluation{
+		Formula:         "min(maxReplicaCount, ceil(queueLength / 20))",
+		QueueLength:     50,
+		MaxReplicaCount: 10,
+		RunningJobCount: 0,
+		Result:          3,
+	}, scaledJob.Status.LastFormulaEval)
+
+	// the failed evaluation falls back to maxReplicaCount
+	scaledJob.Spec.ScalingModifiers.MaxFormula = "queueLength / runningJobCount"
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 50, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, &kedav1alpha1.FormulaEvaluation{
+		Formula:         "queueLength / runningJobCount",
+		QueueLength:     50,
+		MaxReplicaCount: 10,
+		RunningJobCount: 0,
+		Result:          10,
+		Error:           "division by zero",
+	}, scaledJob.Status.LastFormulaEval)
+
+	// the formula was removed
+	scaledJob.Spec.ScalingModifiers = nil
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Nil(t, scaledJob.Status.LastFormulaEval)
+}
+
+func TestSetLastFormulaEvalUnchanged(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	recorded := &kedav1alpha1.FormulaEvaluation{Formula: "queueLength", QueueLength: 5, MaxReplicaCount: 10, Result: 5}
+	scaledJob.Status.LastFormulaEval = recorded
+
+	// the recorded evaluation is kept
+	setLastFormulaEval(scaledJob, &kedav1alpha1.FormulaEvaluation{Formula: "queueLength", QueueLength: 5, MaxReplicaCount: 10, Result: 5})
+	assert.True(t, recorded == scaledJob.Status.LastFormulaEval)
+
+	setLastFormulaEval(scaledJob, nil)
+	assert.Nil(t, scaledJob.Status.LastFormulaEval)
+}
+
+func TestValidateMaxFormula(t *testing.T) {
+	assert.Nil(t, ValidateMaxFormula("min(maxReplicaCount, ceil(queueLength / 20))"))
+	assert.Nil(t, ValidateMaxFormula("floor(queueLength * 0.5) + runningJobCount"))
+	// the formula is not evaluated, the division by zero only happens for a maxReplicaCount of 1
+	assert.Nil(t, ValidateMaxFormula("queueLength / (maxReplicaCount - 1)"))
+	assert.NotNil(t, ValidateMaxFormula("min(maxReplicaCount,"))
+	assert.NotNil(t, ValidateMaxFormula("pendingItems / 2"))
+	assert.NotNil(t, ValidateMaxFormula("os.Exit(1)"))
+	assert.NotNil(t, ValidateMaxFormula("queueLength % 2"))
+	assert.NotNil(t, ValidateMaxFormula(`"10"`))
+	assert.NotNil(t, ValidateMaxFormula("ceil(1, 2)"))
+	assert.NotNil(t, ValidateMaxFormula("min()"))
+	assert.NotNil(t, ValidateMaxFormula("queueLength / ceil(2, 3)"))
+	assert.NotNil(t, ValidateMaxFormula("-unknown"))
+}
@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+var (
+	scaledJobLabels     = []string{"namespace", "scaledJob"}
+	scaledFromZeroTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "scaled_from_zero_total",
+			Help:      "Number of times Jobs were created while no Job of the ScaledJob was running",
+		},
+		scaledJobLabels,
+	)
+	scaleGap = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "scale_gap",
+			Help:      "Number of desired Jobs within maxScale that are not running, a persistently positive gap indicates the cluster can't keep up",
+		},
+		scaledJobLabels,
+	)
+	cleanupDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "cleanup_duration_seconds",
+			Help:      "Duration of the cleanup of the finished Jobs of the ScaledJob, including the deletions exceeding the history limits",
+		},
+		scaledJobLabels,
+	)
+	invalidScaleInputsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "invalid_scale_inputs_total",
+			Help:      "Number of scaling requests skipped as scaleTo or maxScale was negative",
+		},
+		scaledJobLabels,
+	)
+	historyRetained = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "history_retained",
+			Help:      "Number of finished Jobs of the ScaledJob retained after the cleanup by category, completed or failed",
+		},
+		append(scaledJobLabels, "category"),
+	)
+	backpressureSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "backpressure_seconds",
+			Help:      "Delay added to the pollingInterval of the ScaledJob as the creations or deletions of its Jobs keep failing or are slow",
+		},
+		scaledJobLabels,
+	)
+	backlogCritical = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "backlog_critical",
+			Help:      "1 if the desired Jobs exceed the running Jobs of the ScaledJob by more than its backlogCriticalThreshold, 0 otherwise",
+		},
+		scaledJobLabels,
+	)
+	pollToCreateDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "keda",
+			Subsystem: "scaledjob",
+			Name:      "poll_to_create_duration_seconds",
+			Help:      "Duration from the scaling loop starting to compute the metrics of the ScaledJob to the creation of its Jobs finishing",
+		},
+		scaledJobLabels,
+	)
+)
+
+const (
+	historyCategoryCompleted = "completed"
+	historyCategoryFailed    = "failed"
+)
+
+func init() {
+	metrics.Registry.MustRegister(scaledFromZeroTotal)
+	metrics.Registry.MustRegister(scaleGap)
+	metrics.Registry.MustRegister(cleanupDuration)
+	metrics.Registry.MustRegister(invalidScaleInputsTotal)
+	metrics.Registry.MustRegister(historyRetained)
+	metrics.Registry.MustRegister(backpressureSeconds)
+	metrics.Registry.MustRegister(backlogCritical)
+	metrics.Registry.MustRegister(pollToCreateDuration)
+}
+
+// setHistoryRetained records the number of the completed and failed Jobs retained after the cleanup
+func setHistoryRetained(scaledJob *kedav1alpha1.ScaledJob, completed int, failed int) {
+	historyRetained.WithLabelValues(scaledJob.Namespace, scaledJob.Name, historyCategoryCompleted).Set(float64(completed))
+	historyRetained.WithLabelValues(scaledJob.Namespace, scaledJob.Name, historyCategoryFailed).Set(float64(failed))
+}
+
+// deleteHistoryRetained removes the gauges of the retained Jobs of the ScaledJob
+func deleteHistoryRetained(scaledJob *kedav1alpha1.ScaledJob) {
+	historyRetained.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name, historyCategoryCompleted)
+	historyRetained.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name, historyCategoryFailed)
+}
+
+// getScaleGap returns the difference between the desired Jobs capped by maxScale and the running Jobs
+func getScaleGap(scaleTo int64, maxScale int64, runningJobCount int64) int64 {
+	desired := scaleTo
+	if desired > maxScale {
+		desired = maxScale
+	}
+	return desired - runningJobCount
+}
+
+// setBacklogCritical records whether the backlog of the ScaledJob, scaleTo minus the running Jobs, exceeds
+// its backlogCriticalThreshold, the gauge is removed while no threshold is set
+func setBacklogCritical(scaledJob *kedav1alpha1.ScaledJob, scaleTo int64, runningJobCount int64) {
+	threshold := scaledJob.Spec.BacklogCriticalThreshold
+	if threshold == nil {
+		backlogCritical.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+		return
+	}
+
+	critical := float64(0)
+	if scaleTo-runningJobCount > int64(*threshold) {
+		critical = 1
+	}
+	backlogCritical.WithLabelValues(scaledJob.Namespace, scaledJob.Name).Set(critical)
+}
+
+// observePollToCreateDuration records the duration from scaledAt, the time the scaling loop started to compute
+// the metrics, to now, a zero scaledAt is not recorded
+func observePollToCreateDuration(scaledJob *kedav1alpha1.ScaledJob, scaledAt time.Time) {
+	if scaledAt.IsZero() {
+		return
+	}
+	pollToCreateDuration.WithLabelValues(scaledJob.Namespace, scaledJob.Name).Observe(time.Since(scaledAt).Seconds())
+}
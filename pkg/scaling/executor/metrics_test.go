@@ -0,0 +1,268 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestGetScaleGap(t *testing.T) {
+	// all the desired jobs are running
+	assert.Equal(t, int64(0), getScaleGap(5, 10, 5))
+	// the cluster doesn't keep up
+	assert.Equal(t, int64(3), getScaleGap(5, 10, 2))
+	// the desired jobs are capped by maxScale
+	assert.Equal(t, int64(2), getScaleGap(20, 10, 8))
+	// more jobs running than desired
+	assert.Equal(t, int64(-4), getScaleGap(1, 10, 5))
+}
+
+func TestCleanUpDurationObserved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(2, 2)
+	scaledJob.Name = "cleanup-duration"
+	var actualDeletedJobName = make(map[string]string)
+	scaleExecutor := getMockScaleExecutor(getMockClient(t, ctrl, &[]mockJobParameter{}, &actualDeletedJobName))
+
+	assert.Equal(t, uint64(0), getCleanupDurationSampleCount(t, scaledJob.Namespace, scaledJob.Name))
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), getCleanupDurationSampleCount(t, scaledJob.Namespace, scaledJob.Name))
+}
+
+func TestRequestJobScalePollToCreateDuration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "poll-to-create-duration"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	defer pollToCreateDuration.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// the scaling loop computed the metrics 2 seconds before the jobs were created
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 2, 10, 0, time.Now().Add(-2*time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	count, sum := getPollToCreateDuration(t, scaledJob.Namespace, scaledJob.Name)
+	assert.Equal(t, uint64(1), count)
+	assert.GreaterOrEqual(t, sum, float64(2))
+
+	// no jobs are created while inactive
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 10, 0, time.Now())
+	assert.Nil(t, err)
+	count, _ = getPollToCreateDuration(t, scaledJob.Namespace, scaledJob.Name)
+	assert.Equal(t, uint64(1), count)
+
+	// the time of the poll is unknown
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	count, _ = getPollToCreateDuration(t, scaledJob.Namespace, scaledJob.Name)
+	assert.Equal(t, uint64(1), count)
+}
+
+func TestCleanUpHistoryRetained(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobs := []mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:31:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:34:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success3", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success4", CompletionTime: "2020-07-29T15:38:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "fail1", CompletionTime: "2020-07-29T15:32:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail3", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+	}
+
+	// the per-category limits
+	scaledJob := getMockScaledJob(3, 2)
+	scaledJob.Name = "history-retained"
+	var actualDeletedJobName = make(map[string]string)
+	scaleExecutor := getMockScaleExecutor(getMockClient(t, ctrl, &jobs, &actualDeletedJobName))
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(actualDeletedJobName))
+	assert.Equal(t, float64(3), getHistoryRetained(t, scaledJob.Namespace, scaledJob.Name, historyCategoryCompleted))
+	assert.Equal(t, float64(2), getHistoryRetained(t, scaledJob.Namespace, scaledJob.Name, historyCategoryFailed))
+
+	// the total limit keeps success4 and fail3
+	totalJobsHistoryLimit := int32(2)
+	scaledJob.Spec.TotalJobsHistoryLimit = &totalJobsHistoryLimit
+	actualDeletedJobName = make(map[string]string)
+	scaleExecutor = getMockScaleExecutor(getMockClient(t, ctrl, &jobs, &actualDeletedJobName))
+
+	err = scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(actualDeletedJobName))
+	assert.Equal(t, float64(1), getHistoryRetained(t, scaledJob.Namespace, scaledJob.Name, historyCategoryCompleted))
+	assert.Equal(t, float64(1), getHistoryRetained(t, scaledJob.Namespace, scaledJob.Name, historyCategoryFailed))
+
+	deleteHistoryRetained(scaledJob)
+}
+
+func TestRequestJobScaleBacklogCritical(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "backlog-critical"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	threshold := int32(3)
+	scaledJob.Spec.BacklogCriticalThreshold = &threshold
+	defer backlogCritical.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	var createdJobs []*batchv1.Job
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	tests := []struct {
+		scaleTo          int64
+		expectedCritical float64
+	}{
+		// no job is running, the backlog is scaleTo
+		{scaleTo: 3, expectedCritical: 0},
+		{scaleTo: 4, expectedCritical: 1},
+		{scaleTo: 2, expectedCritical: 0},
+	}
+	for _, test := range tests {
+		_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, test.scaleTo, 10, 0, time.Time{})
+		assert.Nil(t, err)
+		critical, ok := getBacklogCritical(t, scaledJob.Namespace, scaledJob.Name)
+		assert.True(t, ok)
+		assert.Equal(t, test.expectedCritical, critical, "scaleTo %d", test.scaleTo)
+	}
+
+	// the gauge is removed without a threshold
+	scaledJob.Spec.BacklogCriticalThreshold = nil
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 4, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	_, ok := getBacklogCritical(t, scaledJob.Namespace, scaledJob.Name)
+	assert.False(t, ok)
+}
+
+func TestSetBacklogCriticalSubtractsRunningJobs(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "backlog-critical-running"
+	threshold := int32(5)
+	scaledJob.Spec.BacklogCriticalThreshold = &threshold
+	defer backlogCritical.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+
+	setBacklogCritical(scaledJob, 20, 14)
+	critical, _ := getBacklogCritical(t, scaledJob.Namespace, scaledJob.Name)
+	assert.Equal(t, float64(1), critical)
+
+	setBacklogCritical(scaledJob, 20, 15)
+	critical, _ = getBacklogCritical(t, scaledJob.Namespace, scaledJob.Name)
+	assert.Equal(t, float64(0), critical)
+}
+
+// getBacklogCritical returns the backlog_critical gauge of the ScaledJob and whether it exists
+func getBacklogCritical(t *testing.T, namespace string, name string) (float64, bool) {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "keda_scaledjob_backlog_critical" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["namespace"] == namespace && labels["scaledJob"] == name {
+				return metric.GetGauge().GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// getHistoryRetained returns the number of the retained Jobs of the category of the ScaledJob
+func getHistoryRetained(t *testing.T, namespace string, name string, category string) float64 {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "keda_scaledjob_history_retained" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["namespace"] == namespace && labels["scaledJob"] == name && labels["category"] == category {
+				return metric.GetGauge().GetValue()
+			}
+		}
+	}
+	t.Fatalf("No history_retained gauge for category %s", category)
+	return 0
+}
+
+// getPollToCreateDuration returns the number and the sum of the observations of the poll to create duration of the ScaledJob
+func getPollToCreateDuration(t *testing.T, namespace string, name string) (uint64, float64) {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "keda_scaledjob_poll_to_create_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["namespace"] == namespace && labels["scaledJob"] == name {
+				return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	return 0, 0
+}
+
+// getCleanupDurationSampleCount returns the number of observations of the cleanup duration of the ScaledJob
+func getCleanupDurationSampleCount(t *testing.T, namespace string, name string) uint64 {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "keda_scaledjob_cleanup_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["namespace"] == namespace && labels["scaledJob"] == name {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
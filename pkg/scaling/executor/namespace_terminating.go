@@ -0,0 +1,21 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// isNamespaceTerminating returns true if the namespace is being deleted, the namespace is assumed
+// to be active if it can't be read
+func (e *scaleExecutor) isNamespaceTerminating(ctx context.Context, logger logr.Logger, namespace string) bool {
+	ns := &corev1.Namespace{}
+	err := e.client.Get(ctx, types.NamespacedName{Name: namespace}, ns)
+	if err != nil {
+		logger.V(1).Info("Failed to get the Namespace, assuming it is active", "error", err.Error())
+		return false
+	}
+	return ns.Status.Phase == corev1.NamespaceTerminating
+}
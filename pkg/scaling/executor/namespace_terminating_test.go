@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestRequestJobScaleInTerminatingNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Namespace = "leaving"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Status.Conditions = *kedav1alpha1.GetInitializedConditions()
+
+	phase := corev1.NamespaceTerminating
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ runtimeclient.ObjectKey, obj runtime.Object) {
+		if ns, ok := obj.(*corev1.Namespace); ok {
+			ns.Status.Phase = phase
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	var createdJobs []*batchv1.Job
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// no job is created in the terminating namespace
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 3, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(createdJobs))
+	condition := scaledJob.Status.Conditions.GetNamespaceTerminatingCondition()
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "NamespaceTerminating", condition.Reason)
+
+	// the namespace deletion was finalized and it was recreated
+	phase = corev1.NamespaceActive
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 3, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(createdJobs))
+	condition = scaledJob.Status.Conditions.GetNamespaceTerminatingCondition()
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "NamespaceActive", condition.Reason)
+}
+
+func TestSetNamespaceTerminatingConditionActive(t *testing.T) {
+	// the Condition is only added once the namespace is terminating
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Status.Conditions = *kedav1alpha1.GetInitializedConditions()
+	setNamespaceTerminatingCondition(scaledJob, false)
+
+	assert.Equal(t, kedav1alpha1.ConditionType(""), scaledJob.Status.Conditions.GetNamespaceTerminatingCondition().Type)
+}
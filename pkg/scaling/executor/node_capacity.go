@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// MaxJobsPerNodeAnnotation on a ScaledJob caps its maxScale to the value times the number of schedulable nodes
+	MaxJobsPerNodeAnnotation = "keda.sh/max-jobs-per-node"
+)
+
+// getMaxScaleByNodeCapacity returns maxScale capped by the MaxJobsPerNodeAnnotation of the ScaledJob times the number
+// of schedulable nodes, so the Jobs scale with the size of the cluster. maxScale is returned when the annotation
+// is not set or invalid, or the nodes can't be listed. The manager's client reads the nodes from the API server,
+// no informer caches them
+func (e *scaleExecutor) getMaxScaleByNodeCapacity(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, maxScale int64) int64 {
+	value, ok := scaledJob.Annotations[MaxJobsPerNodeAnnotation]
+	if !ok {
+		return maxScale
+	}
+	maxJobsPerNode, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || maxJobsPerNode < 0 {
+		logger.Info("Invalid max jobs per node, ignoring it", "annotation", MaxJobsPerNodeAnnotation, "value", value)
+		return maxScale
+	}
+
+	nodes := &corev1.NodeList{}
+	err = e.client.List(ctx, nodes)
+	if err != nil {
+		logger.Error(err, "Failed to list nodes, not capping maxScale by the node capacity")
+		return maxScale
+	}
+	nodeCount := int64(0)
+	for i := range nodes.Items {
+		if isNodeSchedulable(&nodes.Items[i]) {
+			nodeCount++
+		}
+	}
+
+	if capacity := maxJobsPerNode * nodeCount; capacity < maxScale {
+		logger.V(1).Info("Capped maxScale by the node capacity", "maxJobsPerNode", maxJobsPerNode, "schedulableNodes", nodeCount, "maxScale", capacity)
+		return capacity
+	}
+	return maxScale
+}
+
+// isNodeSchedulable returns true if the node is Ready and not cordoned
+func isNodeSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestRequestJobScaleWithMaxJobsPerNode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Annotations = map[string]string{MaxJobsPerNodeAnnotation: "2"}
+
+	// 3 schedulable nodes, a cordoned and a not ready node
+	nodes := []corev1.Node{getNode(true, false), getNode(true, false), getNode(true, false), getNode(true, true), getNode(false, false)}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		if n, ok := list.(*corev1.NodeList); ok {
+			n.Items = nodes
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 10, 0, time.Time{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(createdJobs))
+}
+
+func TestGetMaxScaleByNodeCapacityInvalidAnnotation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	// the nodes are not listed
+	scaleExecutor := getMockScaleExecutor(mock_client.NewMockClient(ctrl))
+
+	assert.Equal(t, int64(10), scaleExecutor.getMaxScaleByNodeCapacity(context.TODO(), scaleExecutor.logger, scaledJob, 10))
+
+	scaledJob.Annotations = map[string]string{MaxJobsPerNodeAnnotation: "two"}
+	assert.Equal(t, int64(10), scaleExecutor.getMaxScaleByNodeCapacity(context.TODO(), scaleExecutor.logger, scaledJob, 10))
+}
+
+func getNode(ready bool, unschedulable bool) corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Node{
+		Spec: corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
@@ -0,0 +1,6 @@
+package executor
+
+const (
+	// OperatorIDLabel is the label identifying the KEDA instance that created a Job
+	OperatorIDLabel = "keda.sh/operator-id"
+)
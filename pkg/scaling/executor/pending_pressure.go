@@ -0,0 +1,53 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// Number of the unscheduled pods of the Jobs of a ScaledJob with respectPendingPressure no more of its Jobs
+	// are created at
+	defaultPendingPodsThreshold = 100
+)
+
+// getMaxScaleByPendingPressure returns maxScale capped by the unscheduled pods of the Jobs of the ScaledJob below
+// the threshold, so the Jobs don't pile up in front of an overloaded scheduler. Only the pods of its own Jobs are listed,
+// by their labels, including the ones spread across jobNamespaces. If the pods can't be listed, no Jobs are created
+func (e *scaleExecutor) getMaxScaleByPendingPressure(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, maxScale int64) int64 {
+	threshold := e.config.PendingPodsThreshold
+	if !scaledJob.Spec.RespectPendingPressure || threshold <= 0 {
+		return maxScale
+	}
+
+	pods, err := e.listJobPods(ctx, scaledJob)
+	if err != nil {
+		logger.Error(err, "Failed to list the pods, not creating Jobs as the pending pressure is unknown")
+		return 0
+	}
+	pending := int64(0)
+	for i := range pods.Items {
+		if isPodUnscheduled(&pods.Items[i]) {
+			pending++
+		}
+	}
+
+	budget := threshold - pending
+	if budget < 0 {
+		budget = 0
+	}
+	if budget < maxScale {
+		logger.Info("Number of Jobs throttled by the pending pressure", "pendingPods", pending, "threshold", threshold, "maxScale", budget)
+		return budget
+	}
+	return maxScale
+}
+
+// isPodUnscheduled returns true if the pod is Pending and not bound to a node yet
+func isPodUnscheduled(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodPending && pod.Spec.NodeName == ""
+}
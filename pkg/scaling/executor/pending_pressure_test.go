@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestRequestJobScaleWithPendingPressure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.RespectPendingPressure = true
+
+	// 3 unscheduled pods, the pods bound to a node or running don't count
+	pods := []corev1.Pod{
+		getPod(corev1.PodPending, ""),
+		getPod(corev1.PodPending, ""),
+		getPod(corev1.PodPending, ""),
+		getPod(corev1.PodPending, "node-1"),
+		getPod(corev1.PodRunning, "node-1"),
+	}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		if p, ok := list.(*corev1.PodList); ok {
+			p.Items = pods
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.PendingPodsThreshold = 5
+
+	requeueAfter, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 10, 10, 0, time.Time{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	assert.Equal(t, getRequeueInterval(scaledJob), requeueAfter)
+}
+
+func TestGetMaxScaleByPendingPressure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.RespectPendingPressure = true
+
+	var listOptions runtimeclient.ListOptions
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, opts ...runtimeclient.ListOption) {
+		listOptions.ApplyOptions(opts)
+		list.(*corev1.PodList).Items = []corev1.Pod{
+			getPod(corev1.PodPending, ""),
+			getPod(corev1.PodPending, ""),
+			getPod(corev1.PodPending, ""),
+		}
+	}).
+		Return(nil)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.PendingPodsThreshold = 2
+
+	// the pending pressure exceeds the threshold
+	assert.Equal(t, int64(0), scaleExecutor.getMaxScaleByPendingPressure(context.TODO(), scaleExecutor.logger, scaledJob, 10))
+	// only the pods of the jobs of the ScaledJob are listed
+	assert.Equal(t, "scaledjob="+scaledJob.Name, listOptions.LabelSelector.String())
+}
+
+func TestGetMaxScaleByPendingPressureListError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.RespectPendingPressure = true
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("forbidden"))
+	scaleExecutor := getMockScaleExecutor(client)
+
+	assert.Equal(t, int64(0), scaleExecutor.getMaxScaleByPendingPressure(context.TODO(), scaleExecutor.logger, scaledJob, 10))
+}
+
+func TestGetMaxScaleByPendingPressureDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the pods are not listed
+	scaleExecutor := getMockScaleExecutor(mock_client.NewMockClient(ctrl))
+
+	scaledJob := getMockScaledJobWithDefault()
+	assert.Equal(t, int64(10), scaleExecutor.getMaxScaleByPendingPressure(context.TODO(), scaleExecutor.logger, scaledJob, 10))
+
+	scaledJob.Spec.RespectPendingPressure = true
+	scaleExecutor.config.PendingPodsThreshold = 0
+	assert.Equal(t, int64(10), scaleExecutor.getMaxScaleByPendingPressure(context.TODO(), scaleExecutor.logger, scaledJob, 10))
+}
+
+func getPod(phase corev1.PodPhase, nodeName string) corev1.Pod {
+	return corev1.Pod{
+		Spec:   corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
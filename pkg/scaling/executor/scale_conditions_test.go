@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestRequestJobScaleActiveAndPausedConditions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Status.Conditions = *kedav1alpha1.GetInitializedConditions()
+
+	// the annotation is read from the latest ScaledJob
+	paused := false
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ runtimeclient.ObjectKey, obj runtime.Object) {
+		if latest, ok := obj.(*kedav1alpha1.ScaledJob); ok && paused {
+			latest.Annotations = map[string]string{PausedAnnotation: "true"}
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	var createdJobs []*batchv1.Job
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	active := scaledJob.Status.Conditions.GetActiveCondition()
+	assert.True(t, active.IsTrue())
+	assert.Equal(t, "ScalerActive", active.Reason)
+	assert.NotNil(t, active.LastTransitionTime)
+	// the Paused Condition is added once the ScaledJob is paused
+	assert.Equal(t, kedav1alpha1.ConditionType(""), scaledJob.Status.Conditions.GetPausedCondition().Type)
+	assert.Equal(t, 1, len(createdJobs))
+
+	// Active -> Paused, no jobs are created
+	paused = true
+	activeSince := metav1.NewTime(time.Now().Add(-time.Minute))
+	setLastTransitionTime(scaledJob.Status.Conditions, kedav1alpha1.ConditionActive, activeSince)
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	active = scaledJob.Status.Conditions.GetActiveCondition()
+	assert.True(t, active.IsFalse())
+	assert.Equal(t, "ScaledJobPaused", active.Reason)
+	assert.True(t, activeSince.Before(active.LastTransitionTime))
+	pausedCondition := scaledJob.Status.Conditions.GetPausedCondition()
+	assert.True(t, pausedCondition.IsTrue())
+	assert.Equal(t, "ScaledJobPaused", pausedCondition.Reason)
+	assert.NotEqual(t, "", pausedCondition.Message)
+	assert.NotNil(t, pausedCondition.LastTransitionTime)
+	assert.Equal(t, 1, len(createdJobs))
+
+	// the transition time is kept while the status doesn't change
+	pausedSince := metav1.NewTime(time.Now().Add(-time.Minute))
+	setLastTransitionTime(scaledJob.Status.Conditions, kedav1alpha1.ConditionPaused, pausedSince)
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, pausedSince, *scaledJob.Status.Conditions.GetPausedCondition().LastTransitionTime)
+
+	// Paused -> Active
+	paused = false
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	active = scaledJob.Status.Conditions.GetActiveCondition()
+	assert.True(t, active.IsTrue())
+	pausedCondition = scaledJob.Status.Conditions.GetPausedCondition()
+	assert.True(t, pausedCondition.IsFalse())
+	assert.True(t, pausedSince.Before(pausedCondition.LastTransitionTime))
+	assert.Equal(t, 2, len(createdJobs))
+}
+
+func TestRequestJobScaleDegradedCondition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &[]*batchv1.Job{})
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// the jobs can't be created without a jobTargetRef
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.NotNil(t, err)
+	degraded := scaledJob.Status.Conditions.GetDegradedCondition()
+	assert.True(t, degraded.IsTrue())
+	assert.Equal(t, err.Error(), degraded.Message)
+
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	degraded = scaledJob.Status.Conditions.GetDegradedCondition()
+	assert.True(t, degraded.IsFalse())
+}
+
+func setLastTransitionTime(conditions kedav1alpha1.Conditions, conditionType kedav1alpha1.ConditionType, transitionTime metav1.Time) {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			conditions[i].LastTransitionTime = &transitionTime
+		}
+	}
+}
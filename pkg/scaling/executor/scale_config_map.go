@@ -0,0 +1,196 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// ScaleConfigMapVolume is the name of the volume of the ConfigMap with the scale context of the batch
+	ScaleConfigMapVolume = "keda-scale-context"
+	// ScaleConfigMapMountPath is the path the ConfigMap with the scale context of the batch is mounted at
+	ScaleConfigMapMountPath = "/etc/keda/scale"
+	// BatchSizeAnnotation holds the number of the Jobs created together with a Job mounting the scale ConfigMap
+	BatchSizeAnnotation = "keda.sh/batch-size"
+)
+
+// createScaleConfigMap creates the ConfigMap with the scale context of the batch of Jobs, owned by the ScaledJob,
+// and returns its name
+func (e *scaleExecutor) createScaleConfigMap(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, batchID string, batchSize int64, completions *int32) (string, error) {
+	configMap := e.newScaleConfigMap(logger, scaledJob, fmt.Sprintf("%s-scale-%s", scaledJob.GetName(), batchID), batchID, batchSize, completions)
+	if err := e.client.Create(context.TODO(), configMap); err != nil {
+		logger.Error(err, "Failed to create the scale ConfigMap", "configMap.Name", configMap.GetName())
+		return "", err
+	}
+	logger.V(1).Info("Created the scale ConfigMap", "configMap.Name", configMap.GetName())
+	return configMap.GetName(), nil
+}
+
+func (e *scaleExecutor) newScaleConfigMap(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, name string, batchID string, batchSize int64, completions *int32) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: scaledJob.GetNamespace(),
+			Labels: map[string]string{
+				"scaledjob":  GetScaledJobLabelValue(scaledJob),
+				BatchIDLabel: batchID,
+			},
+		},
+		Data: map[string]string{
+			"batchId":   batchID,
+			"batchSize": strconv.FormatInt(batchSize, 10),
+		},
+	}
+	if completions != nil {
+		configMap.Data["completions"] = strconv.Itoa(int(*completions))
+	}
+	if e.config.OperatorID != "" {
+		configMap.Labels[OperatorIDLabel] = e.config.OperatorID
+	}
+
+	if err := controllerutil.SetControllerReference(scaledJob, configMap, e.reconcilerScheme); err != nil {
+		logger.Error(err, "Failed to set ScaledJob as the owner of the scale ConfigMap")
+	}
+	return configMap
+}
+
+// injectScaleConfigMap mounts the ConfigMap with the scale context of the batch into the containers of the Job,
+// the size of the batch is kept in an annotation so the ConfigMap can be recreated
+func injectScaleConfigMap(job *batchv1.Job, configMapName string, batchSize int64) {
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[BatchSizeAnnotation] = strconv.FormatInt(batchSize, 10)
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: ScaleConfigMapVolume,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+			},
+		},
+	})
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      ScaleConfigMapVolume,
+			MountPath: ScaleConfigMapMountPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// deleteScaleConfigMaps deletes the scale ConfigMaps of the batches whose Jobs were all deleted.
+// ConfigMaps created since the jobs were listed are skipped, their Jobs may be missing from the list.
+func (e *scaleExecutor) deleteScaleConfigMaps(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, jobs []batchv1.Job, jobsListedAt time.Time) error {
+	batchIDs := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		if batchID, ok := j.Labels[BatchIDLabel]; ok {
+			batchIDs[batchID] = true
+		}
+	}
+
+	requirement, err := labels.NewRequirement(BatchIDLabel, selection.Exists, nil)
+	if err != nil {
+		return err
+	}
+	selector := labels.SelectorFromSet(labels.Set{"scaledjob": GetScaledJobLabelValue(scaledJob)}).Add(*requirement)
+	configMaps := &corev1.ConfigMapList{}
+	err = e.client.List(context.TODO(), configMaps, client.InNamespace(scaledJob.GetNamespace()), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		logger.Error(err, "Can not get list of scale ConfigMaps")
+		return err
+	}
+
+	// the creation timestamp has a precision of seconds
+	listedAt := jobsListedAt.Truncate(time.Second)
+	for _, configMap := range configMaps.Items {
+		if batchIDs[configMap.Labels[BatchIDLabel]] || !configMap.CreationTimestamp.Time.Before(listedAt) {
+			continue
+		}
+
+		err = e.client.Delete(context.TODO(), configMap.DeepCopyObject())
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Remove the scale ConfigMap of a finished batch", "configMap.Name", configMap.GetName())
+	}
+	return nil
+}
+
+// recreateScaleConfigMaps recreates the scale ConfigMaps that were deleted, eg. manually, while Jobs of their batch
+// are still running, the pods of the Jobs couldn't start or restart without them
+func (e *scaleExecutor) recreateScaleConfigMaps(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, jobs []batchv1.Job) error {
+	// the ConfigMaps by their name, with a running Job of their batch
+	batches := map[string]*batchv1.Job{}
+	for i := range jobs {
+		job := &jobs[i]
+		name := getScaleConfigMapName(job)
+		if name == "" || IsJobFinished(job) {
+			continue
+		}
+		if _, ok := batches[name]; !ok {
+			batches[name] = job
+		}
+	}
+
+	for name, job := range batches {
+		err := e.client.Get(context.TODO(), types.NamespacedName{Namespace: scaledJob.GetNamespace(), Name: name}, &corev1.ConfigMap{})
+		if err == nil {
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		configMap := e.newScaleConfigMap(logger, scaledJob, name, job.Labels[BatchIDLabel], getJobBatchSize(job, jobs), job.Spec.Completions)
+		err = e.client.Create(context.TODO(), configMap)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		logger.Info("Recreated the missing scale ConfigMap of a running batch", "configMap.Name", name)
+	}
+	return nil
+}
+
+// getScaleConfigMapName returns the name of the scale ConfigMap mounted by the Job, if any
+func getScaleConfigMapName(job *batchv1.Job) string {
+	for _, volume := range job.Spec.Template.Spec.Volumes {
+		if volume.Name == ScaleConfigMapVolume && volume.ConfigMap != nil {
+			return volume.ConfigMap.Name
+		}
+	}
+	return ""
+}
+
+// getJobBatchSize returns the size of the batch of the Job from its annotation,
+// the Jobs of the batch still existing are counted for the Jobs created without it
+func getJobBatchSize(job *batchv1.Job, jobs []batchv1.Job) int64 {
+	if value, ok := job.Annotations[BatchSizeAnnotation]; ok {
+		if batchSize, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return batchSize
+		}
+	}
+
+	var batchSize int64
+	for _, j := range jobs {
+		if j.Labels[BatchIDLabel] == job.Labels[BatchIDLabel] {
+			batchSize++
+		}
+	}
+	return batchSize
+}
@@ -0,0 +1,179 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestCreateJobsWithScaleConfigMap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.EmitScaleConfigMap = true
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "worker"}, {Name: "sidecar"}},
+			},
+		},
+	}
+
+	var createdJobs []*batchv1.Job
+	var createdConfigMaps []*corev1.ConfigMap
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.CreateOption) {
+		switch o := obj.(type) {
+		case *batchv1.Job:
+			createdJobs = append(createdJobs, o)
+		case *corev1.ConfigMap:
+			createdConfigMaps = append(createdConfigMaps, o)
+		}
+	}).
+		Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 3, 10, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, 1, len(createdConfigMaps))
+	configMap := createdConfigMaps[0]
+	batchID := configMap.Labels[BatchIDLabel]
+	assert.NotEqual(t, "", batchID)
+	assert.Equal(t, "3", configMap.Data["batchSize"])
+	assert.Equal(t, batchID, configMap.Data["batchId"])
+	assert.Equal(t, scaledJob.Name, configMap.Labels["scaledjob"])
+
+	assert.Equal(t, 3, len(createdJobs))
+	for _, job := range createdJobs {
+		assert.Equal(t, batchID, job.Labels[BatchIDLabel])
+		assert.Equal(t, "3", job.Annotations[BatchSizeAnnotation])
+		podSpec := job.Spec.Template.Spec
+		assert.Equal(t, 1, len(podSpec.Volumes))
+		assert.Equal(t, configMap.Name, podSpec.Volumes[0].ConfigMap.Name)
+		for _, container := range podSpec.Containers {
+			assert.Equal(t, ScaleConfigMapMountPath, container.VolumeMounts[0].MountPath)
+		}
+	}
+}
+
+func TestDeleteScaleConfigMaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.EmitScaleConfigMap = true
+
+	var deletedConfigMapName = make(map[string]string)
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		c := list.(*corev1.ConfigMapList)
+		c.Items = []corev1.ConfigMap{
+			// the jobs of the batch still exist
+			{ObjectMeta: metav1.ObjectMeta{Name: "batch-a", Labels: map[string]string{BatchIDLabel: "a"}}},
+			// all the jobs of the batch were deleted
+			{ObjectMeta: metav1.ObjectMeta{Name: "batch-b", Labels: map[string]string{BatchIDLabel: "b"}}},
+			// created after the jobs were listed, its jobs may be missing from the list
+			{ObjectMeta: metav1.ObjectMeta{Name: "batch-c", CreationTimestamp: metav1.Now(), Labels: map[string]string{BatchIDLabel: "c"}}},
+		}
+	}).
+		Return(nil)
+	client.EXPECT().
+		Delete(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.DeleteOption) {
+		configMap := obj.(*corev1.ConfigMap)
+		deletedConfigMapName[configMap.Name] = configMap.Name
+	}).
+		Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	job := getRunningJob("name1", time.Now(), nil)
+	job.Labels = map[string]string{BatchIDLabel: "a"}
+
+	err := scaleExecutor.deleteScaleConfigMaps(scaleExecutor.logger, scaledJob, []batchv1.Job{job}, time.Now())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(deletedConfigMapName))
+	_, ok := deletedConfigMapName["batch-b"]
+	assert.True(t, ok)
+}
+
+func TestRecreateScaleConfigMaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Namespace = "default"
+	scaledJob.Spec.EmitScaleConfigMap = true
+
+	var createdConfigMaps []*corev1.ConfigMap
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key types.NamespacedName, _ runtime.Object) error {
+		// only the ConfigMap of the batch b still exists
+		if key.Name == "batch-b" {
+			return nil
+		}
+		return errors.NewNotFound(corev1.Resource("configmaps"), key.Name)
+	}).
+		AnyTimes()
+	client.EXPECT().
+		Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.CreateOption) {
+		createdConfigMaps = append(createdConfigMaps, obj.(*corev1.ConfigMap))
+	}).
+		Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	batchJob := func(job batchv1.Job, batchID string) batchv1.Job {
+		job.Labels = map[string]string{BatchIDLabel: batchID}
+		injectScaleConfigMap(&job, "batch-"+batchID, 3)
+		return job
+	}
+	completions := int32(2)
+	runningA := batchJob(getRunningJob("a1", time.Now(), nil), "a")
+	runningA.Spec.Completions = &completions
+	// a job of the batch created without the batch size annotation
+	runningD := batchJob(getRunningJob("d1", time.Now(), nil), "d")
+	delete(runningD.Annotations, BatchSizeAnnotation)
+	jobs := []batchv1.Job{
+		runningA,
+		batchJob(getRunningJob("a2", time.Now(), nil), "a"),
+		batchJob(getRunningJob("b1", time.Now(), nil), "b"),
+		// all the jobs of the batch c finished, its ConfigMap is deleted by the cleanup
+		batchJob(*getJob(t, "c1", "2020-10-20T22:16:56Z", batchv1.JobComplete), "c"),
+		runningD,
+		batchJob(*getJob(t, "d2", "2020-10-20T22:16:56Z", batchv1.JobComplete), "d"),
+		// a job without a scale ConfigMap
+		getRunningJob("e1", time.Now(), nil),
+	}
+
+	err := scaleExecutor.recreateScaleConfigMaps(scaleExecutor.logger, scaledJob, jobs)
+
+	assert.Nil(t, err)
+	recreated := map[string]*corev1.ConfigMap{}
+	for _, configMap := range createdConfigMaps {
+		recreated[configMap.Name] = configMap
+	}
+	assert.Equal(t, 2, len(recreated))
+	assert.Equal(t, map[string]string{"batchId": "a", "batchSize": "3", "completions": "2"}, recreated["batch-a"].Data)
+	assert.Equal(t, "default", recreated["batch-a"].Namespace)
+	assert.Equal(t, "a", recreated["batch-a"].Labels[BatchIDLabel])
+	assert.Equal(t, scaledJob.Name, recreated["batch-a"].Labels["scaledjob"])
+	// the jobs of the batch still existing are counted
+	assert.Equal(t, map[string]string{"batchId": "d", "batchSize": "2"}, recreated["batch-d"].Data)
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// DrainableAnnotation set to "true" on a Job by its workload marks the Job as idle,
+	// drainable Jobs are deleted first when the running Jobs exceed maxReplicaCount
+	DrainableAnnotation = "keda.sh/drainable"
+)
+
+// scaleDownJobs deletes excess running Jobs of the ScaledJob, once maxReplicaCount or a time window was reduced below the number of running Jobs
+func (e *scaleExecutor) scaleDownJobs(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, excess int64) error {
+	jobs, err := e.listJobs(context.TODO(), scaledJob)
+	if err != nil {
+		return err
+	}
+
+	runningJobs := []batchv1.Job{}
+	for _, job := range jobs.Items {
+		if job.Labels[ExcludeFromCountLabel] == "true" {
+			continue
+		}
+		if !IsJobFinished(&job) && !isJobSuspended(&job) {
+			runningJobs = append(runningJobs, job)
+		}
+	}
+
+	for _, j := range getScaleDownCandidates(runningJobs, excess) {
+		err = e.client.Delete(context.TODO(), j.DeepCopyObject(), client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Remove a running job exceeding maxReplicaCount", "job.Name", j.GetName(), "drainable", isJobDrainable(&j))
+	}
+	return nil
+}
+
+// getScaleDownCandidates returns up to excess running jobs to delete, the drainable jobs come first,
+// then the most recently started jobs, which lose the least work
+func getScaleDownCandidates(runningJobs []batchv1.Job, excess int64) []batchv1.Job {
+	candidates := append([]batchv1.Job{}, runningJobs...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		drainableI, drainableJ := isJobDrainable(&candidates[i]), isJobDrainable(&candidates[j])
+		if drainableI != drainableJ {
+			return drainableI
+		}
+		startI, startJ := candidates[i].Status.StartTime, candidates[j].Status.StartTime
+		if startI == nil || startJ == nil {
+			// the jobs that didn't start yet come first
+			return startI == nil && startJ != nil
+		}
+		return startJ.Before(startI)
+	})
+
+	if int64(len(candidates)) > excess {
+		candidates = candidates[:excess]
+	}
+	return candidates
+}
+
+// isJobDrainable returns true if the workload marked the Job as idle
+func isJobDrainable(j *batchv1.Job) bool {
+	return j.Annotations[DrainableAnnotation] == "true"
+}
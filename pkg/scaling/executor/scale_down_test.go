@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestRequestJobScaleDeletesDrainableJobsFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.EnforceMaxScale = true
+	scaledJob.Spec.MaxReplicaCount = int32Ptr(1)
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	now := time.Now()
+	jobs := []batchv1.Job{
+		getRunningJob("busy-old", now.Add(-time.Hour), nil),
+		getRunningJob("drainable-old", now.Add(-time.Hour), nil),
+		getRunningJob("busy-new", now.Add(-time.Minute), nil),
+		getRunningJob("drainable-new", now.Add(-time.Minute), nil),
+	}
+	jobs[1].Annotations = map[string]string{DrainableAnnotation: "true"}
+	jobs[3].Annotations = map[string]string{DrainableAnnotation: "true"}
+
+	var deletedJobNames []string
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		j := list.(*batchv1.JobList)
+		j.Items = jobs
+	}).
+		Return(nil).AnyTimes()
+	client.EXPECT().
+		Delete(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.DeleteOption) {
+		job := obj.(*batchv1.Job)
+		deletedJobNames = append(deletedJobNames, job.Name)
+	}).
+		Return(nil).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// maxReplicaCount was lowered to 1, 3 of the 4 running jobs are deleted
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 1, 0, time.Time{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"drainable-new", "drainable-old", "busy-new"}, deletedJobNames)
+}
+
+func TestRequestJobScaleKeepsJobsWhenQueueDrains(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.EnforceMaxScale = true
+	scaledJob.Spec.MaxReplicaCount = int32Ptr(5)
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	now := time.Now()
+	jobs := []batchv1.Job{
+		getRunningJob("running-1", now.Add(-time.Minute), nil),
+		getRunningJob("running-2", now.Add(-time.Minute), nil),
+		getRunningJob("running-3", now.Add(-time.Minute), nil),
+	}
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		j := list.(*batchv1.JobList)
+		j.Items = jobs
+	}).
+		Return(nil).AnyTimes()
+	client.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// the queue drained while the jobs are processing the last messages, none of them is deleted
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 0, 0, time.Time{})
+
+	assert.Nil(t, err)
+}
+
+func TestGetScaleDownCandidates(t *testing.T) {
+	now := time.Now()
+	notStarted := getRunningJob("not-started", now, nil)
+	notStarted.Status.StartTime = nil
+	drainable := getRunningJob("drainable", now.Add(-time.Hour), nil)
+	drainable.Annotations = map[string]string{DrainableAnnotation: "true"}
+	notDrainable := getRunningJob("not-drainable", now.Add(-time.Hour), nil)
+	notDrainable.Annotations = map[string]string{DrainableAnnotation: "false"}
+	jobs := []batchv1.Job{
+		notDrainable,
+		getRunningJob("new", now, nil),
+		notStarted,
+		drainable,
+	}
+
+	candidates := getScaleDownCandidates(jobs, 3)
+
+	assert.Equal(t, 3, len(candidates))
+	assert.Equal(t, "drainable", candidates[0].Name)
+	assert.Equal(t, "not-started", candidates[1].Name)
+	assert.Equal(t, "new", candidates[2].Name)
+	// the input is left unchanged
+	assert.Equal(t, "not-drainable", jobs[0].Name)
+}
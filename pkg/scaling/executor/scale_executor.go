@@ -3,11 +3,14 @@ package executor
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/scale"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -21,25 +24,67 @@ const (
 
 // ScaleExecutor contains methods RequestJobScale and RequestScale
 type ScaleExecutor interface {
-	RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64)
+	JobScaleExecutor
 	RequestScale(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, isActive bool)
 }
 
+// JobScaleExecutor contains methods RequestJobScale, FinalizeScaledJob and CleanUpNow, it allows alternative implementations
+// of the ScaledJob scaling (eg. creating other workload types than Jobs)
+type JobScaleExecutor interface {
+	RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64, backlogAge time.Duration, scaledAt time.Time) (time.Duration, error)
+	FinalizeScaledJob(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error
+	CleanUpNow(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error
+	RestoreJobLabels(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error
+}
+
+// errNilReconcilerScheme is returned instead of creating Jobs when the executor was created without a scheme,
+// the scheme is needed to set the ScaledJob as the owner of its Jobs
+var errNilReconcilerScheme = fmt.Errorf("scale executor has no scheme, Jobs can't be owned by their ScaledJob")
+
+// jobFinalizerFunc removes resources that were created for the Jobs of a ScaledJob being deleted
+type jobFinalizerFunc func(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error
+
 type scaleExecutor struct {
 	client           client.Client
 	scaleClient      *scale.ScalesGetter
 	reconcilerScheme *runtime.Scheme
 	logger           logr.Logger
+	recorder         record.EventRecorder
+	semaphore        JobSemaphore
+	config           Config
+	// jobFinalizers are run in order when a ScaledJob is being deleted
+	jobFinalizers []jobFinalizerFunc
 }
 
-// NewScaleExecutor creates a ScaleExecutor object
-func NewScaleExecutor(client client.Client, scaleClient *scale.ScalesGetter, reconcilerScheme *runtime.Scheme) ScaleExecutor {
-	return &scaleExecutor{
+// NewScaleExecutor creates a ScaleExecutor object, the client is expected to be the manager's client
+// reading from the shared informer cache, Jobs are listed on every polling interval.
+// The Jobs of the ScaledJobs are scaled with the operator-level config
+func NewScaleExecutor(client client.Client, scaleClient *scale.ScalesGetter, reconcilerScheme *runtime.Scheme, recorder record.EventRecorder, config Config) ScaleExecutor {
+	e := &scaleExecutor{
 		client:           client,
 		scaleClient:      scaleClient,
 		reconcilerScheme: reconcilerScheme,
 		logger:           logf.Log.WithName("scaleexecutor"),
+		recorder:         recorder,
+		semaphore:        newLeaseJobSemaphore(client),
+		config:           config,
+	}
+	if reconcilerScheme == nil {
+		e.logger.Error(errNilReconcilerScheme, "Scale executor is misconfigured, no Jobs will be created")
+	}
+	e.jobFinalizers = []jobFinalizerFunc{
+		e.deleteJobs,
+		e.releaseAllSemaphorePermits,
+	}
+	return e
+}
+
+// recordEvent records an event on the object, the executor may have been created without a recorder, eg. in tests
+func (e *scaleExecutor) recordEvent(object runtime.Object, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if e.recorder == nil {
+		return
 	}
+	e.recorder.Eventf(object, eventType, reason, messageFmt, args...)
 }
 
 func (e *scaleExecutor) updateLastActiveTime(ctx context.Context, logger logr.Logger, object interface{}) error {
@@ -90,3 +135,175 @@ func (e *scaleExecutor) setActiveCondition(ctx context.Context, logger logr.Logg
 	}
 	return err
 }
+
+// patchScaledJobStatus patches the changes of the status of the ScaledJob since the original. The scaling of the Jobs
+// changes the status in place and patches it once at the end, instead of once per changed field or Condition
+func (e *scaleExecutor) patchScaledJobStatus(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, original *kedav1alpha1.ScaledJob) error {
+	if equality.Semantic.DeepEqual(original.Status, scaledJob.Status) {
+		return nil
+	}
+
+	err := e.client.Status().Patch(ctx, scaledJob, client.MergeFrom(original))
+	if err != nil {
+		logger.Error(err, "Failed to patch the status of the ScaledJob")
+	}
+	return err
+}
+
+// setActiveAndPausedConditions records whether the triggers of the ScaledJob are active and whether its scaling is paused,
+// a paused ScaledJob is not Active. The Paused Condition is added once the ScaledJob is paused for the first time
+func setActiveAndPausedConditions(scaledJob *kedav1alpha1.ScaledJob, isActive bool, paused bool) {
+	conditions := scaledJob.Status.Conditions.DeepCopy()
+	SetPausedCondition(&conditions, paused)
+	switch {
+	case paused:
+		conditions.SetActiveCondition(metav1.ConditionFalse, "ScaledJobPaused", "Scaling is not performed because the ScaledJob is paused")
+	case isActive:
+		conditions.SetActiveCondition(metav1.ConditionTrue, "ScalerActive", "Scaling is performed because triggers are active")
+	default:
+		conditions.SetActiveCondition(metav1.ConditionFalse, "ScalerNotActive", "Scaling is not performed because triggers are not active")
+	}
+
+	if isConditionUnchanged(scaledJob.Status.Conditions.GetActiveCondition(), conditions.GetActiveCondition()) &&
+		isConditionUnchanged(scaledJob.Status.Conditions.GetPausedCondition(), conditions.GetPausedCondition()) {
+		return
+	}
+	scaledJob.Status.Conditions = conditions
+}
+
+// SetPausedCondition sets the Paused Condition of a ScaledJob, the Condition is added once the ScaledJob is paused
+// for the first time. It is shared by the controller and the scale loop to keep the Condition consistent
+func SetPausedCondition(conditions *kedav1alpha1.Conditions, paused bool) {
+	if paused {
+		conditions.SetPausedCondition(metav1.ConditionTrue, "ScaledJobPaused", fmt.Sprintf("Jobs are not created while the %s annotation is set", PausedAnnotation))
+	} else if conditions.GetPausedCondition().Type != "" {
+		conditions.SetPausedCondition(metav1.ConditionFalse, "ScaledJobNotPaused", "")
+	}
+}
+
+// isConditionUnchanged returns true if both Conditions have the same type, status, reason and message
+func isConditionUnchanged(current kedav1alpha1.Condition, updated kedav1alpha1.Condition) bool {
+	return current.Type == updated.Type && current.Status == updated.Status &&
+		current.Reason == updated.Reason && current.Message == updated.Message
+}
+
+// setOverCapacityCondition records whether more Jobs are running than allowed by the maxReplicaCount of the ScaledJob,
+// a maxScale lowered by the pending work doesn't put it over capacity. The Condition is only updated when its status changes
+func setOverCapacityCondition(scaledJob *kedav1alpha1.ScaledJob, runningJobCount int64, maxReplicaCount int64) {
+	status := metav1.ConditionFalse
+	reason := "RunningJobsWithinMaxReplicaCount"
+	message := fmt.Sprintf("%d Jobs running, maxReplicaCount is %d", runningJobCount, maxReplicaCount)
+	if runningJobCount > maxReplicaCount {
+		status = metav1.ConditionTrue
+		reason = "RunningJobsExceedMaxReplicaCount"
+	}
+
+	current := scaledJob.Status.Conditions.GetOverCapacityCondition()
+	if current.Type == "" {
+		// the Condition is added once the ScaledJob is over capacity for the first time
+		if status == metav1.ConditionFalse {
+			return
+		}
+	} else if current.Status == status {
+		return
+	}
+	scaledJob.Status.Conditions.SetOverCapacityCondition(status, reason, message)
+}
+
+// setGlobalJobLimitReachedCondition records whether the creation of Jobs is blocked by the operator-level limit
+// of the Jobs running for all ScaledJobs, the Condition is added once the limit is reached for the first time.
+// The message leaves out the number of the running Jobs, the status would be patched on every scaling otherwise
+func (e *scaleExecutor) setGlobalJobLimitReachedCondition(scaledJob *kedav1alpha1.ScaledJob, reached bool) {
+	limit := e.config.MaxTotalJobs
+	status := metav1.ConditionFalse
+	reason := "TotalRunningJobsWithinLimit"
+	message := fmt.Sprintf("Jobs running for all ScaledJobs are within the limit of %d", limit)
+	if reached {
+		status = metav1.ConditionTrue
+		reason = "TotalRunningJobsReachLimit"
+		message = fmt.Sprintf("Jobs running for all ScaledJobs reach the limit of %d", limit)
+	}
+
+	current := scaledJob.Status.Conditions.GetGlobalJobLimitReachedCondition()
+	if current.Type == "" {
+		if status == metav1.ConditionFalse {
+			return
+		}
+	} else if current.Status == status && current.Message == message {
+		return
+	}
+	scaledJob.Status.Conditions.SetGlobalJobLimitReachedCondition(status, reason, message)
+}
+
+// setNamespaceTerminatingCondition records whether the creation of Jobs is skipped as the namespace is terminating,
+// the Condition is added once the namespace is terminating for the first time
+func setNamespaceTerminatingCondition(scaledJob *kedav1alpha1.ScaledJob, terminating bool) {
+	status := metav1.ConditionFalse
+	reason := "NamespaceActive"
+	message := fmt.Sprintf("Namespace %s is active", scaledJob.Namespace)
+	if terminating {
+		status = metav1.ConditionTrue
+		reason = "NamespaceTerminating"
+		message = fmt.Sprintf("Namespace %s is terminating, no Jobs are created", scaledJob.Namespace)
+	}
+
+	current := scaledJob.Status.Conditions.GetNamespaceTerminatingCondition()
+	if current.Type == "" {
+		if status == metav1.ConditionFalse {
+			return
+		}
+	} else if current.Status == status {
+		return
+	}
+	scaledJob.Status.Conditions.SetNamespaceTerminatingCondition(status, reason, message)
+}
+
+// setTemplateInvalidCondition records the error of the API server rejecting the Jobs created from the template,
+// a nil error clears the Condition
+func setTemplateInvalidCondition(scaledJob *kedav1alpha1.ScaledJob, templateErr error) {
+	status := metav1.ConditionFalse
+	reason := "JobsCreated"
+	message := ""
+	if templateErr != nil {
+		status = metav1.ConditionTrue
+		reason = "JobCreationRejected"
+		message = templateErr.Error()
+	}
+
+	current := scaledJob.Status.Conditions.GetTemplateInvalidCondition()
+	if current.Type == "" {
+		// the Condition is added once a Job is rejected for the first time
+		if status == metav1.ConditionFalse {
+			return
+		}
+	} else if current.Status == status && current.Message == message {
+		return
+	}
+	scaledJob.Status.Conditions.SetTemplateInvalidCondition(status, reason, message)
+}
+
+// setLastError records the message of the error of the last scaling of the ScaledJob in its status,
+// a nil error clears it. It is only updated when the message changes, so the LastErrorTime
+// is the time the error was first recorded
+func setLastError(scaledJob *kedav1alpha1.ScaledJob, scaleErr error) {
+	message := ""
+	if scaleErr != nil {
+		message = scaleErr.Error()
+	}
+	if scaledJob.Status.LastError == message {
+		return
+	}
+
+	scaledJob.Status.LastError = message
+	if scaleErr != nil {
+		now := metav1.Now()
+		scaledJob.Status.LastErrorTime = &now
+		scaledJob.Status.Conditions.SetDegradedCondition(metav1.ConditionTrue, "ScalingFailed", message)
+	} else {
+		scaledJob.Status.LastErrorTime = nil
+		// the Condition is added once the scaling fails for the first time
+		if scaledJob.Status.Conditions.GetDegradedCondition().Type != "" {
+			scaledJob.Status.Conditions.SetDegradedCondition(metav1.ConditionFalse, "ScalingSucceeded", "")
+		}
+	}
+}
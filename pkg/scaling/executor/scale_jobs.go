@@ -2,13 +2,22 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -17,95 +26,937 @@ import (
 )
 
 const (
+	// ScaledAtAnnotation is the annotation holding the time of the metrics that drove the creation of a Job
+	ScaledAtAnnotation = "keda.sh/scaled-at"
+	// PendingDeleteAnnotation marks Jobs exceeding the history limit when the ScaledJob uses softDeleteAnnotation,
+	// an external process is expected to archive and delete them
+	PendingDeleteAnnotation = "keda.sh/pending-delete"
+	// ExcludeFromCountLabel set to "true" on a Job excludes it from the running Jobs of the ScaledJob (eg. manual debug runs)
+	ExcludeFromCountLabel = "keda.sh/exclude-from-count"
+	// TriggerLabel identifies the trigger a Job was created for by its name, or the type of an unnamed trigger
+	TriggerLabel = "keda.sh/trigger"
+	// BatchIDLabel identifies the Jobs, their pods and ConfigMap created together by one scaling request
+	BatchIDLabel = "keda.sh/batch-id"
+	// ScaleValueEnv is the env var holding the metric value that drove the creation of a Job
+	ScaleValueEnv = "KEDA_SCALE_VALUE"
+	// TriggerEnv is the env var holding the triggers of the ScaledJob that created a Job
+	TriggerEnv = "KEDA_TRIGGER"
+	// ShardIndexEnv is the env var holding the index of the Job within the Jobs created together, from 0 to ShardTotalEnv-1
+	ShardIndexEnv = "SHARD_INDEX"
+	// ShardTotalEnv is the env var holding the number of the Jobs created together
+	ShardTotalEnv = "SHARD_TOTAL"
+	// CleanupNowAnnotation set to "true" on a ScaledJob cleans up its finished Jobs right away instead of with the next
+	// scaling, the annotation is removed once the cleanup completes
+	CleanupNowAnnotation = "keda.sh/cleanup-now"
+	// PausedAnnotation set to "true" on a ScaledJob pauses the creation of its Jobs, the running Jobs are not affected
+	PausedAnnotation = "autoscaling.keda.sh/paused"
+	// PausedParallelismAnnotation holds the parallelism of a Job paused by setting its parallelism to 0,
+	// whoever pauses the Job restores the parallelism from it on resume
+	PausedParallelismAnnotation = "keda.sh/paused-parallelism"
+	// ScalingDecision is the reason of the event emitted for each scaling of the Jobs of a ScaledJob
+	ScalingDecision = "ScalingDecision"
+	// JobDeletedByHistoryLimit is the reason of the event emitted for each Job deleted by a history limit,
+	// the message names the Job and the limit
+	JobDeletedByHistoryLimit = "JobDeletedByHistoryLimit"
+
+	defaultMaxReplicaCount            = int64(100)
 	defaultSuccessfulJobsHistoryLimit = int32(100)
 	defaultFailedJobsHistoryLimit     = int32(100)
+	defaultBacklogAgeBoostPercentage  = int32(100)
+	// Number of attempts to create a Job when the generated name already exists
+	maxJobCreateAttempts = 3
+	// Number of attempts to list the Jobs when counting the running ones
+	maxJobListAttempts = 3
+	// Default interval to the next scaling request while the ScaledJob is still scaling
+	defaultRequeueInterval = 5
 )
 
-func (e *scaleExecutor) RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64) {
+// jobListRetryInterval is multiplied by the attempt to get the wait time before listing the Jobs again
+var jobListRetryInterval = 100 * time.Millisecond
+
+var _ JobScaleExecutor = &scaleExecutor{}
+
+// RequestJobScale creates and cleans up the Jobs of the ScaledJob, it returns the suggested interval to the next
+// scaling request, 0 means the regular pollingInterval should be used. The stages of the scaling change the status
+// of the ScaledJob in place, it is patched once at the end
+func (e *scaleExecutor) RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64, backlogAge time.Duration, scaledAt time.Time) (time.Duration, error) {
 	logger := e.logger.WithValues("scaledJob.Name", scaledJob.Name, "scaledJob.Namespace", scaledJob.Namespace)
 
-	runningJobCount := e.getRunningJobCount(scaledJob, maxScale)
-	logger.Info("Scaling Jobs", "Number of running Jobs", runningJobCount)
+	// defense-in-depth, only the elected leader should create Jobs otherwise
+	// multiple KEDA instances could create Jobs for the same ScaledJob
+	if !IsLeaderElected() {
+		logger.V(1).Info("Not the elected leader, skipping scaling of Jobs")
+		return 0, nil
+	}
 
-	var effectiveMaxScale int64
-	effectiveMaxScale = maxScale - runningJobCount
-	if effectiveMaxScale < 0 {
-		effectiveMaxScale = 0
+	// the running Jobs can't be counted until the cache is synced, try again shortly
+	if !IsCacheSynced() {
+		logger.Info("Cache is not synced yet, postponing scaling of Jobs")
+		return getRequeueInterval(scaledJob), nil
 	}
 
-	if isActive {
-		logger.V(1).Info("At least one scaler is active")
-		now := metav1.Now()
-		scaledJob.Status.LastActiveTime = &now
-		e.updateLastActiveTime(ctx, logger, scaledJob)
-		e.createJobs(logger, scaledJob, scaleTo, effectiveMaxScale)
-	} else {
-		logger.V(1).Info("No change in activity")
+	original := scaledJob.DeepCopy()
+	requeueAfter, err := e.scaleJobs(ctx, logger, &jobScaleRequest{
+		scaledJob:  scaledJob,
+		isActive:   isActive,
+		scaleTo:    scaleTo,
+		maxScale:   maxScale,
+		backlogAge: backlogAge,
+		scaledAt:   scaledAt,
+	})
+	setLastError(scaledJob, err)
+	_ = e.patchScaledJobStatus(ctx, logger, scaledJob, original)
+	return requeueAfter, err
+}
+
+// jobScaleRequest is a scaling of the Jobs of a ScaledJob, it is passed through the stages of the scaling
+type jobScaleRequest struct {
+	scaledJob  *kedav1alpha1.ScaledJob
+	isActive   bool
+	scaleTo    int64
+	maxScale   int64
+	backlogAge time.Duration
+	scaledAt   time.Time
+
+	runningJobCount int64
+	// effectiveMaxScale is the number of the Jobs that can be created besides the running ones
+	effectiveMaxScale int64
+	createdJobCount   int64
+	failedJobCount    int64
+	// replaceBudget is the number of failed and stuck jobs that can still be replaced within maxScale,
+	// the stuck jobs are only recreated with recreateStuck, ie. when new Jobs can be created
+	replaceBudget int64
+	recreateStuck bool
+	requeueAfter  time.Duration
+}
+
+// scaleJobs runs the stages of the scaling of the Jobs, the errors of the stages that don't prevent the others
+// from running are returned together
+func (e *scaleExecutor) scaleJobs(ctx context.Context, logger logr.Logger, req *jobScaleRequest) (requeueAfter time.Duration, err error) {
+	scaledJob := req.scaledJob
+
+	// negative numbers can only come from a bug upstream, the number of Jobs to create would be undefined
+	if req.scaleTo < 0 || req.maxScale < 0 {
+		err := fmt.Errorf("scaleTo %d and maxScale %d must not be negative", req.scaleTo, req.maxScale)
+		logger.Error(err, "Invalid scaling request, not scaling")
+		invalidScaleInputsTotal.WithLabelValues(scaledJob.Namespace, scaledJob.Name).Inc()
+		return 0, err
 	}
 
-	err := e.cleanUp(scaledJob)
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		health.record(scaledJob.Namespace, scaledJob.Name, duration, int(req.createdJobCount-req.failedJobCount), int(req.failedJobCount))
+		backpressure.record(scaledJob.Namespace, scaledJob.Name, err != nil, duration)
+	}()
+
+	e.detectFinishedJobs(ctx, logger, scaledJob)
+
+	req.runningJobCount, err = e.getRunningJobCount(scaledJob, req.maxScale)
 	if err != nil {
+		// without the number of running Jobs, new Jobs could exceed maxScale
+		logger.Error(err, "Failed to count running Jobs, not scaling")
+		return getRequeueInterval(scaledJob), err
+	}
+	logger.Info("Scaling Jobs", "Number of running Jobs", req.runningJobCount)
+	setRunningJobCount(scaledJob, req.runningJobCount)
+
+	e.adjustScale(ctx, logger, req)
+
+	var errs []error
+	if err := e.enforceMaxReplicaCount(logger, scaledJob, req.runningJobCount); err != nil {
+		errs = append(errs, err)
+	}
+
+	canCreateJobs := e.checkJobCreation(ctx, logger, req)
+	cleanUp := e.startCleanUp(req)
+	if canCreateJobs {
+		if err := e.scaleUpJobs(ctx, logger, req); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	// the permits of the finished Jobs and of the Jobs that weren't created are given back to the other ScaledJobs
+	e.releaseSemaphorePermits(ctx, logger, scaledJob, req.runningJobCount+req.createdJobCount-req.failedJobCount)
+	e.recordScalingDecision(scaledJob, req.isActive, req.scaleTo, req.maxScale, req.runningJobCount, req.createdJobCount-req.failedJobCount)
+
+	if err := cleanUp(); err != nil {
 		logger.Error(err, "Failed to cleanUp jobs")
+		errs = append(errs, err)
 	}
 
-	return
+	if len(errs) > 0 {
+		return getRequeueInterval(scaledJob), utilerrors.NewAggregate(errs)
+	}
+	return req.requeueAfter, nil
 }
 
-func (e *scaleExecutor) createJobs(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, scaleTo int64, maxScale int64) {
-	scaledJob.Spec.JobTargetRef.Template.GenerateName = scaledJob.GetName() + "-"
-	if scaledJob.Spec.JobTargetRef.Template.Labels == nil {
-		scaledJob.Spec.JobTargetRef.Template.Labels = map[string]string{}
+// detectFinishedJobs finishes the Jobs that would otherwise count as running, eg. the Jobs stuck in ImagePullBackOff
+// or whose main container terminated, the errors are only logged as the Jobs are detected again by the next scaling
+func (e *scaleExecutor) detectFinishedJobs(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) {
+	if scaledJob.Spec.FailImagePullBackOffJobs {
+		if err := e.failImagePullBackOffJobs(ctx, logger, scaledJob); err != nil {
+			logger.Error(err, "Failed to fail the Jobs stuck in ImagePullBackOff")
+		}
 	}
-	scaledJob.Spec.JobTargetRef.Template.Labels["scaledjob"] = scaledJob.GetName()
 
+	if scaledJob.Spec.CompletionProbe != nil {
+		if err := e.detectProbeCompletedJobs(ctx, logger, scaledJob); err != nil {
+			logger.Error(err, "Failed to detect the Jobs completed by the completion probe")
+		}
+	}
+
+	if scaledJob.Spec.MainContainerName != "" {
+		if err := e.detectSidecarCompletedJobs(ctx, logger, scaledJob); err != nil {
+			logger.Error(err, "Failed to detect the Jobs whose main container terminated")
+		}
+	}
+}
+
+// adjustScale applies the scaling modifiers and the capacity of the nodes to scaleTo and maxScale of the request,
+// and sets the number of the Jobs that can be created besides the running ones
+func (e *scaleExecutor) adjustScale(ctx context.Context, logger logr.Logger, req *jobScaleRequest) {
+	scaledJob := req.scaledJob
+
+	req.scaleTo = getStabilizedScaleTo(logger, scaledJob, req.scaleTo)
+	req.scaleTo = getScaleDownStabilizedScaleTo(logger, scaledJob, req.scaleTo, time.Now())
+	maxScale, formulaEval := getMaxScaleByFormula(logger, scaledJob, req.scaleTo, req.maxScale, req.runningJobCount)
+	setLastFormulaEval(scaledJob, formulaEval)
+	maxScale = e.getMaxScaleByNodeCapacity(ctx, logger, scaledJob, maxScale)
+	req.maxScale = getTimeWindowMaxScale(logger, scaledJob, maxScale, time.Now())
+	scaleGap.WithLabelValues(scaledJob.Namespace, scaledJob.Name).Set(float64(getScaleGap(req.scaleTo, req.maxScale, req.runningJobCount)))
+	setBacklogCritical(scaledJob, req.scaleTo, req.runningJobCount)
+	scaleStates.record(JobScaleState{
+		Namespace:       scaledJob.Namespace,
+		Name:            scaledJob.Name,
+		IsActive:        req.isActive,
+		ScaleTo:         req.scaleTo,
+		MaxScale:        req.maxScale,
+		RunningJobCount: req.runningJobCount,
+		ScaledAt:        req.scaledAt,
+	})
+
+	req.effectiveMaxScale = req.maxScale - req.runningJobCount
+	if req.effectiveMaxScale < 0 {
+		req.effectiveMaxScale = 0
+	}
+	if req.runningJobCount > req.maxScale {
+		logger.Info("Number of running Jobs exceeds maxScale", "Number of running Jobs", req.runningJobCount, "maxScale", req.maxScale)
+	}
+	// maxScale and the running count are in pods, the free pods are shared by the new Jobs
+	if scaledJob.Spec.CountRunningPods {
+		req.effectiveMaxScale /= getJobTemplateParallelism(scaledJob)
+	}
+	setOverCapacityCondition(scaledJob, req.runningJobCount, GetMaxReplicaCount(scaledJob))
+}
+
+// enforceMaxReplicaCount deletes the running Jobs above the maxReplicaCount of the ScaledJob with enforceMaxScale.
+// The queue-derived maxScale is not enforced, it drops when the queue drains while the Jobs are still processing
+// their messages
+func (e *scaleExecutor) enforceMaxReplicaCount(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, runningJobCount int64) error {
+	if !scaledJob.Spec.EnforceMaxScale {
+		return nil
+	}
+	enforcedMaxScale := getTimeWindowMaxScale(logger, scaledJob, GetMaxReplicaCount(scaledJob), time.Now())
+	if runningJobCount <= enforcedMaxScale {
+		return nil
+	}
+
+	excess := runningJobCount - enforcedMaxScale
+	if scaledJob.Spec.CountRunningPods {
+		excess = devideWithCeil(excess, getJobTemplateParallelism(scaledJob))
+	}
+	if err := e.scaleDownJobs(logger, scaledJob, excess); err != nil {
+		logger.Error(err, "Failed to scale down jobs")
+		return err
+	}
+	return nil
+}
+
+// checkJobCreation returns true if Jobs can be created for the ScaledJob, they aren't while its triggers are not
+// active, it is paused or not Ready, its namespace is terminating or the operator-level limit of the Jobs running
+// for all ScaledJobs is reached. The limit caps the number of the Jobs that can be created
+func (e *scaleExecutor) checkJobCreation(ctx context.Context, logger logr.Logger, req *jobScaleRequest) bool {
+	scaledJob := req.scaledJob
+
+	latest := e.getLatestScaledJob(ctx, logger, scaledJob)
+	ready, notReadyReason := isScaledJobReady(latest)
+	paused := IsScaledJobPaused(latest)
+	if latest.Annotations[DebugSnapshotAnnotation] == "true" {
+		if err := e.writeDebugSnapshot(ctx, logger, latest, time.Now()); err != nil {
+			logger.Error(err, "Failed to write the debug snapshot of the jobs")
+		}
+	}
+	setActiveAndPausedConditions(scaledJob, req.isActive, paused)
+
+	switch {
+	case !req.isActive:
+		logger.V(1).Info("No change in activity")
+		return false
+	case paused:
+		logger.Info("ScaledJob is paused, skipping creation of Jobs")
+		return false
+	case !ready:
+		// the metrics may be stale or the spec invalid, Jobs are created once the ScaledJob is Ready again
+		logger.Info("ScaledJob is not Ready, skipping creation of Jobs", "reason", notReadyReason)
+		return false
+	}
+
+	globalJobLimitReached := false
+	if globalBudget, limited := e.getGlobalJobBudget(ctx, logger); limited {
+		globalJobLimitReached = globalBudget == 0
+		if globalBudget < req.effectiveMaxScale {
+			req.effectiveMaxScale = globalBudget
+		}
+		e.setGlobalJobLimitReachedCondition(scaledJob, globalJobLimitReached)
+	}
+	// the API server rejects the Jobs created in a terminating namespace
+	namespaceTerminating := e.isNamespaceTerminating(ctx, logger, scaledJob.Namespace)
+	setNamespaceTerminatingCondition(scaledJob, namespaceTerminating)
+
+	if namespaceTerminating {
+		logger.Info("Namespace is terminating, skipping creation of Jobs")
+		return false
+	}
+	if globalJobLimitReached {
+		logger.Info("Global limit of running Jobs is reached, skipping creation of Jobs")
+		req.requeueAfter = getRequeueInterval(scaledJob)
+		return false
+	}
+	return true
+}
+
+// startCleanUp returns the function running the cleanup of the Jobs of the ScaledJob, which replaces the failed
+// and stuck Jobs within the replaceBudget left by the creation of the Jobs. With concurrentCleanup the cleanup is
+// started right away and the function waits for it, it only deletes finished Jobs so it can run while the new Jobs
+// are created. It gets its own copy of the ScaledJob as both change the status
+func (e *scaleExecutor) startCleanUp(req *jobScaleRequest) func() error {
+	scaledJob := req.scaledJob
+	if !scaledJob.Spec.ConcurrentCleanup {
+		return func() error {
+			return e.cleanUpJobs(scaledJob, req.replaceBudget, req.recreateStuck)
+		}
+	}
+
+	cleanUpScaledJob := scaledJob.DeepCopy()
+	done := make(chan error, 1)
+	go func() {
+		done <- e.cleanUpJobs(cleanUpScaledJob, 0, false)
+	}()
+	return func() error {
+		err := <-done
+		scaledJob.Status.AverageJobDurationSeconds = cleanUpScaledJob.Status.AverageJobDurationSeconds
+		scaledJob.Status.LastCleanupDeletedCount = cleanUpScaledJob.Status.LastCleanupDeletedCount
+		return err
+	}
+}
+
+// scaleUpJobs creates the Jobs for the active ScaledJob, scaleTo is resolved by its scaling strategy and the number
+// of the Jobs that can be created is capped by the pending pressure, the external quota and the semaphore
+func (e *scaleExecutor) scaleUpJobs(ctx context.Context, logger logr.Logger, req *jobScaleRequest) error {
+	scaledJob := req.scaledJob
+
+	logger.V(1).Info("At least one scaler is active")
+	now := metav1.Now()
+	scaledJob.Status.LastActiveTime = &now
+	strategy := scaledJob.Spec.ScalingStrategy
+	if strategy == nil {
+		// the default is resolved for every scaling, later changes of the default are picked up
+		strategy = e.getDefaultScalingStrategy(ctx, logger, scaledJob)
+	}
+	scaleEnv := getScaleEnv(scaledJob, req.scaleTo)
+	req.scaleTo = getScaleToByStrategy(scaledJob, strategy, req.scaleTo)
+	req.scaleTo = e.boostScaleToByBacklogAge(logger, strategy, req.scaleTo, req.backlogAge)
+	req.effectiveMaxScale = e.getMaxScaleByPendingPressure(ctx, logger, scaledJob, req.effectiveMaxScale)
+	req.scaleTo = e.getScaleToByExternalQuota(ctx, logger, scaledJob, req.scaleTo, req.effectiveMaxScale)
+	req.effectiveMaxScale = e.getMaxScaleBySemaphore(ctx, logger, scaledJob, req.scaleTo, req.effectiveMaxScale, req.runningJobCount)
+
+	createdJobCount, err := e.createJobs(logger, scaledJob, strategy, req.scaleTo, req.effectiveMaxScale, req.scaledAt, scaleEnv)
+	req.createdJobCount = createdJobCount
+	observePollToCreateDuration(scaledJob, req.scaledAt)
+	if err != nil {
+		logger.Error(err, "Failed to create jobs")
+		// only failed creations are counted, not an invalid jobTargetRef
+		if aggregate, ok := err.(utilerrors.Aggregate); ok {
+			req.failedJobCount = int64(len(aggregate.Errors()))
+		}
+	}
+	// the transition from 0 to N running Jobs may e.g. require scaling out the nodes
+	if req.runningJobCount == 0 && req.createdJobCount > req.failedJobCount {
+		logger.Info("Scaled from zero", "Number of created Jobs", req.createdJobCount-req.failedJobCount)
+		scaledFromZeroTotal.WithLabelValues(scaledJob.Namespace, scaledJob.Name).Inc()
+	}
+	if scaledJob.Spec.ReplaceFailedJobs || scaledJob.Spec.StuckJobRecreation != nil {
+		req.replaceBudget = req.effectiveMaxScale - req.createdJobCount
+	}
+	req.recreateStuck = true
+	// not all the requested Jobs fit into maxScale or the creation rate limit, still scaling
+	if req.scaleTo > req.effectiveMaxScale || isCreationDeferred(scaledJob) {
+		req.requeueAfter = getRequeueInterval(scaledJob)
+		// no Job fits into maxScale, the slots are freed up by the Jobs finishing
+		if req.effectiveMaxScale == 0 && req.scaleTo > 0 {
+			req.requeueAfter = getAtCapacityRequeueInterval(scaledJob)
+		}
+	}
+	return err
+}
+
+// recordScalingDecision emits an event with the numbers of the Jobs the scaling was based on, so audit pipelines
+// consuming the events get the full trail. The message is made of key=value pairs to be easily parsed
+func (e *scaleExecutor) recordScalingDecision(scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64, runningJobCount int64, createdJobCount int64) {
+	e.recordEvent(scaledJob, corev1.EventTypeNormal, ScalingDecision, "active=%t scaleTo=%d maxScale=%d running=%d created=%d",
+		isActive, scaleTo, maxScale, runningJobCount, createdJobCount)
+}
+
+// getLatestScaledJob returns the latest ScaledJob, the scale loop holds the ScaledJob as it was when the loop started
+// and e.g. the conditions set by the controller or the annotations may have changed since.
+// The passed ScaledJob is returned if the latest one can't be read
+func (e *scaleExecutor) getLatestScaledJob(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) *kedav1alpha1.ScaledJob {
+	latest := &kedav1alpha1.ScaledJob{}
+	err := e.client.Get(ctx, types.NamespacedName{Namespace: scaledJob.Namespace, Name: scaledJob.Name}, latest)
+	if err != nil {
+		logger.V(1).Info("Failed to get the latest ScaledJob, using its last known state", "error", err.Error())
+		return scaledJob
+	}
+	return latest
+}
+
+// isScaledJobReady returns false and the message of the Ready condition if the ScaledJob
+// was marked as not Ready by the controller, a ScaledJob without the Ready condition is considered ready
+func isScaledJobReady(scaledJob *kedav1alpha1.ScaledJob) (bool, string) {
+	readyCondition := scaledJob.Status.Conditions.GetReadyCondition()
+	if readyCondition.IsFalse() {
+		return false, readyCondition.Message
+	}
+	return true, ""
+}
+
+// IsScaledJobPaused returns true if the scaling of the ScaledJob is paused by the PausedAnnotation
+func IsScaledJobPaused(scaledJob *kedav1alpha1.ScaledJob) bool {
+	return scaledJob.Annotations[PausedAnnotation] == "true"
+}
+
+// getRequeueInterval returns the interval used instead of the pollingInterval while the ScaledJob is still scaling
+func getRequeueInterval(scaledJob *kedav1alpha1.ScaledJob) time.Duration {
+	if scaledJob.Spec.RequeueInterval != nil {
+		return time.Second * time.Duration(*scaledJob.Spec.RequeueInterval)
+	}
+	return time.Second * time.Duration(defaultRequeueInterval)
+}
+
+// getAtCapacityRequeueInterval returns the interval used instead of the pollingInterval while Jobs are requested
+// but maxScale is reached, it defaults to the requeueInterval
+func getAtCapacityRequeueInterval(scaledJob *kedav1alpha1.ScaledJob) time.Duration {
+	if scaledJob.Spec.AtCapacityRequeueInterval != nil {
+		return time.Second * time.Duration(*scaledJob.Spec.AtCapacityRequeueInterval)
+	}
+	return getRequeueInterval(scaledJob)
+}
+
+// getScaleToByStrategy returns the number of Jobs to create for the queue length according to
+// the scaling strategy of the ScaledJob, its own or the default one
+func getScaleToByStrategy(scaledJob *kedav1alpha1.ScaledJob, strategy *kedav1alpha1.ScalingStrategy, queueLength int64) int64 {
+	if strategy == nil {
+		return queueLength
+	}
+
+	switch strategy.Strategy {
+	case kedav1alpha1.PercentageScalingStrategy:
+		if strategy.Percentage == nil {
+			return queueLength
+		}
+		return devideWithCeil(queueLength*int64(*strategy.Percentage), 100)
+	case kedav1alpha1.ThroughputScalingStrategy:
+		averageDuration := scaledJob.Status.AverageJobDurationSeconds
+		if strategy.ExpectedJobDurationSeconds == nil || averageDuration == nil || *averageDuration >= int64(*strategy.ExpectedJobDurationSeconds) {
+			return queueLength
+		}
+		// every Job processes the queue faster than expected, fewer Jobs are needed
+		return devideWithCeil(queueLength*(*averageDuration), int64(*strategy.ExpectedJobDurationSeconds))
+	default:
+		return queueLength
+	}
+}
+
+// boostScaleToByBacklogAge increases scaleTo by the configured percentage when the age of the backlog
+// exceeds the threshold defined in the scaling strategy
+func (e *scaleExecutor) boostScaleToByBacklogAge(logger logr.Logger, strategy *kedav1alpha1.ScalingStrategy, scaleTo int64, backlogAge time.Duration) int64 {
+	if strategy == nil || strategy.BacklogAgeThresholdSeconds == nil {
+		return scaleTo
+	}
+
+	threshold := time.Duration(*strategy.BacklogAgeThresholdSeconds) * time.Second
+	if backlogAge <= threshold {
+		return scaleTo
+	}
+
+	boostPercentage := defaultBacklogAgeBoostPercentage
+	if strategy.BacklogAgeBoostPercentage != nil {
+		boostPercentage = *strategy.BacklogAgeBoostPercentage
+	}
+
+	boost := (scaleTo*int64(boostPercentage) + 99) / 100
+	logger.V(1).Info("Backlog age exceeds the threshold, boosting number of jobs", "backlogAge", backlogAge, "threshold", threshold, "boost", boost)
+	return scaleTo + boost
+}
+
+// createJobs creates up to maxScale jobs and returns the number of jobs requested,
+// errors of the individual Job creations are aggregated into the returned error.
+// Once a Job is rejected as invalid, no further jobs are requested and the TemplateInvalid condition is set.
+// The jobs are annotated with scaledAt, the time of the metrics that drove their creation,
+// and scaleEnv is added to the env vars of their first container. The strategy is the resolved scaling strategy of the ScaledJob.
+func (e *scaleExecutor) createJobs(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, strategy *kedav1alpha1.ScalingStrategy, scaleTo int64, maxScale int64, scaledAt time.Time, scaleEnv []corev1.EnvVar) (int64, error) {
 	logger.Info("Creating jobs", "Effective number of max jobs", maxScale)
 
+	// the Jobs deferred to the next scaling are recorded, whatever stopped the creation
+	var deferredCount int64
+	defer func() {
+		deferredJobs.record(scaledJob.Namespace, scaledJob.Name, deferredCount)
+	}()
+
+	if err := e.validateJobGeneration(scaledJob); err != nil {
+		return 0, err
+	}
+	templatePatch, err := getJobTemplatePatch(scaledJob.Spec.JobTemplatePatch)
+	if err != nil {
+		return 0, err
+	}
+
+	// when the completions divisor is set, the workload is shared among the jobs
+	// and every job gets its share as completions
+	completions := getJobCompletions(strategy, scaleTo)
+	if completions != nil {
+		scaleTo = devideWithCeil(scaleTo, int64(*completions))
+	}
+
 	if scaleTo > maxScale {
 		scaleTo = maxScale
 	}
 	logger.Info("Creating jobs", "Number of jobs", scaleTo)
 
-	for i := 0; i < int(scaleTo); i++ {
-
-		job := &batchv1.Job{
-			ObjectMeta: metav1.ObjectMeta{
-				GenerateName: scaledJob.GetName() + "-",
-				Namespace:    scaledJob.GetNamespace(),
-				Labels: map[string]string{
-					"app.kubernetes.io/name":       scaledJob.GetName(),
-					"app.kubernetes.io/version":    version.Version,
-					"app.kubernetes.io/part-of":    scaledJob.GetName(),
-					"app.kubernetes.io/managed-by": "keda-operator",
-					"scaledjob":                    scaledJob.GetName(),
+	// the Jobs created together prefer the same nodes, eg. for data locality,
+	// or mount the ConfigMap with the scale context of their batch
+	var batchID string
+	if scaledJob.Spec.BatchAffinityTopologyKey != "" || scaledJob.Spec.EmitScaleConfigMap {
+		batchID = utilrand.String(8)
+	}
+
+	// the indices of the Jobs still running from a previous scaling are not reused
+	var shardIndices []int64
+	var shardTotal int64
+	if scaledJob.Spec.ShardedCreation && scaleTo > 0 {
+		var err error
+		shardIndices, shardTotal, err = e.reserveShardIndices(scaledJob, scaleTo)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var scaleConfigMapName string
+	if scaledJob.Spec.EmitScaleConfigMap && scaleTo > 0 {
+		var err error
+		scaleConfigMapName, err = e.createScaleConfigMap(logger, scaledJob, batchID, scaleTo, completions)
+		if err != nil {
+			// the pods of the Jobs couldn't start without the ConfigMap
+			return 0, err
+		}
+	}
+
+	var errs []error
+	var templateErr error
+	limiter := rateLimiters.get(scaledJob)
+	budget := e.config.JobCreationBudget
+	creationStart := time.Now()
+	attempted := int64(0)
+	for ; attempted < scaleTo; attempted++ {
+		if limiter != nil && !limiter.limiter.TryAccept() {
+			// the remaining Jobs are created by the next scaling requests as the bucket refills
+			logger.Info("Job creation rate limit reached, deferring the creation of the remaining jobs", "Number of deferred jobs", scaleTo-attempted)
+			deferredCount = scaleTo - attempted
+			break
+		}
+		if budget > 0 && attempted > 0 && time.Since(creationStart) > budget {
+			// the remaining Jobs are created by the next scaling requests, the scale loop isn't blocked meanwhile
+			logger.Info("Job creation budget exceeded, deferring the creation of the remaining jobs", "Number of deferred jobs", scaleTo-attempted, "budget", budget)
+			deferredCount = scaleTo - attempted
+			break
+		}
+		job := e.generateJob(logger, scaledJob)
+		if templatePatch != nil {
+			if err := applyJobTemplatePatch(job, templatePatch); err != nil {
+				// the patch is the same for all the Jobs
+				return 0, err
+			}
+		}
+		if !scaledAt.IsZero() {
+			if job.Annotations == nil {
+				job.Annotations = map[string]string{}
+			}
+			job.Annotations[ScaledAtAnnotation] = scaledAt.UTC().Format(time.RFC3339)
+		}
+		if completions != nil {
+			job.Spec.Completions = completions
+			job.Spec.Parallelism = completions
+		}
+		injectScaleEnv(job, scaleEnv)
+		if scaledJob.Spec.ShardedCreation {
+			injectScaleEnv(job, getShardEnv(shardIndices[attempted], shardTotal))
+			job.Labels[ShardIndexLabel] = strconv.FormatInt(shardIndices[attempted], 10)
+		}
+		if batchID != "" {
+			job.Labels[BatchIDLabel] = batchID
+		}
+		if scaledJob.Spec.BatchAffinityTopologyKey != "" {
+			injectBatchAffinity(job, batchID, scaledJob.Spec.BatchAffinityTopologyKey)
+		}
+		if scaleConfigMapName != "" {
+			injectScaleConfigMap(job, scaleConfigMapName, scaleTo)
+		}
+		if scaledJob.Spec.InjectScaleMetadataVolume {
+			injectScaleMetadataVolume(job)
+		}
+		if err := e.createJob(logger, job); err != nil {
+			errs = append(errs, fmt.Errorf("job %d of %d: %w", attempted+1, scaleTo, err))
+			if errors.IsInvalid(err) {
+				// the other Jobs from the same template would be rejected too
+				logger.Info("Job was rejected as invalid, not creating further jobs")
+				templateErr = err
+				attempted++
+				break
+			}
+		}
+	}
+	logger.Info("Created jobs", "Number of jobs", attempted-int64(len(errs)))
+
+	if attempted > 0 {
+		setTemplateInvalidCondition(scaledJob, templateErr)
+	}
+
+	return attempted, utilerrors.NewAggregate(errs)
+}
+
+// validateJobGeneration checks Jobs can be generated from the ScaledJob's jobTargetRef and owned by the ScaledJob
+func (e *scaleExecutor) validateJobGeneration(scaledJob *kedav1alpha1.ScaledJob) error {
+	if e.reconcilerScheme == nil {
+		return errNilReconcilerScheme
+	}
+	if scaledJob.Spec.JobTargetRef == nil {
+		return fmt.Errorf("scaledJob.Spec.JobTargetRef is not set")
+	}
+	return nil
+}
+
+// getScaleEnv returns the env vars describing the scaling decision when the ScaledJob enables injectScaleEnv,
+// the value is the metric value reported by the triggers, the triggers are listed by name or type
+func getScaleEnv(scaledJob *kedav1alpha1.ScaledJob, scaleValue int64) []corev1.EnvVar {
+	if !scaledJob.Spec.InjectScaleEnv {
+		return nil
+	}
+
+	triggers := make([]string, 0, len(scaledJob.Spec.Triggers))
+	for _, trigger := range scaledJob.Spec.Triggers {
+		if trigger.Name != "" {
+			triggers = append(triggers, trigger.Name)
+		} else {
+			triggers = append(triggers, trigger.Type)
+		}
+	}
+
+	return []corev1.EnvVar{
+		{Name: ScaleValueEnv, Value: strconv.FormatInt(scaleValue, 10)},
+		{Name: TriggerEnv, Value: strings.Join(triggers, ",")},
+	}
+}
+
+// getShardEnv returns the env vars assigning a shard of the workload to the Job, every running Job
+// gets its own index
+func getShardEnv(index int64, total int64) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: ShardIndexEnv, Value: strconv.FormatInt(index, 10)},
+		{Name: ShardTotalEnv, Value: strconv.FormatInt(total, 10)},
+	}
+}
+
+// injectScaleEnv sets the env vars on the first container of the Job, existing env vars with the same name are replaced
+func injectScaleEnv(job *batchv1.Job, scaleEnv []corev1.EnvVar) {
+	if len(scaleEnv) == 0 || len(job.Spec.Template.Spec.Containers) == 0 {
+		return
+	}
+
+	container := &job.Spec.Template.Spec.Containers[0]
+	for _, env := range scaleEnv {
+		replaced := false
+		for i := range container.Env {
+			if container.Env[i].Name == env.Name {
+				container.Env[i] = env
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			container.Env = append(container.Env, env)
+		}
+	}
+}
+
+// injectBatchAffinity labels the pods of the Job with the batchID and adds a preferred pod affinity
+// to the pods of the same batch within the topologyKey
+func injectBatchAffinity(job *batchv1.Job, batchID string, topologyKey string) {
+	podTemplate := &job.Spec.Template
+	if podTemplate.Labels == nil {
+		podTemplate.Labels = map[string]string{}
+	}
+	podTemplate.Labels[BatchIDLabel] = batchID
+
+	if podTemplate.Spec.Affinity == nil {
+		podTemplate.Spec.Affinity = &corev1.Affinity{}
+	}
+	if podTemplate.Spec.Affinity.PodAffinity == nil {
+		podTemplate.Spec.Affinity.PodAffinity = &corev1.PodAffinity{}
+	}
+	podAffinity := podTemplate.Spec.Affinity.PodAffinity
+	podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.WeightedPodAffinityTerm{
+			Weight: 100,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{BatchIDLabel: batchID},
 				},
+				TopologyKey: topologyKey,
 			},
-			Spec: *scaledJob.Spec.JobTargetRef.DeepCopy(),
+		})
+}
+
+// injectDefaultTolerations adds the default tolerations to the pods of the Job, eg. to run on tainted spot nodes,
+// a toleration of the template for the same key and effect takes precedence over the default
+func injectDefaultTolerations(job *batchv1.Job, defaults []corev1.Toleration) {
+	podSpec := &job.Spec.Template.Spec
+	for i := range defaults {
+		found := false
+		for _, toleration := range podSpec.Tolerations {
+			if toleration.Key == defaults[i].Key && toleration.Effect == defaults[i].Effect {
+				found = true
+				break
+			}
+		}
+		if !found {
+			podSpec.Tolerations = append(podSpec.Tolerations, *defaults[i].DeepCopy())
 		}
+	}
+}
 
-		// Job doesn't allow RestartPolicyAlways, it seems like this value is set by the client as a default one,
-		// we should set this property to allowed value in that case
-		if job.Spec.Template.Spec.RestartPolicy == "" {
-			logger.V(1).Info("Job RestartPolicy is not set, setting it to 'OnFailure', to avoid setting it to the client's default value 'Always'")
-			job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+// getJobCompletions returns the completions of every created Job derived from scaleTo and
+// the completionsDivisor of the scaling strategy, nil is returned if the divisor is not set
+func getJobCompletions(strategy *kedav1alpha1.ScalingStrategy, scaleTo int64) *int32 {
+	if strategy == nil || strategy.CompletionsDivisor == nil || *strategy.CompletionsDivisor < 1 || scaleTo < 1 {
+		return nil
+	}
+
+	completions := int32(devideWithCeil(scaleTo, int64(*strategy.CompletionsDivisor)))
+	return &completions
+}
+
+func devideWithCeil(x, y int64) int64 {
+	ans := x / y
+	reminder := x % y
+	if reminder != 0 {
+		return ans + 1
+	}
+	return ans
+}
+
+// createJob creates the passed Job, the creation is retried if the name generated from
+// the job's generateName already exists
+func (e *scaleExecutor) createJob(logger logr.Logger, job *batchv1.Job) error {
+	var err error
+	for attempt := 1; attempt <= maxJobCreateAttempts; attempt++ {
+		err = e.client.Create(context.TODO(), job)
+		if err == nil || !errors.IsAlreadyExists(err) || job.GenerateName == "" {
+			break
 		}
+		logger.V(1).Info("Generated Job name already exists, retrying", "attempt", attempt)
+		job.Name = ""
+	}
+	if err != nil {
+		logger.Error(err, "Failed to create a new Job")
+	}
+	return err
+}
 
-		// Set ScaledObject instance as the owner and controller
-		err := controllerutil.SetControllerReference(scaledJob, job, e.reconcilerScheme)
-		if err != nil {
-			logger.Error(err, "Failed to set ScaledObject as the owner of the new Job")
+// generateJob generates a new Job from the ScaledJob's jobTargetRef, in the next of its jobNamespaces if they are set.
+// The callers check the Job can be generated with validateJobGeneration first
+func (e *scaleExecutor) generateJob(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) *batchv1.Job {
+	scaledJob.Spec.JobTargetRef.Template.GenerateName = getJobNamePrefix(scaledJob)
+	if scaledJob.Spec.JobTargetRef.Template.Labels == nil {
+		scaledJob.Spec.JobTargetRef.Template.Labels = map[string]string{}
+	}
+	scaledJob.Spec.JobTargetRef.Template.Labels["scaledjob"] = GetScaledJobLabelValue(scaledJob)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: getJobNamePrefix(scaledJob),
+			Namespace:    scaledJob.GetNamespace(),
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       GetScaledJobLabelValue(scaledJob),
+				"app.kubernetes.io/version":    version.Version,
+				"app.kubernetes.io/part-of":    GetScaledJobLabelValue(scaledJob),
+				"app.kubernetes.io/managed-by": "keda-operator",
+				"scaledjob":                    GetScaledJobLabelValue(scaledJob),
+			},
+		},
+		Spec: *scaledJob.Spec.JobTargetRef.DeepCopy(),
+	}
+
+	if e.config.OperatorID != "" {
+		job.Labels[OperatorIDLabel] = e.config.OperatorID
+		job.Spec.Template.Labels[OperatorIDLabel] = e.config.OperatorID
+	}
+	if trigger := getJobTrigger(scaledJob); trigger != "" {
+		job.Labels[TriggerLabel] = trigger
+	}
+
+	// Job doesn't allow RestartPolicyAlways, it seems like this value is set by the client as a default one,
+	// we should set this property to allowed value in that case
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		logger.V(1).Info("Job RestartPolicy is not set, setting it to 'OnFailure', to avoid setting it to the client's default value 'Always'")
+		job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+	}
+
+	// the default satisfies e.g. the PodSecurity standards, a security context set in the template is kept as is
+	if job.Spec.Template.Spec.SecurityContext == nil && scaledJob.Spec.DefaultPodSecurityContext != nil {
+		job.Spec.Template.Spec.SecurityContext = scaledJob.Spec.DefaultPodSecurityContext.DeepCopy()
+	}
+	injectDefaultTolerations(job, scaledJob.Spec.DefaultTolerations)
+
+	// Set ScaledObject instance as the owner and controller
+	if err := controllerutil.SetControllerReference(scaledJob, job, e.reconcilerScheme); err != nil {
+		logger.Error(err, "Failed to set ScaledObject as the owner of the new Job")
+	}
+
+	if len(scaledJob.Spec.JobNamespaces) > 0 {
+		assignJobNamespace(job, scaledJob, jobNamespaces.next(scaledJob, e.config.getScaledJobNamespaces(scaledJob)))
+	}
+	return job
+}
+
+// getJobNamePrefix returns the prefix of the names generated for the Jobs, "<scaledjob>-" by default
+func getJobNamePrefix(scaledJob *kedav1alpha1.ScaledJob) string {
+	if scaledJob.Spec.JobNamePrefix != "" {
+		return scaledJob.Spec.JobNamePrefix
+	}
+	return scaledJob.GetName() + "-"
+}
+
+// ValidateJobNamePrefix checks the names generated from the prefix are valid Job names
+func ValidateJobNamePrefix(prefix string) error {
+	// the API server appends 5 random characters to the prefix
+	if errs := validation.IsDNS1123Label(prefix + strings.Repeat("x", 5)); len(errs) > 0 {
+		return fmt.Errorf("jobNamePrefix %q is not valid: %s", prefix, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// FinalizeScaledJob runs the cleanup of the ScaledJob's resources in order, it stops on the first error
+// so the cleanup can be retried by the next reconciliation
+func (e *scaleExecutor) FinalizeScaledJob(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error {
+	logger := e.logger.WithValues("scaledJob.Name", scaledJob.Name, "scaledJob.Namespace", scaledJob.Namespace)
+
+	for _, finalize := range e.jobFinalizers {
+		if err := finalize(ctx, logger, scaledJob); err != nil {
+			logger.Error(err, "Failed to finalize ScaledJob")
+			return err
 		}
+	}
+	scaleStates.remove(scaledJob.Namespace, scaledJob.Name)
+	rateLimiters.remove(scaledJob.Namespace, scaledJob.Name)
+	deferredJobs.remove(scaledJob.Namespace, scaledJob.Name)
+	jobNamespaces.remove(scaledJob.Namespace, scaledJob.Name)
+	scaleDowns.remove(scaledJob.Namespace, scaledJob.Name)
+	backpressure.remove(scaledJob.Namespace, scaledJob.Name)
+	scaleGap.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+	backlogCritical.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+	cleanupDuration.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+	pollToCreateDuration.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+	invalidScaleInputsTotal.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+	deleteHistoryRetained(scaledJob)
+	health.remove(scaledJob.Namespace, scaledJob.Name)
+	scaledFromZeroTotal.DeleteLabelValues(scaledJob.Namespace, scaledJob.Name)
+	return nil
+}
 
-		err = e.client.Create(context.TODO(), job)
+// CleanUpNow runs the cleanup of the Jobs of the ScaledJob out of band and removes the CleanupNowAnnotation
+func (e *scaleExecutor) CleanUpNow(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error {
+	logger := e.logger.WithValues("scaledJob.Name", scaledJob.Name, "scaledJob.Namespace", scaledJob.Namespace)
+
+	if err := e.cleanUp(scaledJob, 0, false); err != nil {
+		return err
+	}
+	logger.Info("Cleaned up jobs as requested by the annotation", "annotation", CleanupNowAnnotation)
+
+	patch := client.MergeFrom(scaledJob.DeepCopy())
+	delete(scaledJob.Annotations, CleanupNowAnnotation)
+	return e.client.Patch(ctx, scaledJob, patch)
+}
+
+// deleteJobs deletes all Jobs created for the ScaledJob. The Jobs spread outside of its namespace are looked up by its UID
+// in all the namespaces allowed by the operator, the jobNamespaces may have been changed since they were created
+func (e *scaleExecutor) deleteJobs(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+	jobs := &batchv1.JobList{}
+	err := e.client.List(ctx, jobs, e.getJobListOptions(scaledJob, scaledJob.GetNamespace())...)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range e.config.AllowedJobNamespaces {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == scaledJob.GetNamespace() {
+			continue
+		}
+		spread := &batchv1.JobList{}
+		err = e.client.List(ctx, spread, client.InNamespace(namespace))
 		if err != nil {
-			logger.Error(err, "Failed to create a new Job")
+			return err
+		}
+		for i := range spread.Items {
+			if isSpreadJob(&spread.Items[i], scaledJob) {
+				jobs.Items = append(jobs.Items, spread.Items[i])
+			}
+		}
+	}
 
+	for _, job := range jobs.Items {
+		err = e.client.Delete(ctx, job.DeepCopyObject(), client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
 		}
 	}
-	logger.Info("Created jobs", "Number of jobs", scaleTo)
+	logger.V(1).Info("Deleted jobs of the ScaledJob", "Number of jobs", len(jobs.Items))
+	return nil
+}
+
+// getJobListOptions returns the options to list the Jobs (or their pods) created for the ScaledJob by this KEDA instance
+// in the namespace, the ones outside of the namespace of the ScaledJob are listed with listJobs and listJobPods
+func (e *scaleExecutor) getJobListOptions(scaledJob *kedav1alpha1.ScaledJob, namespace string) []client.ListOption {
+	labels := map[string]string{"scaledjob": GetScaledJobLabelValue(scaledJob)}
+	if e.config.OperatorID != "" {
+		labels[OperatorIDLabel] = e.config.OperatorID
+	}
 
+	// the Jobs spread across the jobNamespaces are counted and cleaned up together
+	if len(scaledJob.Spec.JobNamespaces) > 0 {
+		labels[ScaledJobNamespaceLabel] = scaledJob.GetNamespace()
+	}
+
+	return []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(labels),
+	}
 }
 
-func (e *scaleExecutor) isJobFinished(j *batchv1.Job) bool {
+// IsJobFinished returns true if the Job completed or failed, its completion probe succeeded
+// or its main container terminated while the sidecars keep running
+func IsJobFinished(j *batchv1.Job) bool {
+	if isJobCompletedByProbe(j) || getMainContainerTerminatedConditionType(j) != "" {
+		return true
+	}
 	for _, c := range j.Status.Conditions {
 		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == v1.ConditionTrue {
 			return true
@@ -114,41 +965,123 @@ func (e *scaleExecutor) isJobFinished(j *batchv1.Job) bool {
 	return false
 }
 
-func (e *scaleExecutor) getRunningJobCount(scaledJob *kedav1alpha1.ScaledJob, maxScale int64) int64 {
-	var runningJobs int64
-
-	opts := []client.ListOption{
-		client.InNamespace(scaledJob.GetNamespace()),
-		client.MatchingLabels(map[string]string{"scaledjob": scaledJob.GetName()}),
+// isJobSuspended returns true for Jobs paused by setting their parallelism to 0 and recording the previous one
+// in PausedParallelismAnnotation, they have no active pods and don't finish until resumed, so they don't take
+// a slot of the running Jobs. A Job whose parallelism is 0 without the annotation is still counted as running
+func isJobSuspended(j *batchv1.Job) bool {
+	if _, ok := j.Annotations[PausedParallelismAnnotation]; !ok {
+		return false
 	}
+	return j.Spec.Parallelism != nil && *j.Spec.Parallelism == 0
+}
 
-	jobs := &batchv1.JobList{}
-	err := e.client.List(context.TODO(), jobs, opts...)
-
+// getRunningJobCount returns the number of running Jobs of the ScaledJob, listing the Jobs is retried
+// on transient errors, the error is returned once the attempts are exhausted
+func (e *scaleExecutor) getRunningJobCount(scaledJob *kedav1alpha1.ScaledJob, maxScale int64) (int64, error) {
+	var jobs *batchv1.JobList
+	var err error
+	for attempt := 1; attempt <= maxJobListAttempts; attempt++ {
+		jobs, err = e.listJobs(context.TODO(), scaledJob)
+		if err == nil || errors.IsNotFound(err) || attempt == maxJobListAttempts {
+			break
+		}
+		e.logger.V(1).Info("Failed to list Jobs, retrying", "attempt", attempt, "error", err.Error())
+		time.Sleep(time.Duration(attempt) * jobListRetryInterval)
+	}
+	if errors.IsNotFound(err) {
+		return 0, nil
+	}
 	if err != nil {
-		return 0
+		return 0, err
 	}
 
-	for _, job := range jobs.Items {
-		if !e.isJobFinished(&job) {
+	if scaledJob.Spec.CountRunningPods {
+		return e.countRunningPods(jobs.Items), nil
+	}
+	return e.countRunningJobs(jobs.Items), nil
+}
+
+// countRunningJobs returns the number of the jobs that are neither finished, suspended nor excluded from the count
+func (e *scaleExecutor) countRunningJobs(jobs []batchv1.Job) int64 {
+	var runningJobs int64
+	for _, job := range jobs {
+		if job.Labels[ExcludeFromCountLabel] == "true" {
+			continue
+		}
+		if !IsJobFinished(&job) && !isJobSuspended(&job) {
 			runningJobs++
 		}
 	}
-
 	return runningJobs
 }
 
-// Clean up will delete the jobs that is exceed historyLimit
-func (e *scaleExecutor) cleanUp(scaledJob *kedav1alpha1.ScaledJob) error {
-	logger := e.logger.WithValues("scaledJob.Name", scaledJob.Name, "scaledJob.Namespace", scaledJob.Namespace)
+// countRunningPods returns the number of the active pods of the jobs countRunningJobs counts,
+// so the jobs whose parallelism was raised by hand occupy their actual capacity
+func (e *scaleExecutor) countRunningPods(jobs []batchv1.Job) int64 {
+	var runningPods int64
+	for _, job := range jobs {
+		if job.Labels[ExcludeFromCountLabel] == "true" {
+			continue
+		}
+		if !IsJobFinished(&job) && !isJobSuspended(&job) {
+			runningPods += getJobPodCount(&job)
+		}
+	}
+	return runningPods
+}
 
-	opts := []client.ListOption{
-		client.InNamespace(scaledJob.GetNamespace()),
-		client.MatchingLabels(map[string]string{"scaledjob": scaledJob.GetName()}),
+// getJobPodCount returns the number of the active pods of the running job, a job whose pods
+// aren't active yet counts its parallelism as they are about to start
+func getJobPodCount(job *batchv1.Job) int64 {
+	if job.Status.Active > 0 {
+		return int64(job.Status.Active)
 	}
+	if job.Spec.Parallelism != nil && *job.Spec.Parallelism > 0 {
+		return int64(*job.Spec.Parallelism)
+	}
+	return 1
+}
 
-	jobs := &batchv1.JobList{}
-	err := e.client.List(context.TODO(), jobs, opts...)
+// GetMaxReplicaCount returns the maxReplicaCount of the ScaledJob, the number of running Jobs it allows
+// regardless of the pending work
+func GetMaxReplicaCount(scaledJob *kedav1alpha1.ScaledJob) int64 {
+	if scaledJob.Spec.MaxReplicaCount == nil {
+		return defaultMaxReplicaCount
+	}
+	return int64(*scaledJob.Spec.MaxReplicaCount)
+}
+
+// getJobTemplateParallelism returns the number of pods every Job of the ScaledJob runs in parallel
+func getJobTemplateParallelism(scaledJob *kedav1alpha1.ScaledJob) int64 {
+	if scaledJob.Spec.JobTargetRef == nil || scaledJob.Spec.JobTargetRef.Parallelism == nil || *scaledJob.Spec.JobTargetRef.Parallelism <= 0 {
+		return 1
+	}
+	return int64(*scaledJob.Spec.JobTargetRef.Parallelism)
+}
+
+// cleanUp runs the cleanup of the Jobs out of the scaling of the Jobs and patches the status of the ScaledJob
+func (e *scaleExecutor) cleanUp(scaledJob *kedav1alpha1.ScaledJob, replaceBudget int64, recreateStuck bool) error {
+	logger := e.logger.WithValues("scaledJob.Name", scaledJob.Name, "scaledJob.Namespace", scaledJob.Namespace)
+
+	original := scaledJob.DeepCopy()
+	err := e.cleanUpJobs(scaledJob, replaceBudget, recreateStuck)
+	_ = e.patchScaledJobStatus(context.TODO(), logger, scaledJob, original)
+	return err
+}
+
+// Clean up will delete the jobs that is exceed historyLimit.
+// Up to replaceBudget stuck and failed jobs are replaced by new ones after they are deleted,
+// the stuck jobs are only handled with recreateStuck, ie. while Jobs can be created for the ScaledJob
+func (e *scaleExecutor) cleanUpJobs(scaledJob *kedav1alpha1.ScaledJob, replaceBudget int64, recreateStuck bool) error {
+	logger := e.logger.WithValues("scaledJob.Name", scaledJob.Name, "scaledJob.Namespace", scaledJob.Namespace)
+
+	start := time.Now()
+	defer func() {
+		cleanupDuration.WithLabelValues(scaledJob.Namespace, scaledJob.Name).Observe(time.Since(start).Seconds())
+	}()
+
+	jobsListedAt := time.Now()
+	jobs, err := e.listJobs(context.TODO(), scaledJob)
 	if err != nil {
 		logger.Error(err, "Can not get list of Jobs")
 		return err
@@ -156,6 +1089,7 @@ func (e *scaleExecutor) cleanUp(scaledJob *kedav1alpha1.ScaledJob) error {
 
 	completedJobs := []batchv1.Job{}
 	failedJobs := []batchv1.Job{}
+	runningJobs := []batchv1.Job{}
 	for _, job := range jobs.Items {
 		finishedJobConditionType := e.getFinishedJobConditionType(&job)
 		switch finishedJobConditionType {
@@ -163,11 +1097,66 @@ func (e *scaleExecutor) cleanUp(scaledJob *kedav1alpha1.ScaledJob) error {
 			completedJobs = append(completedJobs, job)
 		case batchv1.JobFailed:
 			failedJobs = append(failedJobs, job)
+		default:
+			runningJobs = append(runningJobs, job)
+		}
+	}
+
+	err = e.handleOwnerlessJobs(logger, scaledJob, jobs.Items)
+	if err != nil {
+		return err
+	}
+
+	if averageDuration, ok := getAverageJobDuration(completedJobs); ok {
+		setAverageJobDuration(scaledJob, averageDuration)
+	}
+
+	if scaledJob.Spec.DeleteOrphanedPods {
+		err = e.deleteOrphanedPods(logger, scaledJob, jobs.Items, jobsListedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	if scaledJob.Spec.EmitScaleConfigMap {
+		err = e.deleteScaleConfigMaps(logger, scaledJob, jobs.Items, jobsListedAt)
+		if err != nil {
+			return err
+		}
+		err = e.recreateScaleConfigMaps(logger, scaledJob, jobs.Items)
+		if err != nil {
+			return err
+		}
+	}
+
+	if maxJobAge, ok := getMaxJobAge(scaledJob); ok {
+		err = e.deleteJobsExceedingMaxAge(logger, runningJobs, maxJobAge, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	if scaledJob.Spec.StuckJobRecreation != nil && recreateStuck {
+		replaceBudget, err = e.recreateStuckJobs(logger, scaledJob, runningJobs, replaceBudget, time.Now())
+		if err != nil {
+			return err
 		}
 	}
 
-	sort.Sort(byCompletedTime(completedJobs))
-	sort.Sort(byCompletedTime(failedJobs))
+	if scaledJob.Spec.SuccessfulJobsCleanupOrder == kedav1alpha1.NewestJobsCleanupOrder {
+		sort.Sort(sort.Reverse(byCompletedTime(completedJobs)))
+		if scaledJob.Spec.AlwaysRetainLastSuccess && len(completedJobs) > 0 {
+			// the most recent completed job is moved last, the jobs are deleted from the front
+			completedJobs = append(completedJobs[1:], completedJobs[0])
+		}
+	} else {
+		sort.Sort(byCompletedTime(completedJobs))
+	}
+	if scaledJob.Spec.FailedJobsCleanupOrder == kedav1alpha1.NewestJobsCleanupOrder {
+		sort.Sort(sort.Reverse(byCompletedTime(failedJobs)))
+	} else {
+		sort.Sort(byCompletedTime(failedJobs))
+	}
 
 	successfulJobsHistoryLimit := defaultSuccessfulJobsHistoryLimit
 	failedJobsHistoryLimit := defaultFailedJobsHistoryLimit
@@ -180,31 +1169,442 @@ func (e *scaleExecutor) cleanUp(scaledJob *kedav1alpha1.ScaledJob) error {
 		failedJobsHistoryLimit = *scaledJob.Spec.FailedJobsHistoryLimit
 	}
 
-	err = e.deleteJobsWithHistoryLimit(logger, completedJobs, successfulJobsHistoryLimit)
+	// the finished jobs that are not retained by the history limits are deleted
+	historyCompletedCount := getHistoryJobCount(completedJobs, scaledJob.Spec.SoftDeleteAnnotation)
+	historyFailedCount := getHistoryJobCount(failedJobs, scaledJob.Spec.SoftDeleteAnnotation)
+
+	// the jobs referenced by an incident don't count towards the limits and are kept until the incident clears
+	var heldCompletedJobs, heldFailedJobs []batchv1.Job
+	completedJobs, heldCompletedJobs = splitIncidentJobs(scaledJob, completedJobs)
+	failedJobs, heldFailedJobs = splitIncidentJobs(scaledJob, failedJobs)
+	if len(heldCompletedJobs)+len(heldFailedJobs) > 0 {
+		logger.V(1).Info("Retaining jobs referenced by an incident", "annotation", scaledJob.Spec.IncidentRetentionAnnotation,
+			"completed", len(heldCompletedJobs), "failed", len(heldFailedJobs))
+	}
+
+	// the jobs of the triggers with their own history limits are cleaned up separately
+	var triggerBuckets []*triggerHistoryBucket
+	completedJobs, failedJobs, triggerBuckets = splitTriggerHistoryBuckets(scaledJob, completedJobs, failedJobs, successfulJobsHistoryLimit, failedJobsHistoryLimit)
+	budget := newDeletionBudget(scaledJob)
+	bucketCompletedCount, bucketFailedCount, err := e.cleanUpTriggerHistoryBuckets(logger, scaledJob, triggerBuckets, budget)
 	if err != nil {
 		return err
 	}
-	err = e.deleteJobsWithHistoryLimit(logger, failedJobs, failedJobsHistoryLimit)
+
+	// the most recent completed job is kept for audit regardless of the limits
+	if scaledJob.Spec.AlwaysRetainLastSuccess && successfulJobsHistoryLimit < 1 {
+		successfulJobsHistoryLimit = 1
+	}
+
+	_, err = e.deleteJobsWithHistoryLimit(logger, scaledJob, completedJobs, "successfulJobsHistoryLimit", successfulJobsHistoryLimit, scaledJob.Spec.SoftDeleteAnnotation, budget)
 	if err != nil {
 		return err
 	}
+	deletedFailedJobs, err := e.deleteJobsWithHistoryLimit(logger, scaledJob, failedJobs, "failedJobsHistoryLimit", failedJobsHistoryLimit, scaledJob.Spec.SoftDeleteAnnotation, budget)
+	if err != nil {
+		return err
+	}
+	// only the jobs deleted by this cleanup are replaced, the jobs that are still there would be replaced again
+	if scaledJob.Spec.ReplaceFailedJobs && replaceBudget > 0 {
+		e.replaceFailedJobs(logger, scaledJob, deletedFailedJobs, replaceBudget)
+	}
+	if scaledJob.Spec.FailedJobPodsHistoryLimit != nil {
+		retainedFailedJobs := getRetainedJobs(failedJobs, failedJobsHistoryLimit)
+		err = e.deleteFailedJobPodsWithHistoryLimit(logger, scaledJob, retainedFailedJobs, *scaledJob.Spec.FailedJobPodsHistoryLimit)
+		if err != nil {
+			return err
+		}
+	}
+
+	retainedCompletedCount := len(getRetainedJobs(completedJobs, successfulJobsHistoryLimit))
+	retainedFailedCount := len(getRetainedJobs(failedJobs, failedJobsHistoryLimit))
+	if scaledJob.Spec.TotalJobsHistoryLimit != nil {
+		retainedCompletedJobs := getRetainedJobs(completedJobs, successfulJobsHistoryLimit)
+		totalJobsHistoryLimit := *scaledJob.Spec.TotalJobsHistoryLimit
+		lastSuccessRetained := false
+		if scaledJob.Spec.AlwaysRetainLastSuccess && len(retainedCompletedJobs) > 0 {
+			// the most recent completed job counts towards the limit, but it is never deleted
+			retainedCompletedJobs = retainedCompletedJobs[:len(retainedCompletedJobs)-1]
+			lastSuccessRetained = true
+			if totalJobsHistoryLimit > 0 {
+				totalJobsHistoryLimit--
+			}
+		}
+		finishedJobs := append(retainedCompletedJobs, getRetainedJobs(failedJobs, failedJobsHistoryLimit)...)
+		sort.Sort(byCompletedTime(finishedJobs))
+		_, err = e.deleteJobsWithHistoryLimit(logger, scaledJob, finishedJobs, "totalJobsHistoryLimit", totalJobsHistoryLimit, scaledJob.Spec.SoftDeleteAnnotation, budget)
+		if err != nil {
+			return err
+		}
+
+		retainedCompletedCount, retainedFailedCount = 0, 0
+		if lastSuccessRetained {
+			retainedCompletedCount++
+		}
+		for _, job := range getRetainedJobs(finishedJobs, totalJobsHistoryLimit) {
+			if e.getFinishedJobConditionType(&job) == batchv1.JobComplete {
+				retainedCompletedCount++
+			} else {
+				retainedFailedCount++
+			}
+		}
+	}
+	deferredCompletedCount, deferredFailedCount := budget.getDeferred()
+	if deferredCompletedCount+deferredFailedCount > 0 {
+		logger.Info("Deferred the deletion of jobs exceeding the history limits to the next cleanup",
+			"maxDeletesPerReconcile", *scaledJob.Spec.MaxDeletesPerReconcile, "deferred", deferredCompletedCount+deferredFailedCount)
+	}
+	retainedCompletedCount += bucketCompletedCount + deferredCompletedCount + getHistoryJobCount(heldCompletedJobs, scaledJob.Spec.SoftDeleteAnnotation)
+	retainedFailedCount += bucketFailedCount + deferredFailedCount + getHistoryJobCount(heldFailedJobs, scaledJob.Spec.SoftDeleteAnnotation)
+	setHistoryRetained(scaledJob, retainedCompletedCount, retainedFailedCount)
+	setLastCleanupDeletedCount(scaledJob, kedav1alpha1.CleanupDeletedCount{
+		Completed: getDeletedJobCount(historyCompletedCount, retainedCompletedCount),
+		Failed:    getDeletedJobCount(historyFailedCount, retainedFailedCount),
+	})
 	return nil
 }
 
-func (e *scaleExecutor) deleteJobsWithHistoryLimit(logger logr.Logger, jobs []batchv1.Job, historyLimit int32) error {
+// getHistoryJobCount returns the number of finished jobs in the history before the cleanup,
+// the soft deleted jobs marked for deletion by a previous cleanup are no longer part of the history
+func getHistoryJobCount(jobs []batchv1.Job, softDelete bool) int {
+	count := len(jobs)
+	if softDelete {
+		for _, job := range jobs {
+			if _, ok := job.Annotations[PendingDeleteAnnotation]; ok {
+				count--
+			}
+		}
+	}
+	return count
+}
+
+// getDeletedJobCount returns the number of jobs deleted from the history by the cleanup
+func getDeletedJobCount(historyCount int, retainedCount int) int64 {
+	if historyCount < retainedCount {
+		return 0
+	}
+	return int64(historyCount - retainedCount)
+}
+
+// getRetainedJobs returns the jobs that are not deleted by deleteJobsWithHistoryLimit
+func getRetainedJobs(jobs []batchv1.Job, historyLimit int32) []batchv1.Job {
 	if len(jobs) <= int(historyLimit) {
+		return jobs
+	}
+	return jobs[len(jobs)-int(historyLimit):]
+}
+
+// getAverageJobDuration returns the average duration of the completed jobs from their start to their completion,
+// false is returned if the duration of no job is known
+func getAverageJobDuration(completedJobs []batchv1.Job) (time.Duration, bool) {
+	var total time.Duration
+	var count int64
+	for i := range completedJobs {
+		duration, ok := getJobDuration(&completedJobs[i])
+		if !ok {
+			continue
+		}
+		total += duration
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return total / time.Duration(count), true
+}
+
+// getJobDuration returns the duration of the job from its start to its completion, false is returned if either
+// time is not set, eg. the CompletionTime of a job whose StartTime was never recorded, or the completion precedes the start
+func getJobDuration(job *batchv1.Job) (time.Duration, bool) {
+	if job.Status.StartTime == nil || job.Status.CompletionTime == nil {
+		return 0, false
+	}
+	duration := job.Status.CompletionTime.Sub(job.Status.StartTime.Time)
+	if duration < 0 {
+		return 0, false
+	}
+	return duration, true
+}
+
+// setAverageJobDuration records the average duration of the completed Jobs in the ScaledJob's status,
+// it is only updated when the duration in seconds changes
+func setAverageJobDuration(scaledJob *kedav1alpha1.ScaledJob, averageDuration time.Duration) {
+	seconds := int64(averageDuration.Round(time.Second) / time.Second)
+	if scaledJob.Status.AverageJobDurationSeconds != nil && *scaledJob.Status.AverageJobDurationSeconds == seconds {
+		return
+	}
+	scaledJob.Status.AverageJobDurationSeconds = &seconds
+}
+
+// setLastCleanupDeletedCount records the number of Jobs deleted by the last cleanup in the ScaledJob's status,
+// it is only updated when the numbers change
+func setLastCleanupDeletedCount(scaledJob *kedav1alpha1.ScaledJob, deletedCount kedav1alpha1.CleanupDeletedCount) {
+	recorded := scaledJob.Status.LastCleanupDeletedCount
+	if recorded == nil && deletedCount == (kedav1alpha1.CleanupDeletedCount{}) {
+		return
+	}
+	if recorded != nil && *recorded == deletedCount {
+		return
+	}
+	scaledJob.Status.LastCleanupDeletedCount = &deletedCount
+}
+
+// setRunningJobCount records the number of running Jobs in the ScaledJob's status,
+// it is only updated when the number changes
+func setRunningJobCount(scaledJob *kedav1alpha1.ScaledJob, runningJobCount int64) {
+	if scaledJob.Status.RunningJobCount != nil && *scaledJob.Status.RunningJobCount == runningJobCount {
+		return
+	}
+	scaledJob.Status.RunningJobCount = &runningJobCount
+}
+
+// deleteOrphanedPods deletes pods of the ScaledJob whose owning Job doesn't exist anymore.
+// Pods created since the jobs were listed are skipped, their Job may be missing from the list
+// as it was created meanwhile, eg. while the cleanup runs concurrently with the creation of Jobs
+func (e *scaleExecutor) deleteOrphanedPods(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, jobs []batchv1.Job, jobsListedAt time.Time) error {
+	existingJobs := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		existingJobs[j.GetName()] = true
+	}
+
+	pods, err := e.listJobPods(context.TODO(), scaledJob)
+	if err != nil {
+		logger.Error(err, "Can not get list of Pods")
+		return err
+	}
+
+	// the creation timestamp has a precision of seconds
+	listedAt := jobsListedAt.Truncate(time.Second)
+	for _, pod := range pods.Items {
+		jobName := getPodJobName(&pod)
+		if existingJobs[jobName] || !pod.CreationTimestamp.Time.Before(listedAt) {
+			continue
+		}
+
+		err = e.client.Delete(context.TODO(), pod.DeepCopyObject())
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Remove an orphaned pod", "pod.Name", pod.GetName(), "job.Name", jobName)
+	}
+	return nil
+}
+
+// handleOwnerlessJobs detects the jobs whose owner reference was removed, eg. by a user or another controller.
+// Such jobs still match the labels of the ScaledJob and are counted, but they would be left behind by the
+// garbage collector once the ScaledJob is deleted. The ScaledJob is set as their controller again when the
+// ScaledJob enables adoptOwnerlessJobs, they are only logged otherwise
+func (e *scaleExecutor) handleOwnerlessJobs(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, jobs []batchv1.Job) error {
+	for i := range jobs {
+		job := &jobs[i]
+		if owner := metav1.GetControllerOf(job); owner != nil {
+			if owner.UID != scaledJob.GetUID() {
+				logger.Info("Job is controlled by another object, not adopting it", "job.Name", job.GetName(), "owner.Kind", owner.Kind, "owner.Name", owner.Name)
+			}
+			continue
+		}
+		if isSpreadJob(job, scaledJob) {
+			// the Jobs spread across the jobNamespaces are deleted by the finalizer of the ScaledJob
+			continue
+		}
+		if !scaledJob.Spec.AdoptOwnerlessJobs {
+			logger.Info("Job has no owner reference, it won't be deleted with the ScaledJob", "job.Name", job.GetName())
+			continue
+		}
+
+		patch := client.MergeFrom(job.DeepCopy())
+		if err := controllerutil.SetControllerReference(scaledJob, job, e.reconcilerScheme); err != nil {
+			return err
+		}
+		err := e.client.Patch(context.TODO(), job, patch)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Restored the owner reference of a job", "job.Name", job.GetName())
+	}
+	return nil
+}
+
+// deleteFailedJobPodsWithHistoryLimit deletes the oldest pods of the retained failed jobs, only the last
+// historyLimit pods of each job are kept with their logs, the pods of the retries add up otherwise
+func (e *scaleExecutor) deleteFailedJobPodsWithHistoryLimit(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, failedJobs []batchv1.Job, historyLimit int32) error {
+	if len(failedJobs) == 0 {
 		return nil
 	}
 
+	pods, err := e.listJobPods(context.TODO(), scaledJob)
+	if err != nil {
+		logger.Error(err, "Can not get list of Pods")
+		return err
+	}
+
+	jobPods := make(map[string][]corev1.Pod, len(failedJobs))
+	for _, j := range failedJobs {
+		jobPods[j.GetName()] = []corev1.Pod{}
+	}
+	for _, pod := range pods.Items {
+		jobName := getPodJobName(&pod)
+		if podsOfJob, ok := jobPods[jobName]; ok {
+			jobPods[jobName] = append(podsOfJob, pod)
+		}
+	}
+
+	for jobName, podsOfJob := range jobPods {
+		if len(podsOfJob) <= int(historyLimit) {
+			continue
+		}
+		sort.SliceStable(podsOfJob, func(i, j int) bool {
+			return podsOfJob[i].CreationTimestamp.Before(&podsOfJob[j].CreationTimestamp)
+		})
+		for _, pod := range podsOfJob[:len(podsOfJob)-int(historyLimit)] {
+			err = e.client.Delete(context.TODO(), pod.DeepCopyObject())
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			logger.Info("Remove a pod of a failed job exceeding the pods history limit", "pod.Name", pod.GetName(), "job.Name", jobName)
+		}
+	}
+	return nil
+}
+
+// getPodJobName returns the name of the Job owning the pod, the "job-name" label is used
+// when the owner reference was already removed by the garbage collector
+func getPodJobName(pod *corev1.Pod) string {
+	if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "Job" {
+		return owner.Name
+	}
+	return pod.Labels["job-name"]
+}
+
+// getMaxJobAge returns the age above which running jobs are deleted, false is returned if it is not set.
+// The maxJobAgeDurationMultiplier times the recorded average job duration takes precedence,
+// the maxJobAgeSeconds is used until an average duration is recorded
+func getMaxJobAge(scaledJob *kedav1alpha1.ScaledJob) (time.Duration, bool) {
+	multiplier := scaledJob.Spec.MaxJobAgeDurationMultiplier
+	averageDuration := scaledJob.Status.AverageJobDurationSeconds
+	if multiplier != nil && *multiplier > 0 && averageDuration != nil && *averageDuration > 0 {
+		return time.Duration(int64(*multiplier)**averageDuration) * time.Second, true
+	}
+	if scaledJob.Spec.MaxJobAgeSeconds != nil {
+		return time.Duration(*scaledJob.Spec.MaxJobAgeSeconds) * time.Second, true
+	}
+	return 0, false
+}
+
+// deleteJobsExceedingMaxAge deletes running jobs that are older than maxJobAge.
+// The activeDeadlineSeconds of a Job takes precedence, maxJobAge is only a backstop for jobs that
+// were not terminated by their deadline, jobs that will reach their deadline are left to terminate on their own.
+func (e *scaleExecutor) deleteJobsExceedingMaxAge(logger logr.Logger, jobs []batchv1.Job, maxJobAge time.Duration, now time.Time) error {
+	for _, j := range jobs {
+		if j.Status.StartTime == nil {
+			continue
+		}
+
+		age := now.Sub(j.Status.StartTime.Time)
+		if age <= maxJobAge {
+			continue
+		}
+
+		if j.Spec.ActiveDeadlineSeconds != nil {
+			deadline := j.Status.StartTime.Add(time.Duration(*j.Spec.ActiveDeadlineSeconds) * time.Second)
+			if now.Before(deadline) {
+				logger.V(1).Info("Job exceeds the maxJobAge, but it will be terminated by its activeDeadlineSeconds", "job.Name", j.ObjectMeta.Name, "deadline", deadline)
+				continue
+			}
+		}
+
+		err := e.client.Delete(context.TODO(), j.DeepCopyObject(), client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Remove a job by reaching the maxJobAge", "job.Name", j.ObjectMeta.Name, "age", age, "maxJobAge", maxJobAge)
+	}
+	return nil
+}
+
+// replaceFailedJobs creates a new Job for each failed job deleted by the failedJobsHistoryLimit,
+// at most replaceBudget jobs are created
+func (e *scaleExecutor) replaceFailedJobs(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, deletedJobs []batchv1.Job, replaceBudget int64) {
+	if len(deletedJobs) == 0 {
+		return
+	}
+	if err := e.validateJobGeneration(scaledJob); err != nil {
+		logger.Error(err, "Not replacing failed jobs")
+		return
+	}
+
+	for _, j := range deletedJobs {
+		if replaceBudget <= 0 {
+			logger.Info("Not replacing failed job, maxScale reached", "job.Name", j.ObjectMeta.Name)
+			return
+		}
+		if err := e.createJob(logger, e.generateJob(logger, scaledJob)); err != nil {
+			continue
+		}
+		replaceBudget--
+		logger.Info("Replaced a failed job", "job.Name", j.ObjectMeta.Name)
+	}
+}
+
+// deleteJobsWithHistoryLimit deletes the first jobs exceeding the historyLimit, with softDelete the jobs are
+// annotated with PendingDeleteAnnotation instead and an external process is expected to delete them.
+// The jobs exceeding the deletion budget of the cleanup are left to the next cleanup, the deleted jobs are returned
+func (e *scaleExecutor) deleteJobsWithHistoryLimit(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, jobs []batchv1.Job, limit string, historyLimit int32, softDelete bool, budget *deletionBudget) ([]batchv1.Job, error) {
+	if len(jobs) <= int(historyLimit) {
+		return nil, nil
+	}
+
+	var deleted []batchv1.Job
 	deleteJobLength := len(jobs) - int(historyLimit)
 	for _, j := range (jobs)[0:deleteJobLength] {
+		// the jobs already marked for deletion are not patched again, they don't use up the budget
+		if _, marked := j.Annotations[PendingDeleteAnnotation]; !(softDelete && marked) && !budget.take(e.getFinishedJobConditionType(&j)) {
+			continue
+		}
+		if softDelete {
+			marked, err := e.markJobPendingDelete(logger, &j)
+			if err != nil {
+				return deleted, err
+			}
+			if marked {
+				e.recordEvent(scaledJob, corev1.EventTypeNormal, JobDeletedByHistoryLimit,
+					"Marked Job %s for deletion, %s %d reached", j.GetName(), limit, historyLimit)
+			}
+			continue
+		}
 		err := e.client.Delete(context.TODO(), j.DeepCopyObject())
 		if err != nil {
-			return err
+			return deleted, err
 		}
-		logger.Info("Remove a job by reaching the historyLimit", "job.Name", j.ObjectMeta.Name, "historyLimit", historyLimit)
+		deleted = append(deleted, j)
+		logger.Info("Remove a job by reaching the historyLimit", "job.Name", j.ObjectMeta.Name, "limit", limit, "historyLimit", historyLimit)
+		e.recordEvent(scaledJob, corev1.EventTypeNormal, JobDeletedByHistoryLimit,
+			"Deleted Job %s, %s %d reached", j.GetName(), limit, historyLimit)
 	}
-	return nil
+	return deleted, nil
+}
+
+// markJobPendingDelete annotates the job with PendingDeleteAnnotation, jobs already annotated are not patched again.
+// It returns true if the job was annotated
+func (e *scaleExecutor) markJobPendingDelete(logger logr.Logger, job *batchv1.Job) (bool, error) {
+	if _, ok := job.Annotations[PendingDeleteAnnotation]; ok {
+		return false, nil
+	}
+
+	patch := client.MergeFrom(job.DeepCopy())
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[PendingDeleteAnnotation] = "true"
+	err := e.client.Patch(context.TODO(), job, patch)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	logger.Info("Mark a job for deletion by reaching the historyLimit", "job.Name", job.ObjectMeta.Name)
+	return true, nil
 }
 
 type byCompletedTime []batchv1.Job
@@ -216,6 +1616,12 @@ func (c byCompletedTime) Less(i, j int) bool {
 func (c byCompletedTime) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
 
 func (e *scaleExecutor) getFinishedJobConditionType(j *batchv1.Job) batchv1.JobConditionType {
+	if isJobCompletedByProbe(j) {
+		return batchv1.JobComplete
+	}
+	if conditionType := getMainContainerTerminatedConditionType(j); conditionType != "" {
+		return conditionType
+	}
 	for _, c := range j.Status.Conditions {
 		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == v1.ConditionTrue {
 			return c.Type
@@ -2,17 +2,30 @@ package executor
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
@@ -39,80 +52,2318 @@ func TestCleanUpNormalCase(t *testing.T) {
 
 	scaleExecutor := getMockScaleExecutor(client)
 
-	scaleExecutor.cleanUp(scaledJob)
+	scaleExecutor.cleanUp(scaledJob, 0, false)
 
 	_, ok := actualDeletedJobName["name2"]
 	assert.True(t, ok)
 }
 
+func TestCleanUpLastCleanupDeletedCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// successfulJobHistoryLimit = 1
+	// failedJobHistoryLimit = 1
+	scaledJob := getMockScaledJob(1, 1)
+
+	jobs := []batchv1.Job{
+		*getJob(t, "success1", "2020-07-29T15:31:00Z", batchv1.JobComplete),
+		*getJob(t, "success2", "2020-07-29T15:32:00Z", batchv1.JobComplete),
+		*getJob(t, "success3", "2020-07-29T15:33:00Z", batchv1.JobComplete),
+		*getJob(t, "fail1", "2020-07-29T15:34:00Z", batchv1.JobFailed),
+		*getJob(t, "fail2", "2020-07-29T15:35:00Z", batchv1.JobFailed),
+	}
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = jobs
+	}).
+		Return(nil).AnyTimes()
+	client.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	var jobPatches int
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		jobPatches++
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	// the counts are patched when they change only
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(3)
+	client.EXPECT().Status().Return(statusWriter).Times(3)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	assert.Nil(t, scaleExecutor.cleanUp(scaledJob, 0, false))
+	assert.Equal(t, kedav1alpha1.CleanupDeletedCount{Completed: 2, Failed: 1}, *scaledJob.Status.LastCleanupDeletedCount)
+
+	assert.Nil(t, scaleExecutor.cleanUp(scaledJob, 0, false))
+
+	// nothing exceeds the history limits
+	jobs = []batchv1.Job{jobs[2], jobs[4]}
+	assert.Nil(t, scaleExecutor.cleanUp(scaledJob, 0, false))
+	assert.Equal(t, kedav1alpha1.CleanupDeletedCount{}, *scaledJob.Status.LastCleanupDeletedCount)
+
+	// the jobs marked for deletion by a previous cleanup are not counted again
+	scaledJob.Spec.SoftDeleteAnnotation = true
+	jobs = []batchv1.Job{
+		*getJob(t, "success1", "2020-07-29T15:31:00Z", batchv1.JobComplete),
+		*getJob(t, "success2", "2020-07-29T15:32:00Z", batchv1.JobComplete),
+		*getJob(t, "success3", "2020-07-29T15:33:00Z", batchv1.JobComplete),
+	}
+	jobs[0].Annotations = map[string]string{PendingDeleteAnnotation: "true"}
+	assert.Nil(t, scaleExecutor.cleanUp(scaledJob, 0, false))
+	assert.Equal(t, kedav1alpha1.CleanupDeletedCount{Completed: 1}, *scaledJob.Status.LastCleanupDeletedCount)
+	assert.Equal(t, 1, jobPatches)
+}
+
 func TestCleanUpMixedCaseWithSortByTime(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	// Setup ScaledJob
-	// successfulJobHistoryLimit = 1
-	// failedJobHistoryLimit = 2
-	scaledJob := getMockScaledJob(1, 2)
+	// Setup ScaledJob
+	// successfulJobHistoryLimit = 1
+	// failedJobHistoryLimit = 2
+	scaledJob := getMockScaledJob(1, 2)
+
+	var actualDeletedJobName = make(map[string]string)
+
+	// Setup current running jobs
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success3", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "fail1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail3", CompletionTime: "2020-07-29T15:38:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+
+	scaleExecutor := getMockScaleExecutor(client)
+
+	scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Equal(t, 3, len(actualDeletedJobName))
+	_, ok := actualDeletedJobName["success2"]
+	assert.True(t, ok)
+	_, ok = actualDeletedJobName["success3"]
+	assert.True(t, ok)
+	_, ok = actualDeletedJobName["fail2"]
+	assert.True(t, ok)
+}
+
+func TestCleanUpFailedJobsCleanupOrder(t *testing.T) {
+	tests := []struct {
+		order           kedav1alpha1.JobsCleanupOrder
+		expectedDeleted []string
+	}{
+		{order: kedav1alpha1.OldestJobsCleanupOrder, expectedDeleted: []string{"fail1", "fail2"}},
+		{order: kedav1alpha1.NewestJobsCleanupOrder, expectedDeleted: []string{"fail3", "fail4"}},
+	}
+
+	for _, test := range tests {
+		ctrl := gomock.NewController(t)
+
+		scaledJob := getMockScaledJob(1, 2)
+		scaledJob.Spec.FailedJobsCleanupOrder = test.order
+
+		var actualDeletedJobName = make(map[string]string)
+		client := getMockClient(t, ctrl, &[]mockJobParameter{
+			{Name: "fail3", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+			{Name: "fail1", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobFailed},
+			{Name: "fail4", CompletionTime: "2020-07-29T15:38:00Z", JobConditionType: batchv1.JobFailed},
+			{Name: "fail2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobFailed},
+		}, &actualDeletedJobName)
+
+		scaleExecutor := getMockScaleExecutor(client)
+
+		err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, len(test.expectedDeleted), len(actualDeletedJobName), "order %s", test.order)
+		for _, name := range test.expectedDeleted {
+			_, ok := actualDeletedJobName[name]
+			assert.True(t, ok, "order %s: expected %s to be deleted", test.order, name)
+		}
+		ctrl.Finish()
+	}
+}
+
+func TestCleanUpNow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(1, 1)
+	scaledJob.Annotations = map[string]string{CleanupNowAnnotation: "true"}
+
+	var actualDeletedJobName = make(map[string]string)
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:31:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:34:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "fail1", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+	var patchedScaledJobs []*kedav1alpha1.ScaledJob
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchedScaledJobs = append(patchedScaledJobs, obj.(*kedav1alpha1.ScaledJob))
+	}).
+		Return(nil)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.CleanUpNow(context.TODO(), scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"success1": "success1"}, actualDeletedJobName)
+	// the annotation is removed once the cleanup completed
+	assert.Equal(t, 1, len(patchedScaledJobs))
+	_, ok := patchedScaledJobs[0].Annotations[CleanupNowAnnotation]
+	assert.False(t, ok)
+}
+
+func TestCleanUpNowKeepsAnnotationOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(1, 1)
+	scaledJob.Annotations = map[string]string{CleanupNowAnnotation: "true"}
+
+	// no Patch is expected, the cleanup is retried
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("etcdserver: request timed out"))
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.CleanUpNow(context.TODO(), scaledJob)
+
+	assert.Error(t, err)
+	assert.Equal(t, "true", scaledJob.Annotations[CleanupNowAnnotation])
+}
+
+func TestCleanUpOwnerlessJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.UID = "scaledjob-uid"
+	scaledJob.Spec.AdoptOwnerlessJobs = true
+
+	isController := true
+	jobs := []batchv1.Job{
+		getRunningJob("owned", time.Now(), nil),
+		getRunningJob("ownerless", time.Now(), nil),
+		getRunningJob("owned-by-other", time.Now(), nil),
+		getRunningJob("spread", time.Now(), nil),
+	}
+	jobs[0].OwnerReferences = []metav1.OwnerReference{{Kind: "ScaledJob", Name: scaledJob.Name, UID: "scaledjob-uid", Controller: &isController}}
+	jobs[2].OwnerReferences = []metav1.OwnerReference{{Kind: "CronJob", Name: "other", UID: "other-uid", Controller: &isController}}
+	// the jobs spread across the jobNamespaces are ownerless by design
+	jobs[3].Namespace = "tenant-a"
+	jobs[3].Annotations = map[string]string{ScaledJobUIDAnnotation: "scaledjob-uid"}
+
+	var patchedJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = jobs
+	}).
+		Return(nil).Times(2)
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchedJobs = append(patchedJobs, obj.(*batchv1.Job))
+	}).
+		Return(nil)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(patchedJobs))
+	assert.Equal(t, "ownerless", patchedJobs[0].Name)
+	owner := metav1.GetControllerOf(patchedJobs[0])
+	assert.NotNil(t, owner)
+	assert.Equal(t, "ScaledJob", owner.Kind)
+	assert.Equal(t, scaledJob.UID, owner.UID)
+
+	// the ownerless job is only logged
+	scaledJob.Spec.AdoptOwnerlessJobs = false
+	jobs[1].OwnerReferences = nil
+
+	err = scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(patchedJobs))
+}
+
+func TestCleanUpSuccessfulJobsCleanupOrder(t *testing.T) {
+	tests := []struct {
+		order                   kedav1alpha1.JobsCleanupOrder
+		alwaysRetainLastSuccess bool
+		expectedDeleted         []string
+	}{
+		{order: kedav1alpha1.OldestJobsCleanupOrder, expectedDeleted: []string{"success1", "success2"}},
+		{order: kedav1alpha1.NewestJobsCleanupOrder, expectedDeleted: []string{"success3", "success4"}},
+		// the most recent completed job is kept, the next most recent ones are deleted
+		{order: kedav1alpha1.NewestJobsCleanupOrder, alwaysRetainLastSuccess: true, expectedDeleted: []string{"success2", "success3"}},
+	}
+
+	for _, test := range tests {
+		ctrl := gomock.NewController(t)
+
+		scaledJob := getMockScaledJob(2, 1)
+		scaledJob.Spec.SuccessfulJobsCleanupOrder = test.order
+		scaledJob.Spec.AlwaysRetainLastSuccess = test.alwaysRetainLastSuccess
+
+		var actualDeletedJobName = make(map[string]string)
+		client := getMockClient(t, ctrl, &[]mockJobParameter{
+			{Name: "success3", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobComplete},
+			{Name: "success1", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobComplete},
+			{Name: "success4", CompletionTime: "2020-07-29T15:38:00Z", JobConditionType: batchv1.JobComplete},
+			{Name: "success2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobComplete},
+		}, &actualDeletedJobName)
+
+		scaleExecutor := getMockScaleExecutor(client)
+
+		err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, len(test.expectedDeleted), len(actualDeletedJobName), "order %s", test.order)
+		for _, name := range test.expectedDeleted {
+			_, ok := actualDeletedJobName[name]
+			assert.True(t, ok, "order %s: expected %s to be deleted", test.order, name)
+		}
+		ctrl.Finish()
+	}
+}
+
+func TestCleanUpTotalJobsHistoryLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// successfulJobHistoryLimit = 3
+	// failedJobHistoryLimit = 2
+	// totalJobsHistoryLimit = 3
+	scaledJob := getMockScaledJob(3, 2)
+	totalJobsHistoryLimit := int32(3)
+	scaledJob.Spec.TotalJobsHistoryLimit = &totalJobsHistoryLimit
+
+	var actualDeletedJobName = make(map[string]string)
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:31:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:34:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success3", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success4", CompletionTime: "2020-07-29T15:38:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "fail1", CompletionTime: "2020-07-29T15:32:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail3", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	// success1 and fail1 exceed the per-category limits,
+	// success2 and fail2 are the oldest exceeding the total limit
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(actualDeletedJobName))
+	for _, name := range []string{"success1", "fail1", "success2", "fail2"} {
+		_, ok := actualDeletedJobName[name]
+		assert.True(t, ok, "expected %s to be deleted", name)
+	}
+}
+
+func TestCleanUpRecordsHistoryLimitDeletions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(3, 2)
+	totalJobsHistoryLimit := int32(3)
+	scaledJob.Spec.TotalJobsHistoryLimit = &totalJobsHistoryLimit
+
+	var actualDeletedJobName = make(map[string]string)
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:31:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:34:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success3", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success4", CompletionTime: "2020-07-29T15:38:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "fail1", CompletionTime: "2020-07-29T15:32:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail3", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+
+	scaleExecutor := getMockScaleExecutor(client)
+	recorder := record.NewFakeRecorder(10)
+	scaleExecutor.recorder = recorder
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	// every deletion names the job and the limit that triggered it
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(recorder.Events))
+	assert.Equal(t, "Normal JobDeletedByHistoryLimit Deleted Job success1, successfulJobsHistoryLimit 3 reached", <-recorder.Events)
+	assert.Equal(t, "Normal JobDeletedByHistoryLimit Deleted Job fail1, failedJobsHistoryLimit 2 reached", <-recorder.Events)
+	assert.Equal(t, "Normal JobDeletedByHistoryLimit Deleted Job success2, totalJobsHistoryLimit 3 reached", <-recorder.Events)
+	assert.Equal(t, "Normal JobDeletedByHistoryLimit Deleted Job fail2, totalJobsHistoryLimit 3 reached", <-recorder.Events)
+}
+
+func TestCleanUpSoftDeleteRecordsHistoryLimitDeletions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(1, 1)
+	scaledJob.Spec.SoftDeleteAnnotation = true
+
+	var actualDeletedJobName = make(map[string]string)
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobComplete},
+	}, &actualDeletedJobName)
+	client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	scaleExecutor := getMockScaleExecutor(client)
+	recorder := record.NewFakeRecorder(10)
+	scaleExecutor.recorder = recorder
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(recorder.Events))
+	assert.Equal(t, "Normal JobDeletedByHistoryLimit Marked Job success2 for deletion, successfulJobsHistoryLimit 1 reached", <-recorder.Events)
+}
+
+func TestCleanUpAlwaysRetainLastSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// successfulJobHistoryLimit = 0
+	// failedJobHistoryLimit = 1
+	scaledJob := getMockScaledJob(0, 1)
+	scaledJob.Spec.AlwaysRetainLastSuccess = true
+
+	jobs := []mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:31:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:34:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success3", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "fail1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+	}
+	var actualDeletedJobName = make(map[string]string)
+	scaleExecutor := getMockScaleExecutor(getMockClient(t, ctrl, &jobs, &actualDeletedJobName))
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(actualDeletedJobName))
+	_, ok := actualDeletedJobName["success3"]
+	assert.False(t, ok)
+
+	// the total limit doesn't delete the most recent completed job either
+	totalJobsHistoryLimit := int32(0)
+	scaledJob.Spec.TotalJobsHistoryLimit = &totalJobsHistoryLimit
+	actualDeletedJobName = make(map[string]string)
+	scaleExecutor = getMockScaleExecutor(getMockClient(t, ctrl, &jobs, &actualDeletedJobName))
+
+	err = scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(actualDeletedJobName))
+	_, ok = actualDeletedJobName["fail1"]
+	assert.True(t, ok)
+	_, ok = actualDeletedJobName["success3"]
+	assert.False(t, ok)
+}
+
+func TestCleanUpFailedJobPodsHistoryLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// successfulJobHistoryLimit = 1
+	// failedJobHistoryLimit = 1
+	scaledJob := getMockScaledJob(1, 1)
+	failedJobPodsHistoryLimit := int32(2)
+	scaledJob.Spec.FailedJobPodsHistoryLimit = &failedJobPodsHistoryLimit
+
+	jobs := []*batchv1.Job{
+		getJob(t, "fail1", "2020-07-29T15:37:00Z", batchv1.JobFailed),
+		getJob(t, "success1", "2020-07-29T15:36:00Z", batchv1.JobComplete),
+	}
+	// the failed job was retried 3 times, the completed job's pods are kept
+	pods := []v1.Pod{
+		getPodCreatedAt("fail1-aaaaa", "fail1", "2020-07-29T15:30:00Z"),
+		getPodCreatedAt("fail1-ccccc", "fail1", "2020-07-29T15:34:00Z"),
+		getPodCreatedAt("fail1-bbbbb", "fail1", "2020-07-29T15:32:00Z"),
+		getPodCreatedAt("fail1-ddddd", "fail1", "2020-07-29T15:36:00Z"),
+		getPodCreatedAt("success1-aaaaa", "success1", "2020-07-29T15:30:00Z"),
+		getPodCreatedAt("success1-bbbbb", "success1", "2020-07-29T15:32:00Z"),
+		getPodCreatedAt("success1-ccccc", "success1", "2020-07-29T15:34:00Z"),
+	}
+
+	var deletedPodNames []string
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		switch l := list.(type) {
+		case *batchv1.JobList:
+			for _, job := range jobs {
+				l.Items = append(l.Items, *job)
+			}
+		case *v1.PodList:
+			l.Items = pods
+		}
+	}).
+		Return(nil).Times(2)
+	client.EXPECT().
+		Delete(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.DeleteOption) {
+		pod, ok := obj.(*v1.Pod)
+		if !ok {
+			t.Error("Cast failed on v1.Pod at mocking client.Delete()")
+		}
+		deletedPodNames = append(deletedPodNames, pod.GetName())
+	}).
+		Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"fail1-aaaaa", "fail1-bbbbb"}, deletedPodNames)
+}
+
+func getPodCreatedAt(name string, jobName string, creationTime string) v1.Pod {
+	creationTimestamp, _ := time.Parse(time.RFC3339, creationTime)
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(creationTimestamp),
+			Labels:            map[string]string{"job-name": jobName},
+		},
+	}
+}
+
+func TestCleanUpSoftDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// successfulJobHistoryLimit = 1
+	// failedJobHistoryLimit = 1
+	scaledJob := getMockScaledJob(1, 1)
+	scaledJob.Spec.SoftDeleteAnnotation = true
+
+	var actualDeletedJobName = make(map[string]string)
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "fail1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+	var actualPatchedJobs []*batchv1.Job
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		actualPatchedJobs = append(actualPatchedJobs, obj.(*batchv1.Job))
+	}).
+		Return(nil).AnyTimes()
+
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualDeletedJobName))
+	assert.Equal(t, 2, len(actualPatchedJobs))
+	for _, job := range actualPatchedJobs {
+		assert.Contains(t, []string{"success2", "fail2"}, job.Name)
+		assert.Equal(t, "true", job.Annotations[PendingDeleteAnnotation])
+	}
+}
+
+func TestCleanUpDefaultValue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Setup ScaledJob
+	// The optional value is not configured 100 by default
+	// successfulJobHistoryLimit = nil
+	// failedJobHistoryLimit = nil
+	scaledJob := getMockScaledJobWithDefault()
+
+	var actualDeletedJobName = make(map[string]string)
+	mockJobParameters := make([]mockJobParameter, 202)
+	// Setup the first one will be removed.
+	mockJobParameters[0] = mockJobParameter{Name: "success0", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobComplete}
+	for i := 1; i < 101; i++ {
+		mockJobParameters[i] = mockJobParameter{Name: fmt.Sprintf("success%d", i), CompletionTime: "2020-07-29T15:40:00Z", JobConditionType: batchv1.JobComplete}
+	}
+	mockJobParameters[101] = mockJobParameter{Name: "fail101", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobFailed}
+	for i := 102; i < 202; i++ {
+		mockJobParameters[i] = mockJobParameter{Name: fmt.Sprintf("fail%d", i), CompletionTime: "2020-07-29T15:40:00Z", JobConditionType: batchv1.JobFailed}
+	}
+
+	// Setup current running jobs
+	client := getMockClient(t, ctrl, &mockJobParameters, &actualDeletedJobName)
+
+	scaleExecutor := getMockScaleExecutor(client)
+
+	scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Equal(t, 2, len(actualDeletedJobName))
+	_, ok := actualDeletedJobName["success0"]
+	assert.True(t, ok)
+	_, ok = actualDeletedJobName["fail101"]
+	assert.True(t, ok)
+}
+
+func TestCleanUpReplaceFailedJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Setup ScaledJob
+	// successfulJobHistoryLimit = 2
+	// failedJobHistoryLimit = 1
+	scaledJob := getMockScaledJob(2, 1)
+	scaledJob.Spec.ReplaceFailedJobs = true
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	var actualDeletedJobName = make(map[string]string)
+	var createdJobs []*batchv1.Job
+
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "fail1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail3", CompletionTime: "2020-07-29T15:38:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+	expectCreate(t, client, &createdJobs)
+
+	scaleExecutor := getMockScaleExecutor(client)
+
+	scaleExecutor.cleanUp(scaledJob, 10, false)
+
+	assert.Equal(t, 2, len(createdJobs))
+	assert.Equal(t, 2, len(actualDeletedJobName))
+	_, ok := actualDeletedJobName["fail1"]
+	assert.True(t, ok)
+	_, ok = actualDeletedJobName["fail2"]
+	assert.True(t, ok)
+}
+
+func TestCleanUpReplaceFailedJobsRespectsMaxScale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Setup ScaledJob
+	// successfulJobHistoryLimit = 2
+	// failedJobHistoryLimit = 0
+	scaledJob := getMockScaledJob(2, 0)
+	scaledJob.Spec.ReplaceFailedJobs = true
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	var actualDeletedJobName = make(map[string]string)
+	var createdJobs []*batchv1.Job
+
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "fail1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail3", CompletionTime: "2020-07-29T15:38:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+	expectCreate(t, client, &createdJobs)
+
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// only one replacement fits into the remaining maxScale
+	scaleExecutor.cleanUp(scaledJob, 1, false)
+
+	assert.Equal(t, 1, len(createdJobs))
+	assert.Equal(t, 3, len(actualDeletedJobName))
+}
+
+func TestCleanUpReplaceFailedJobsNotDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(2, 0)
+	scaledJob.Spec.ReplaceFailedJobs = true
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = []batchv1.Job{
+			*getJob(t, "fail1", "2020-07-29T15:37:00Z", batchv1.JobFailed),
+			*getJob(t, "fail2", "2020-07-29T15:38:00Z", batchv1.JobFailed),
+		}
+	}).
+		Return(nil).Times(2)
+	client.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("etcd unavailable")).Times(2)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// the failed jobs survive every cleanup, they are not replaced over and over
+	for i := 0; i < 2; i++ {
+		err := scaleExecutor.cleanUp(scaledJob, 10, false)
+		assert.NotNil(t, err)
+	}
+	assert.Equal(t, 0, len(createdJobs))
+}
+
+func TestCleanUpWithoutReplaceBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Setup ScaledJob
+	// successfulJobHistoryLimit = 2
+	// failedJobHistoryLimit = 1
+	scaledJob := getMockScaledJob(2, 1)
+	scaledJob.Spec.ReplaceFailedJobs = true
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	var actualDeletedJobName = make(map[string]string)
+	var createdJobs []*batchv1.Job
+
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "fail1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
+		{Name: "fail2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+	expectCreate(t, client, &createdJobs)
+
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// trigger is not active, so no budget is given for replacements
+	scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Equal(t, 0, len(createdJobs))
+	assert.Equal(t, 1, len(actualDeletedJobName))
+}
+
+func TestJobScaleExecutorMockImplementation(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	mock := &mockJobScaleExecutor{}
+
+	var jobScaleExecutor JobScaleExecutor = mock
+	jobScaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 3, time.Minute, time.Time{})
+
+	assert.Equal(t, 1, len(mock.requests))
+	assert.Equal(t, scaledJob.Name, mock.requests[0].scaledJob.Name)
+	assert.True(t, mock.requests[0].isActive)
+	assert.Equal(t, int64(5), mock.requests[0].scaleTo)
+	assert.Equal(t, int64(3), mock.requests[0].maxScale)
+	assert.Equal(t, time.Minute, mock.requests[0].backlogAge)
+}
+
+type mockJobScaleRequest struct {
+	scaledJob  *kedav1alpha1.ScaledJob
+	isActive   bool
+	scaleTo    int64
+	maxScale   int64
+	backlogAge time.Duration
+	scaledAt   time.Time
+}
+
+type mockJobScaleExecutor struct {
+	requests []mockJobScaleRequest
+}
+
+func (m *mockJobScaleExecutor) RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64, backlogAge time.Duration, scaledAt time.Time) (time.Duration, error) {
+	m.requests = append(m.requests, mockJobScaleRequest{scaledJob: scaledJob, isActive: isActive, scaleTo: scaleTo, maxScale: maxScale, backlogAge: backlogAge, scaledAt: scaledAt})
+	return 0, nil
+}
+
+func (m *mockJobScaleExecutor) FinalizeScaledJob(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error {
+	return nil
+}
+
+func (m *mockJobScaleExecutor) CleanUpNow(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error {
+	return nil
+}
+
+func (m *mockJobScaleExecutor) RestoreJobLabels(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) error {
+	return nil
+}
+
+func TestFinalizeScaledJobOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	client := mock_client.NewMockClient(ctrl)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	var order []string
+	scaleExecutor.jobFinalizers = []jobFinalizerFunc{
+		func(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+			order = append(order, "first")
+			return nil
+		},
+		func(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+			order = append(order, "second")
+			return nil
+		},
+	}
+
+	err := scaleExecutor.FinalizeScaledJob(context.TODO(), scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestFinalizeScaledJobStopsOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	client := mock_client.NewMockClient(ctrl)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	var order []string
+	scaleExecutor.jobFinalizers = []jobFinalizerFunc{
+		func(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+			order = append(order, "first")
+			return fmt.Errorf("failed")
+		},
+		func(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+			order = append(order, "second")
+			return nil
+		},
+	}
+
+	err := scaleExecutor.FinalizeScaledJob(context.TODO(), scaledJob)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"first"}, order)
+}
+
+func TestFinalizeScaledJobDeletesJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+
+	var actualDeletedJobName = make(map[string]string)
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "name1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "name2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.FinalizeScaledJob(context.TODO(), scaledJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(actualDeletedJobName))
+}
+
+func TestBoostScaleToByBacklogAge(t *testing.T) {
+	scaleExecutor := getMockScaleExecutor(nil)
+	scaledJob := getMockScaledJobWithDefault()
+	threshold := int32(60)
+	scaledJob.Spec.ScalingStrategy = &kedav1alpha1.ScalingStrategy{
+		BacklogAgeThresholdSeconds: &threshold,
+	}
+
+	// backlog younger than the threshold isn't boosted
+	assert.Equal(t, int64(4), scaleExecutor.boostScaleToByBacklogAge(scaleExecutor.logger, scaledJob.Spec.ScalingStrategy, 4, 30*time.Second))
+	// default boost is 100%
+	assert.Equal(t, int64(8), scaleExecutor.boostScaleToByBacklogAge(scaleExecutor.logger, scaledJob.Spec.ScalingStrategy, 4, 2*time.Minute))
+
+	boost := int32(50)
+	scaledJob.Spec.ScalingStrategy.BacklogAgeBoostPercentage = &boost
+	assert.Equal(t, int64(6), scaleExecutor.boostScaleToByBacklogAge(scaleExecutor.logger, scaledJob.Spec.ScalingStrategy, 4, 2*time.Minute))
+	// boost is rounded up, so at least one more job is created
+	assert.Equal(t, int64(2), scaleExecutor.boostScaleToByBacklogAge(scaleExecutor.logger, scaledJob.Spec.ScalingStrategy, 1, 2*time.Minute))
+}
+
+func TestBoostScaleToByBacklogAgeWithoutStrategy(t *testing.T) {
+	scaleExecutor := getMockScaleExecutor(nil)
+	scaledJob := getMockScaledJobWithDefault()
+
+	assert.Equal(t, int64(4), scaleExecutor.boostScaleToByBacklogAge(scaleExecutor.logger, scaledJob.Spec.ScalingStrategy, 4, time.Hour))
+}
+
+func TestCreateJobsWithCompletionsDivisor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	divisor := int32(3)
+	scaledJob.Spec.ScalingStrategy = &kedav1alpha1.ScalingStrategy{CompletionsDivisor: &divisor}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// 10 items are shared by 3 jobs with 4 completions each
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 10, 100, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, 3, len(createdJobs))
+	for _, job := range createdJobs {
+		assert.Equal(t, int32(4), *job.Spec.Completions)
+		assert.Equal(t, int32(4), *job.Spec.Parallelism)
+	}
+}
+
+func TestCreateJobsWithCompletionsDivisorRespectsMaxScale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	divisor := int32(5)
+	scaledJob.Spec.ScalingStrategy = &kedav1alpha1.ScalingStrategy{CompletionsDivisor: &divisor}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 10, 2, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, 2, len(createdJobs))
+	for _, job := range createdJobs {
+		assert.Equal(t, int32(2), *job.Spec.Completions)
+	}
+}
+
+func TestGetJobCompletions(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	assert.Nil(t, getJobCompletions(scaledJob.Spec.ScalingStrategy, 10))
+
+	divisor := int32(4)
+	scaledJob.Spec.ScalingStrategy = &kedav1alpha1.ScalingStrategy{CompletionsDivisor: &divisor}
+	assert.Equal(t, int32(3), *getJobCompletions(scaledJob.Spec.ScalingStrategy, 10))
+	assert.Equal(t, int32(1), *getJobCompletions(scaledJob.Spec.ScalingStrategy, 1))
+	assert.Nil(t, getJobCompletions(scaledJob.Spec.ScalingStrategy, 0))
+}
+
+func TestRequestJobScaleSkippedWhenNotLeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	// no calls to the client are expected, neither Create nor List
+	client := mock_client.NewMockClient(ctrl)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	SetLeaderElected(false)
+	defer SetLeaderElected(true)
+
+	requeueAfter, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), requeueAfter)
+}
+
+func TestRequestJobScaleWaitsForCacheSync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	// cold cache, no calls to the client are expected, neither Create nor List
+	client := mock_client.NewMockClient(ctrl)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	SetCacheSynced(false)
+	defer SetCacheSynced(true)
+
+	requeueAfter, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, defaultRequeueInterval*time.Second, requeueAfter)
+
+	// once synced, the jobs are created
+	var createdJobs []*batchv1.Job
+	client = getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor = getMockScaleExecutor(client)
+	SetCacheSynced(true)
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(createdJobs))
+}
+
+func TestRequestJobScaleSkippedWhenNotReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	// the controller marked the latest ScaledJob as not Ready
+	notReady := true
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ runtimeclient.ObjectKey, obj runtime.Object) {
+		latest, ok := obj.(*kedav1alpha1.ScaledJob)
+		if !ok {
+			return
+		}
+		latest.Status.Conditions = *kedav1alpha1.GetInitializedConditions()
+		if notReady {
+			latest.Status.Conditions.SetReadyCondition(metav1.ConditionFalse, "ScaledJobCheckFailed", "ScaledJob.Spec.ScalingStrategy is not valid")
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	var createdJobs []*batchv1.Job
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(createdJobs))
+
+	// Ready again
+	notReady = false
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(createdJobs))
+}
+
+func TestCreateJobsAggregatesErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	// creation of the 2nd and the 4th job fails
+	quotaErr := fmt.Errorf("quota exceeded")
+	var createCalls int
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.CreateOption) error {
+		createCalls++
+		if createCalls == 2 || createCalls == 4 {
+			return quotaErr
+		}
+		return nil
+	}).
+		Times(5)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 5, 10, time.Time{}, nil)
+
+	assert.Equal(t, int64(5), count)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "job 2 of 5: quota exceeded")
+	assert.Contains(t, err.Error(), "job 4 of 5: quota exceeded")
+	assert.NotContains(t, err.Error(), "job 1 of 5")
+	assert.NotContains(t, err.Error(), "job 3 of 5")
+	assert.NotContains(t, err.Error(), "job 5 of 5")
+	// the errors of the creations are wrapped, so the callers can inspect them
+	aggregate, ok := err.(utilerrors.Aggregate)
+	assert.True(t, ok)
+	for _, e := range aggregate.Errors() {
+		assert.True(t, goerrors.Is(e, quotaErr))
+	}
+}
+
+func TestCreateJobsStopsOnInvalidTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	invalidErr := errors.NewInvalid(schema.GroupKind{Group: "batch", Kind: "Job"}, "",
+		field.ErrorList{field.Invalid(field.NewPath("spec", "template", "spec", "volumes"), "data", "invalid volume")})
+
+	// the 2nd job is rejected, no further jobs are created
+	var createCalls int
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.CreateOption) error {
+		createCalls++
+		if createCalls == 2 {
+			return invalidErr
+		}
+		return nil
+	}).
+		Times(2)
+	// the condition is patched with the status at the end of the scaling
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 5, 10, time.Time{}, nil)
+
+	assert.Equal(t, int64(2), count)
+	assert.NotNil(t, err)
+	condition := scaledJob.Status.Conditions.GetTemplateInvalidCondition()
+	assert.True(t, condition.IsTrue())
+	assert.Equal(t, invalidErr.Error(), condition.Message)
+
+	// the template was fixed
+	client.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err = scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 1, 10, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	condition = scaledJob.Status.Conditions.GetTemplateInvalidCondition()
+	assert.True(t, condition.IsFalse())
+}
+
+func TestGetScaleToByPercentageStrategy(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	assert.Equal(t, int64(10), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 10))
+
+	percentage := int32(25)
+	scaledJob.Spec.ScalingStrategy = &kedav1alpha1.ScalingStrategy{
+		Strategy:   kedav1alpha1.PercentageScalingStrategy,
+		Percentage: &percentage,
+	}
+	assert.Equal(t, int64(3), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 10))
+	assert.Equal(t, int64(1), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 1))
+	assert.Equal(t, int64(0), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 0))
+
+	percentage = 50
+	assert.Equal(t, int64(5), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 10))
+	assert.Equal(t, int64(6), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 11))
+
+	percentage = 100
+	assert.Equal(t, int64(10), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 10))
+}
+
+func TestGetAverageJobDuration(t *testing.T) {
+	_, ok := getAverageJobDuration([]batchv1.Job{})
+	assert.False(t, ok)
+
+	start := time.Date(2020, 7, 29, 15, 0, 0, 0, time.UTC)
+	jobs := []batchv1.Job{
+		*getJob(t, "success1", "2020-07-29T15:01:00Z", batchv1.JobComplete),
+		*getJob(t, "success2", "2020-07-29T15:03:00Z", batchv1.JobComplete),
+		// not started, not counted
+		*getJob(t, "success3", "2020-07-29T15:30:00Z", batchv1.JobComplete),
+	}
+	startTime := metav1.NewTime(start)
+	jobs[0].Status.StartTime = &startTime
+	jobs[1].Status.StartTime = &startTime
+
+	duration, ok := getAverageJobDuration(jobs)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, duration)
+}
+
+func TestGetJobDuration(t *testing.T) {
+	start := metav1.NewTime(time.Date(2020, 7, 29, 15, 0, 0, 0, time.UTC))
+	job := getJob(t, "success1", "2020-07-29T15:01:00Z", batchv1.JobComplete)
+
+	// completed, but the start was never recorded
+	job.Status.StartTime = nil
+	_, ok := getJobDuration(job)
+	assert.False(t, ok)
+
+	job.Status.StartTime = &start
+	duration, ok := getJobDuration(job)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, duration)
+
+	// the completion precedes the start
+	later := metav1.NewTime(start.Add(time.Hour))
+	job.Status.StartTime = &later
+	_, ok = getJobDuration(job)
+	assert.False(t, ok)
+
+	job.Status.StartTime = &start
+	job.Status.CompletionTime = nil
+	_, ok = getJobDuration(job)
+	assert.False(t, ok)
+}
+
+func TestCleanUpAverageJobDurationWithoutStartTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the completed jobs have no StartTime, no average duration is recorded
+	scaledJob := getMockScaledJob(10, 10)
+	var actualDeletedJobName = make(map[string]string)
+	scaleExecutor := getMockScaleExecutor(getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "success1", CompletionTime: "2020-07-29T15:31:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "success2", CompletionTime: "2020-07-29T15:34:00Z", JobConditionType: batchv1.JobComplete},
+	}, &actualDeletedJobName))
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Nil(t, scaledJob.Status.AverageJobDurationSeconds)
+}
+
+func TestGetScaleToByThroughputStrategy(t *testing.T) {
+	expected := int32(120)
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.ScalingStrategy = &kedav1alpha1.ScalingStrategy{
+		Strategy:                   kedav1alpha1.ThroughputScalingStrategy,
+		ExpectedJobDurationSeconds: &expected,
+	}
+
+	// no average duration recorded yet
+	assert.Equal(t, int64(10), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 10))
+
+	// jobs complete in a quarter of the expected time
+	average := int64(30)
+	scaledJob.Status.AverageJobDurationSeconds = &average
+	assert.Equal(t, int64(3), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 10))
+	assert.Equal(t, int64(1), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 1))
+	assert.Equal(t, int64(0), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 0))
+
+	// jobs are slower than expected, not increased
+	average = 240
+	assert.Equal(t, int64(10), getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 10))
+}
+
+func TestCleanUpRecordsAverageJobDuration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(10, 10)
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		j := list.(*batchv1.JobList)
+		job := getJob(t, "success1", "2020-07-29T15:01:30Z", batchv1.JobComplete)
+		startTime := metav1.NewTime(time.Date(2020, 7, 29, 15, 0, 0, 0, time.UTC))
+		job.Status.StartTime = &startTime
+		j.Items = append(j.Items, *job)
+	}).
+		Return(nil).Times(2)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	// the unchanged duration is patched once
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	client.EXPECT().Status().Return(statusWriter).Times(1)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	assert.Nil(t, scaleExecutor.cleanUp(scaledJob, 0, false))
+	assert.Equal(t, int64(90), *scaledJob.Status.AverageJobDurationSeconds)
+
+	assert.Nil(t, scaleExecutor.cleanUp(scaledJob, 0, false))
+}
+
+func TestCreateJobsByPercentageStrategyClampedToMaxScale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	percentage := int32(50)
+	scaledJob.Spec.ScalingStrategy = &kedav1alpha1.ScalingStrategy{
+		Strategy:   kedav1alpha1.PercentageScalingStrategy,
+		Percentage: &percentage,
+	}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, getScaleToByStrategy(scaledJob, scaledJob.Spec.ScalingStrategy, 20), 4, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), count)
+	assert.Equal(t, 4, len(createdJobs))
+}
+
+func TestDeleteJobsExceedingMaxAge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	var actualDeletedJobName = make(map[string]string)
+	client := mock_client.NewMockClient(ctrl)
+	expectDelete(t, client, &actualDeletedJobName)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	jobs := []batchv1.Job{
+		// younger than maxJobAge
+		getRunningJob("young", now.Add(-5*time.Minute), nil),
+		// older than maxJobAge without a deadline
+		getRunningJob("old", now.Add(-20*time.Minute), nil),
+		// older than maxJobAge, but its deadline is still ahead, the deadline wins
+		getRunningJob("deadline-ahead", now.Add(-20*time.Minute), int64Ptr(30*60)),
+		// older than maxJobAge and its deadline already passed, maxJobAge is the backstop
+		getRunningJob("deadline-passed", now.Add(-20*time.Minute), int64Ptr(15*60)),
+		// not started yet
+		{ObjectMeta: metav1.ObjectMeta{Name: "not-started"}},
+	}
+
+	err := scaleExecutor.deleteJobsExceedingMaxAge(scaleExecutor.logger, jobs, 10*time.Minute, now)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(actualDeletedJobName))
+	_, ok := actualDeletedJobName["old"]
+	assert.True(t, ok)
+	_, ok = actualDeletedJobName["deadline-passed"]
+	assert.True(t, ok)
+}
+
+func TestCleanUpWithMaxJobAge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJob(2, 2)
+	scaledJob.Spec.MaxJobAgeSeconds = int64Ptr(60)
+
+	var actualDeletedJobName = make(map[string]string)
+	// finished jobs are not affected by the maxJobAge
+	client := getMockClient(t, ctrl, &[]mockJobParameter{
+		{Name: "name1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobComplete},
+		{Name: "name2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobFailed},
+	}, &actualDeletedJobName)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualDeletedJobName))
+}
+
+func TestGetMaxJobAge(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	_, ok := getMaxJobAge(scaledJob)
+	assert.False(t, ok)
+
+	scaledJob.Spec.MaxJobAgeSeconds = int64Ptr(600)
+	maxJobAge, ok := getMaxJobAge(scaledJob)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Minute, maxJobAge)
+
+	// no average duration recorded yet, the maxJobAgeSeconds is used
+	multiplier := int32(3)
+	scaledJob.Spec.MaxJobAgeDurationMultiplier = &multiplier
+	maxJobAge, _ = getMaxJobAge(scaledJob)
+	assert.Equal(t, 10*time.Minute, maxJobAge)
+
+	scaledJob.Status.AverageJobDurationSeconds = int64Ptr(60)
+	maxJobAge, _ = getMaxJobAge(scaledJob)
+	assert.Equal(t, 3*time.Minute, maxJobAge)
+
+	scaledJob.Spec.MaxJobAgeSeconds = nil
+	scaledJob.Status.AverageJobDurationSeconds = int64Ptr(3600)
+	maxJobAge, ok = getMaxJobAge(scaledJob)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Hour, maxJobAge)
+}
+
+func TestDeleteJobsExceedingMaxAgeByAverageDuration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	multiplier := int32(2)
+	jobs := []batchv1.Job{
+		getRunningJob("running-5m", now.Add(-5*time.Minute), nil),
+		getRunningJob("running-30m", now.Add(-30*time.Minute), nil),
+	}
+
+	// short average duration, the job running for 30 minutes is reaped
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.MaxJobAgeDurationMultiplier = &multiplier
+	scaledJob.Status.AverageJobDurationSeconds = int64Ptr(5 * 60)
+
+	var actualDeletedJobName = make(map[string]string)
+	client := mock_client.NewMockClient(ctrl)
+	expectDelete(t, client, &actualDeletedJobName)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	maxJobAge, _ := getMaxJobAge(scaledJob)
+	err := scaleExecutor.deleteJobsExceedingMaxAge(scaleExecutor.logger, jobs, maxJobAge, now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(actualDeletedJobName))
+	_, ok := actualDeletedJobName["running-30m"]
+	assert.True(t, ok)
+
+	// long average duration, no job is reaped
+	scaledJob.Status.AverageJobDurationSeconds = int64Ptr(60 * 60)
+	actualDeletedJobName = make(map[string]string)
+
+	maxJobAge, _ = getMaxJobAge(scaledJob)
+	err = scaleExecutor.deleteJobsExceedingMaxAge(scaleExecutor.logger, jobs, maxJobAge, now)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualDeletedJobName))
+}
+
+func TestCreateJobsWithNilScheme(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	// no Jobs are created
+	scaleExecutor := NewScaleExecutor(mock_client.NewMockClient(ctrl), nil, nil, nil, DefaultConfig()).(*scaleExecutor)
+
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 3, 10, time.Time{}, nil)
+
+	assert.Equal(t, errNilReconcilerScheme, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestGenerateJobWithOperatorID(t *testing.T) {
+	scaleExecutor := getMockScaleExecutor(nil)
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	job := scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	_, ok := job.Labels[OperatorIDLabel]
+	assert.False(t, ok)
+
+	scaleExecutor.config.OperatorID = "keda-1"
+
+	job = scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	assert.Equal(t, "keda-1", job.Labels[OperatorIDLabel])
+	assert.Equal(t, "keda-1", job.Spec.Template.Labels[OperatorIDLabel])
+}
+
+func TestGenerateJobWithJobNamePrefix(t *testing.T) {
+	scaleExecutor := getMockScaleExecutor(nil)
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	job := scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	assert.Equal(t, scaledJob.Name+"-", job.GenerateName)
+
+	scaledJob.Spec.JobNamePrefix = "batch-consumer-"
+	job = scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	assert.Equal(t, "batch-consumer-", job.GenerateName)
+	assert.Equal(t, scaledJob.Name, job.Labels["scaledjob"])
+}
+
+func TestCreateJobsWithDefaultTolerations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.DefaultTolerations = []v1.Toleration{
+		{Key: "spot", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+		{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "jobs", Effect: v1.TaintEffectNoSchedule},
+	}
+	// the toleration of the template for the same key and effect is kept
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{
+		Template: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				Tolerations: []v1.Toleration{
+					{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "batch", Effect: v1.TaintEffectNoSchedule},
+				},
+			},
+		},
+	}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 2, 10, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, 2, len(createdJobs))
+	for _, job := range createdJobs {
+		assert.Equal(t, []v1.Toleration{
+			{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "batch", Effect: v1.TaintEffectNoSchedule},
+			{Key: "spot", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+		}, job.Spec.Template.Spec.Tolerations)
+	}
+	// the template of the ScaledJob is not modified
+	assert.Equal(t, 1, len(scaledJob.Spec.JobTargetRef.Template.Spec.Tolerations))
+}
+
+func TestGenerateJobWithDefaultPodSecurityContext(t *testing.T) {
+	scaleExecutor := getMockScaleExecutor(nil)
+	scaledJob := getMockScaledJobWithDefault()
+	runAsNonRoot := true
+	scaledJob.Spec.DefaultPodSecurityContext = &v1.PodSecurityContext{RunAsNonRoot: &runAsNonRoot}
+
+	// the template without a security context gets the default
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	job := scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	assert.Equal(t, scaledJob.Spec.DefaultPodSecurityContext, job.Spec.Template.Spec.SecurityContext)
+	assert.False(t, scaledJob.Spec.DefaultPodSecurityContext == job.Spec.Template.Spec.SecurityContext)
+
+	// the security context of the template is kept
+	runAsUser := int64(1000)
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{
+		Template: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				SecurityContext: &v1.PodSecurityContext{RunAsUser: &runAsUser},
+			},
+		},
+	}
+	job = scaleExecutor.generateJob(scaleExecutor.logger, scaledJob)
+	assert.Equal(t, &runAsUser, job.Spec.Template.Spec.SecurityContext.RunAsUser)
+	assert.Nil(t, job.Spec.Template.Spec.SecurityContext.RunAsNonRoot)
+}
+
+func TestValidateJobNamePrefix(t *testing.T) {
+	assert.NoError(t, ValidateJobNamePrefix("batch-consumer-"))
+	assert.NoError(t, ValidateJobNamePrefix(strings.Repeat("a", 58)))
+	// the generated names would exceed 63 characters
+	assert.Error(t, ValidateJobNamePrefix(strings.Repeat("a", 59)))
+	assert.Error(t, ValidateJobNamePrefix("Batch_Consumer-"))
+}
+
+func TestGetRunningJobCountFilteredByOperatorID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, opts ...runtimeclient.ListOption) {
+		listOpts := &runtimeclient.ListOptions{}
+		listOpts.ApplyOptions(opts)
+		// the fake API server applies the label selector
+		j := list.(*batchv1.JobList)
+		for _, operator := range []string{"keda-1", "keda-2", "keda-1"} {
+			job := batchv1.Job{}
+			job.Labels = map[string]string{"scaledjob": scaledJob.Name, OperatorIDLabel: operator}
+			if listOpts.LabelSelector.Matches(labels.Set(job.Labels)) {
+				j.Items = append(j.Items, job)
+			}
+		}
+	}).
+		Return(nil)
+	scaleExecutor := getMockScaleExecutor(client)
+	scaleExecutor.config.OperatorID = "keda-1"
+
+	count, err := scaleExecutor.getRunningJobCount(scaledJob, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestGetRunningJobCountWithExcludedJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		j := list.(*batchv1.JobList)
+		j.Items = append(j.Items, getRunningJob("name1", time.Now(), nil), getRunningJob("name2", time.Now(), nil))
+		debugJob := getRunningJob("debug", time.Now(), nil)
+		debugJob.Labels = map[string]string{ExcludeFromCountLabel: "true"}
+		countedJob := getRunningJob("name3", time.Now(), nil)
+		countedJob.Labels = map[string]string{ExcludeFromCountLabel: "false"}
+		j.Items = append(j.Items, debugJob, countedJob)
+	}).
+		Return(nil)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.getRunningJobCount(scaledJob, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestGetRunningJobCountWithSuspendedJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		j := list.(*batchv1.JobList)
+		runningJob := getRunningJob("running", time.Now(), nil)
+		runningJob.Spec.Parallelism = int32Ptr(2)
+		// the parallelism is 0, but the Job wasn't paused
+		scaledDownJob := getRunningJob("scaled-down", time.Now(), nil)
+		scaledDownJob.Spec.Parallelism = int32Ptr(0)
+		j.Items = append(j.Items, getRunningJob("name1", time.Now(), nil), getSuspendedJob("suspended", time.Now()), runningJob, scaledDownJob)
+	}).
+		Return(nil)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.getRunningJobCount(scaledJob, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestGetRunningJobCountByPods(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.CountRunningPods = true
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		j := list.(*batchv1.JobList)
+		// the parallelism was raised by hand
+		editedJob := getRunningJob("edited", time.Now(), nil)
+		editedJob.Spec.Parallelism = int32Ptr(3)
+		editedJob.Status.Active = 3
+		// the pods are not active yet
+		startingJob := getRunningJob("starting", time.Now(), nil)
+		startingJob.Spec.Parallelism = int32Ptr(2)
+		finishedJob := *getJob(t, "finished", "2020-07-29T15:31:00Z", batchv1.JobComplete)
+		j.Items = append(j.Items, getRunningJob("name1", time.Now(), nil), editedJob, startingJob, getSuspendedJob("suspended", time.Now()), finishedJob)
+	}).
+		Return(nil)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.getRunningJobCount(scaledJob, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), count)
+}
+
+func TestRequestJobScaleCountRunningPods(t *testing.T) {
+	tests := []struct {
+		name                string
+		parallelism         *int32
+		expectedCreatedJobs int
+	}{
+		{name: "default parallelism", parallelism: nil, expectedCreatedJobs: 2},
+		{name: "parallelism 2", parallelism: int32Ptr(2), expectedCreatedJobs: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			scaledJob := getMockScaledJobWithDefault()
+			scaledJob.Spec.CountRunningPods = true
+			scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{Parallelism: test.parallelism}
+
+			client := mock_client.NewMockClient(ctrl)
+			client.EXPECT().
+				List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+				if j, ok := list.(*batchv1.JobList); ok {
+					editedJob := getRunningJob("edited", time.Now(), nil)
+					editedJob.Spec.Parallelism = int32Ptr(3)
+					editedJob.Status.Active = 3
+					j.Items = append(j.Items, getRunningJob("name1", time.Now(), nil), editedJob)
+				}
+			}).
+				Return(nil).AnyTimes()
+			statusWriter := mock_client.NewMockStatusWriter(ctrl)
+			statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			client.EXPECT().Status().Return(statusWriter).AnyTimes()
+			client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			var createdJobs []*batchv1.Job
+			expectCreate(t, client, &createdJobs)
+			scaleExecutor := getMockScaleExecutor(client)
+
+			// 4 of the 6 pods are running, the 2 free pods are shared by the new jobs
+			_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 6, 0, time.Time{})
+			assert.Nil(t, err)
+			assert.Equal(t, int64(4), *scaledJob.Status.RunningJobCount)
+			assert.Equal(t, test.expectedCreatedJobs, len(createdJobs))
+		})
+	}
+}
+
+func TestGetRunningJobCountRetriesOnListError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+
+	client := mock_client.NewMockClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.NewServiceUnavailable("etcd leader changed")),
+		client.EXPECT().
+			List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+			j := list.(*batchv1.JobList)
+			j.Items = append(j.Items, getRunningJob("name1", time.Now(), nil))
+		}).
+			Return(nil),
+	)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.getRunningJobCount(scaledJob, 10)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestGetRunningJobCountRetriesAreBounded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.NewServiceUnavailable("etcd leader changed")).Times(maxJobListAttempts)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.getRunningJobCount(scaledJob, 10)
+
+	assert.NotNil(t, err)
+}
+
+func TestDeleteOrphanedPods(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	isController := true
+
+	var actualDeletedPodName = make(map[string]string)
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		p := list.(*v1.PodList)
+		p.Items = []v1.Pod{
+			// owned by an existing job
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod1", OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "job1", Controller: &isController}}}},
+			// owned by a job that is gone
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod2", OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "job2", Controller: &isController}}}},
+			// owner reference was already removed
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod3", Labels: map[string]string{"job-name": "job3"}}},
+			// owner reference was already removed, but the job exists
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod4", Labels: map[string]string{"job-name": "job1"}}},
+			// created after the jobs were listed, its job may be missing from the list
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod5", CreationTimestamp: metav1.Now(), OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "job5", Controller: &isController}}}},
+		}
+	}).
+		Return(nil)
+	client.EXPECT().
+		Delete(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.DeleteOption) {
+		pod := obj.(*v1.Pod)
+		actualDeletedPodName[pod.GetName()] = pod.GetName()
+	}).
+		Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	jobs := []batchv1.Job{{ObjectMeta: metav1.ObjectMeta{Name: "job1"}}}
+	err := scaleExecutor.deleteOrphanedPods(scaleExecutor.logger, scaledJob, jobs, time.Now())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(actualDeletedPodName))
+	_, ok := actualDeletedPodName["pod2"]
+	assert.True(t, ok)
+	_, ok = actualDeletedPodName["pod3"]
+	assert.True(t, ok)
+}
+
+func TestRequestJobScaleRequeueInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// not active, the regular pollingInterval is used
+	requeueAfter, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), requeueAfter)
+
+	// all the requested jobs were created, the regular pollingInterval is used
+	requeueAfter, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), requeueAfter)
+
+	// still scaling, maxScale was reached
+	requeueAfter, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 20, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, defaultRequeueInterval*time.Second, requeueAfter)
+
+	requeueInterval := int32(2)
+	scaledJob.Spec.RequeueInterval = &requeueInterval
+	requeueAfter, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 20, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2*time.Second, requeueAfter)
+}
+
+func TestRequestJobScaleAtCapacityRequeueInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	requeueInterval := int32(5)
+	scaledJob.Spec.RequeueInterval = &requeueInterval
+	atCapacityRequeueInterval := int32(1)
+	scaledJob.Spec.AtCapacityRequeueInterval = &atCapacityRequeueInterval
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// some of the requested jobs fit into maxScale
+	requeueAfter, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 20, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Second, requeueAfter)
+
+	// maxScale is reached, no job fits
+	requeueAfter, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 20, 0, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Second, requeueAfter)
+
+	// nothing is requested
+	requeueAfter, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 0, 0, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), requeueAfter)
+
+	// the requeueInterval is used by default
+	scaledJob.Spec.AtCapacityRequeueInterval = nil
+	requeueAfter, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 20, 0, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Second, requeueAfter)
+}
+
+func TestRequestJobScaleRequeueIntervalOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	// the running jobs can't be counted, no jobs are created
+	client := getMockClientForRequestJobScale(ctrl, fmt.Errorf("list failed"))
+	scaleExecutor := getMockScaleExecutor(client)
+
+	requeueAfter, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 10, 0, time.Time{})
+	assert.NotNil(t, err)
+	assert.Equal(t, defaultRequeueInterval*time.Second, requeueAfter)
+}
+
+func TestRequestJobScaleRunningJobCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		if j, ok := list.(*batchv1.JobList); ok {
+			j.Items = append(j.Items, getRunningJob("name1", time.Now(), nil), getRunningJob("name2", time.Now(), nil))
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	// the unchanged number is patched once
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	client.EXPECT().Status().Return(statusWriter).Times(1)
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), *scaledJob.Status.RunningJobCount)
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 10, 0, time.Time{})
+	assert.Nil(t, err)
+}
+
+func TestRequestJobScaleRecordsScalingDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		if j, ok := list.(*batchv1.JobList); ok {
+			j.Items = append(j.Items, getRunningJob("name1", time.Now(), nil), getRunningJob("name2", time.Now(), nil))
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	var createdJobs []*batchv1.Job
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	recorder := record.NewFakeRecorder(10)
+	scaleExecutor.recorder = recorder
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 5, 4, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Normal ScalingDecision active=true scaleTo=5 maxScale=4 running=2 created=2", <-recorder.Events)
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 4, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Normal ScalingDecision active=false scaleTo=0 maxScale=4 running=2 created=0", <-recorder.Events)
+}
+
+func TestRequestJobScaleWithoutRecorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the completed job exceeds the history limit, its deletion records an event too
+	scaledJob := getMockScaledJob(0, 0)
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	deletedJobName := make(map[string]string)
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		if j, ok := list.(*batchv1.JobList); ok {
+			j.Items = append(j.Items, *getJob(t, "success1", "2020-07-29T15:31:00Z", batchv1.JobComplete))
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	expectCreate(t, client, &createdJobs)
+	expectDelete(t, client, &deletedJobName)
+
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
+	scaleExecutor := NewScaleExecutor(client, nil, scheme, nil, DefaultConfig())
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 2, 10, 0, time.Time{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	assert.Equal(t, map[string]string{"success1": "success1"}, deletedJobName)
+}
+
+func TestRequestJobScaleConcurrentCleanup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.ConcurrentCleanup = true
 
-	var actualDeletedJobName = make(map[string]string)
+	// the running jobs are counted, the cleanup fails to list the jobs
+	var listCalls int32
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ runtime.Object, _ ...runtimeclient.ListOption) error {
+		if atomic.AddInt32(&listCalls, 1) > 1 {
+			return fmt.Errorf("list failed")
+		}
+		return nil
+	}).
+		Times(2)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
 
-	// Setup current running jobs
-	client := getMockClient(t, ctrl, &[]mockJobParameter{
-		{Name: "success1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobComplete},
-		{Name: "success2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobComplete},
-		{Name: "success3", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobComplete},
-		{Name: "fail1", CompletionTime: "2020-07-29T15:37:00Z", JobConditionType: batchv1.JobFailed},
-		{Name: "fail2", CompletionTime: "2020-07-29T15:36:00Z", JobConditionType: batchv1.JobFailed},
-		{Name: "fail3", CompletionTime: "2020-07-29T15:38:00Z", JobConditionType: batchv1.JobFailed},
-	}, &actualDeletedJobName)
+	// the jobs can't be created without a jobTargetRef
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 2, 10, 0, time.Time{})
+
+	aggregate, ok := err.(utilerrors.Aggregate)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(aggregate.Errors()))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&listCalls))
+}
+
+func TestCreateJobRetriesOnAlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	client := mock_client.NewMockClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.NewAlreadyExists(batchv1.Resource("jobs"), "azure-storage-queue-consumer-abcde")),
+		client.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil),
+	)
 	scaleExecutor := getMockScaleExecutor(client)
 
-	scaleExecutor.cleanUp(scaledJob)
+	err := scaleExecutor.createJob(scaleExecutor.logger, scaleExecutor.generateJob(scaleExecutor.logger, scaledJob))
 
-	assert.Equal(t, 3, len(actualDeletedJobName))
-	_, ok := actualDeletedJobName["success2"]
-	assert.True(t, ok)
-	_, ok = actualDeletedJobName["success3"]
-	assert.True(t, ok)
-	_, ok = actualDeletedJobName["fail2"]
-	assert.True(t, ok)
+	assert.Nil(t, err)
 }
 
-func TestCleanUpDefaultValue(t *testing.T) {
+func TestCreateJobRetriesAreBounded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	// Setup ScaledJob
-	// The optional value is not configured 100 by default
-	// successfulJobHistoryLimit = nil
-	// failedJobHistoryLimit = nil
 	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
 
-	var actualDeletedJobName = make(map[string]string)
-	mockJobParameters := make([]mockJobParameter, 202)
-	// Setup the first one will be removed.
-	mockJobParameters[0] = mockJobParameter{Name: "success0", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobComplete}
-	for i := 1; i < 101; i++ {
-		mockJobParameters[i] = mockJobParameter{Name: fmt.Sprintf("success%d", i), CompletionTime: "2020-07-29T15:40:00Z", JobConditionType: batchv1.JobComplete}
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(errors.NewAlreadyExists(batchv1.Resource("jobs"), "azure-storage-queue-consumer-abcde")).
+		Times(maxJobCreateAttempts)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.createJob(scaleExecutor.logger, scaleExecutor.generateJob(scaleExecutor.logger, scaledJob))
+
+	assert.True(t, errors.IsAlreadyExists(err))
+}
+
+func TestCreateJobsAnnotatedWithScaledAt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	scaledAt := time.Date(2020, 7, 29, 15, 37, 0, 0, time.UTC)
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 2, 10, scaledAt, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	for _, job := range createdJobs {
+		assert.Equal(t, "2020-07-29T15:37:00Z", job.Annotations[ScaledAtAnnotation])
 	}
-	mockJobParameters[101] = mockJobParameter{Name: "fail101", CompletionTime: "2020-07-29T15:35:00Z", JobConditionType: batchv1.JobFailed}
-	for i := 102; i < 202; i++ {
-		mockJobParameters[i] = mockJobParameter{Name: fmt.Sprintf("fail%d", i), CompletionTime: "2020-07-29T15:40:00Z", JobConditionType: batchv1.JobFailed}
+
+	// no annotation without a timestamp
+	createdJobs = nil
+	_, err = scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 1, 10, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	_, ok := createdJobs[0].Annotations[ScaledAtAnnotation]
+	assert.False(t, ok)
+}
+
+func TestRequestJobScaleOverCapacityCondition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		j, ok := list.(*batchv1.JobList)
+		if ok {
+			for _, name := range []string{"name1", "name2", "name3"} {
+				j.Items = append(j.Items, getRunningJob(name, time.Now(), nil))
+			}
+		}
+	}).
+		Return(nil).AnyTimes()
+	patchCount := 0
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchCount++
+	}).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+	// the number of running jobs is already recorded
+	scaledJob.Status.RunningJobCount = int64Ptr(3)
+	scaledJob.Spec.MaxReplicaCount = int32Ptr(5)
+
+	// within maxReplicaCount, the condition is not added
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 5, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, kedav1alpha1.ConditionType(""), scaledJob.Status.Conditions.GetOverCapacityCondition().Type)
+	assert.Equal(t, 0, patchCount)
+
+	// the queue drained, maxScale dropped below the number of running jobs, but they are still within maxReplicaCount
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 0, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, kedav1alpha1.ConditionType(""), scaledJob.Status.Conditions.GetOverCapacityCondition().Type)
+	assert.Equal(t, 0, patchCount)
+
+	// maxReplicaCount was reduced below the number of running jobs
+	scaledJob.Spec.MaxReplicaCount = int32Ptr(2)
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 2, 0, time.Time{})
+	assert.Nil(t, err)
+	condition := scaledJob.Status.Conditions.GetOverCapacityCondition()
+	assert.True(t, condition.IsTrue())
+	assert.Equal(t, "RunningJobsExceedMaxReplicaCount", condition.Reason)
+	assert.Equal(t, 1, patchCount)
+
+	// unchanged condition is not patched again
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 2, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, patchCount)
+
+	// back within maxReplicaCount
+	scaledJob.Spec.MaxReplicaCount = int32Ptr(3)
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 3, 0, time.Time{})
+	assert.Nil(t, err)
+	condition = scaledJob.Status.Conditions.GetOverCapacityCondition()
+	assert.True(t, condition.IsFalse())
+	assert.Equal(t, 2, patchCount)
+}
+
+func TestCreateJobsInjectScaleEnv(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{
+		Template: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "main", Env: []v1.EnvVar{{Name: "FOO", Value: "bar"}, {Name: TriggerEnv, Value: "overridden"}}},
+					{Name: "sidecar"},
+				},
+			},
+		},
+	}
+	scaledJob.Spec.Triggers = []kedav1alpha1.ScaleTriggers{
+		{Type: "azure-queue"},
+		{Type: "rabbitmq", Name: "orders"},
 	}
+	scaledJob.Spec.InjectScaleEnv = true
 
-	// Setup current running jobs
-	client := getMockClient(t, ctrl, &mockJobParameters, &actualDeletedJobName)
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 2, 10, time.Time{}, getScaleEnv(scaledJob, 42))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	for _, job := range createdJobs {
+		env := job.Spec.Template.Spec.Containers[0].Env
+		assert.Equal(t, []v1.EnvVar{
+			{Name: "FOO", Value: "bar"},
+			{Name: TriggerEnv, Value: "azure-queue,orders"},
+			{Name: ScaleValueEnv, Value: "42"},
+		}, env)
+		assert.Empty(t, job.Spec.Template.Spec.Containers[1].Env)
+	}
+	// the template of the ScaledJob is not modified
+	assert.Equal(t, 2, len(scaledJob.Spec.JobTargetRef.Template.Spec.Containers[0].Env))
+
+	// disabled, no env vars are injected
+	scaledJob.Spec.InjectScaleEnv = false
+	assert.Nil(t, getScaleEnv(scaledJob, 42))
+}
+
+func TestCreateJobsWithShardedCreation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{
+		Template: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "main"}},
+			},
+		},
+	}
+	scaledJob.Spec.ShardedCreation = true
 
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	expectCreate(t, client, &createdJobs)
 	scaleExecutor := getMockScaleExecutor(client)
 
-	scaleExecutor.cleanUp(scaledJob)
+	// scaleTo is capped by maxScale, the shards are counted from the created jobs
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 6, 4, time.Time{}, nil)
 
-	assert.Equal(t, 2, len(actualDeletedJobName))
-	_, ok := actualDeletedJobName["success0"]
-	assert.True(t, ok)
-	_, ok = actualDeletedJobName["fail101"]
-	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(createdJobs))
+	shardIndices := map[string]bool{}
+	for _, job := range createdJobs {
+		env := job.Spec.Template.Spec.Containers[0].Env
+		assert.Equal(t, 2, len(env))
+		assert.Equal(t, ShardIndexEnv, env[0].Name)
+		assert.Equal(t, v1.EnvVar{Name: ShardTotalEnv, Value: "4"}, env[1])
+		shardIndices[env[0].Value] = true
+	}
+	assert.Equal(t, map[string]bool{"0": true, "1": true, "2": true, "3": true}, shardIndices)
+	// the template of the ScaledJob is not modified
+	assert.Empty(t, scaledJob.Spec.JobTargetRef.Template.Spec.Containers[0].Env)
+}
+
+func TestRequestJobScaleScaledFromZero(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.ObjectMeta.Name = "scaled-from-zero"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	runningJobs := []batchv1.Job{}
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		if j, ok := list.(*batchv1.JobList); ok {
+			j.Items = append(j.Items, runningJobs...)
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	// the Jobs are deleted with the ScaledJob
+	client.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	var createdJobs []*batchv1.Job
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	scaledFromZero := scaledFromZeroTotal.WithLabelValues(scaledJob.Namespace, scaledJob.Name)
+
+	// nothing to create
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 0, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(0), testutil.ToFloat64(scaledFromZero))
+
+	// 0 to N
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 2, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(scaledFromZero))
+	// the created jobs are not running yet
+	assert.Equal(t, float64(2), testutil.ToFloat64(scaleGap.WithLabelValues(scaledJob.Namespace, scaledJob.Name)))
+
+	// N to N+1
+	runningJobs = append(runningJobs, getRunningJob("name1", time.Now(), nil))
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(scaledFromZero))
+
+	// the counter is removed with the ScaledJob, a recreated ScaledJob starts from 0
+	assert.Nil(t, scaleExecutor.FinalizeScaledJob(context.TODO(), scaledJob))
+	assert.Equal(t, float64(0), testutil.ToFloat64(scaledFromZeroTotal.WithLabelValues(scaledJob.Namespace, scaledJob.Name)))
+}
+
+func TestRequestJobScaleWithNilJobTargetRef(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.ReplaceFailedJobs = true
+
+	// no jobs are created
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	count, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 2, 10, time.Time{}, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	assert.NotPanics(t, func() {
+		_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 2, 10, 0, time.Time{})
+	})
+	assert.NotNil(t, err)
+}
+
+func TestRequestJobScaleLastError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+
+	var createdJobs []*batchv1.Job
+	client := getMockClientForRequestJobScale(ctrl, nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// the jobs can't be created without a jobTargetRef
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 2, 10, 0, time.Time{})
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), scaledJob.Status.LastError)
+	assert.NotNil(t, scaledJob.Status.LastErrorTime)
+
+	// the error is cleared once the scaling succeeds
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 2, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	assert.Equal(t, "", scaledJob.Status.LastError)
+	assert.Nil(t, scaledJob.Status.LastErrorTime)
+}
+
+func TestRequestJobScaleWithNegativeInputs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "negative-inputs"
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	// no jobs are listed nor created
+	client := mock_client.NewMockClient(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+	counter := invalidScaleInputsTotal.WithLabelValues(scaledJob.Namespace, scaledJob.Name)
+
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, -1, 10, 0, time.Time{})
+	assert.NotNil(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(counter))
+
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 2, -10, 0, time.Time{})
+	assert.NotNil(t, err)
+	assert.Equal(t, float64(2), testutil.ToFloat64(counter))
+	assert.Equal(t, err.Error(), scaledJob.Status.LastError)
+}
+
+func TestCreateJobsWithBatchAffinity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.BatchAffinityTopologyKey = "kubernetes.io/hostname"
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 2, 10, time.Time{}, nil)
+	assert.Nil(t, err)
+	_, err = scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 1, 10, time.Time{}, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(createdJobs))
+	batchIDs := []string{}
+	for _, job := range createdJobs {
+		batchID := job.Spec.Template.Labels[BatchIDLabel]
+		assert.NotEmpty(t, batchID)
+		batchIDs = append(batchIDs, batchID)
+
+		terms := job.Spec.Template.Spec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		assert.Equal(t, 1, len(terms))
+		assert.Equal(t, "kubernetes.io/hostname", terms[0].PodAffinityTerm.TopologyKey)
+		assert.Equal(t, map[string]string{BatchIDLabel: batchID}, terms[0].PodAffinityTerm.LabelSelector.MatchLabels)
+	}
+	// the jobs of the same batch share the id
+	assert.Equal(t, batchIDs[0], batchIDs[1])
+	assert.NotEqual(t, batchIDs[0], batchIDs[2])
+	// the template of the ScaledJob is not modified
+	assert.Nil(t, scaledJob.Spec.JobTargetRef.Template.Spec.Affinity)
+}
+
+func TestCreateJobsWithoutBatchAffinity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 1, 10, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	_, ok := createdJobs[0].Spec.Template.Labels[BatchIDLabel]
+	assert.False(t, ok)
+	assert.Nil(t, createdJobs[0].Spec.Template.Spec.Affinity)
 }
 
 type mockJobParameter struct {
@@ -122,11 +2373,15 @@ type mockJobParameter struct {
 }
 
 func getMockScaleExecutor(client *mock_client.MockClient) *scaleExecutor {
+	scheme := runtime.NewScheme()
+	_ = kedav1alpha1.AddToScheme(scheme)
 	return &scaleExecutor{
 		client:           client,
 		scaleClient:      nil,
-		reconcilerScheme: nil,
+		reconcilerScheme: scheme,
 		logger:           logf.Log.WithName("scaleexecutor"),
+		recorder:         &record.FakeRecorder{},
+		config:           DefaultConfig(),
 	}
 }
 
@@ -164,8 +2419,16 @@ func getMockClient(t *testing.T, ctrl *gomock.Controller, jobs *[]mockJobParamet
 	}).
 		Return(nil)
 
+	expectDelete(t, client, deletedJobName)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	return client
+}
+
+func expectDelete(t *testing.T, client *mock_client.MockClient, deletedJobName *map[string]string) {
 	client.EXPECT().
-		Delete(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object) {
+		Delete(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.DeleteOption) {
 		j, ok := obj.(*batchv1.Job)
 		if !ok {
 			t.Error("Cast failed on batchv1.Job at mocking client.Delete()")
@@ -173,9 +2436,63 @@ func getMockClient(t *testing.T, ctrl *gomock.Controller, jobs *[]mockJobParamet
 		(*deletedJobName)[j.GetName()] = j.GetName()
 	}).
 		Return(nil).AnyTimes()
+}
+
+// getMockClientForRequestJobScale returns a client without any Jobs, listing returns the passed error
+func getMockClientForRequestJobScale(ctrl *gomock.Controller, listErr error) *mock_client.MockClient {
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(listErr).AnyTimes()
+
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	return client
 }
 
+func expectCreate(t *testing.T, client *mock_client.MockClient, createdJobs *[]*batchv1.Job) {
+	client.EXPECT().
+		Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...runtimeclient.CreateOption) {
+		j, ok := obj.(*batchv1.Job)
+		if !ok {
+			t.Error("Cast failed on batchv1.Job at mocking client.Create()")
+		}
+		*createdJobs = append(*createdJobs, j)
+	}).
+		Return(nil).AnyTimes()
+}
+
+func getRunningJob(name string, startTime time.Time, activeDeadlineSeconds *int64) batchv1.Job {
+	startTimeT := metav1.NewTime(startTime)
+	return batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds: activeDeadlineSeconds,
+		},
+		Status: batchv1.JobStatus{
+			StartTime: &startTimeT,
+		},
+	}
+}
+
+// getSuspendedJob returns a running Job paused by setting its parallelism to 0
+func getSuspendedJob(name string, startTime time.Time) batchv1.Job {
+	job := getRunningJob(name, startTime, nil)
+	job.Annotations = map[string]string{PausedParallelismAnnotation: "2"}
+	job.Spec.Parallelism = int32Ptr(0)
+	return job
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
 func getJob(t *testing.T, name string, completionTime string, jobConditionType batchv1.JobConditionType) *batchv1.Job {
 	parsedCompletionTime, err := time.Parse(time.RFC3339, completionTime)
 	completionTimeT := metav1.NewTime(parsedCompletionTime)
@@ -198,3 +2515,72 @@ func getJob(t *testing.T, name string, completionTime string, jobConditionType b
 		},
 	}
 }
+
+func TestGetRunningJobCountListsFromCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Namespace = "default"
+
+	// any call reaching the API server fails the test, the mock has no expectations
+	apiClient := mock_client.NewMockClient(ctrl)
+	cache := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, getCachedJobs(scaledJob, 3)...)
+
+	e := getMockScaleExecutor(nil)
+	e.client = getCacheBackedClient(cache, apiClient)
+
+	runningJobCount, err := e.getRunningJobCount(scaledJob, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), runningJobCount)
+}
+
+func BenchmarkGetRunningJobCount(b *testing.B) {
+	ctrl := gomock.NewController(b)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Namespace = "default"
+
+	for _, jobCount := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("%d jobs", jobCount), func(b *testing.B) {
+			cache := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, getCachedJobs(scaledJob, jobCount)...)
+			e := getMockScaleExecutor(nil)
+			e.client = getCacheBackedClient(cache, mock_client.NewMockClient(ctrl))
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := e.getRunningJobCount(scaledJob, int64(jobCount)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// getCacheBackedClient returns a client reading from the cache and writing to the API server,
+// the way the manager's client does
+func getCacheBackedClient(cache runtimeclient.Reader, apiClient runtimeclient.Client) runtimeclient.Client {
+	return &runtimeclient.DelegatingClient{
+		Reader: &runtimeclient.DelegatingReader{
+			CacheReader:  cache,
+			ClientReader: apiClient,
+		},
+		Writer:       apiClient,
+		StatusClient: apiClient,
+	}
+}
+
+func getCachedJobs(scaledJob *kedav1alpha1.ScaledJob, count int) []runtime.Object {
+	jobs := make([]runtime.Object, 0, count)
+	for i := 0; i < count; i++ {
+		jobs = append(jobs, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", scaledJob.GetName(), i),
+				Namespace: scaledJob.GetNamespace(),
+				Labels:    map[string]string{"scaledjob": scaledJob.GetName()},
+			},
+		})
+	}
+	return jobs
+}
@@ -0,0 +1,58 @@
+package executor
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ScaleMetadataVolume is the name of the downward API volume with the labels and annotations of the pod
+	ScaleMetadataVolume = "keda-scale-metadata"
+	// ScaleMetadataMountPath is the path the downward API volume is mounted at, the labels and annotations
+	// of the pod are in the files "labels" and "annotations"
+	ScaleMetadataMountPath = "/etc/keda/metadata"
+)
+
+// scaleMetadataLabels are the labels set on the Job by KEDA that are copied to its pods
+var scaleMetadataLabels = []string{"scaledjob", TriggerLabel, BatchIDLabel, ShardIndexLabel}
+
+// injectScaleMetadataVolume copies the labels and annotations set on the Job by KEDA to its pods and mounts
+// them into the containers of the Job with a downward API volume, so the workload can read its scale context
+// from files. The downward API only exposes the metadata of the pod, not of the Job
+func injectScaleMetadataVolume(job *batchv1.Job) {
+	template := &job.Spec.Template
+	if template.Labels == nil {
+		template.Labels = map[string]string{}
+	}
+	for _, key := range scaleMetadataLabels {
+		if value, ok := job.Labels[key]; ok {
+			template.Labels[key] = value
+		}
+	}
+	if scaledAt, ok := job.Annotations[ScaledAtAnnotation]; ok {
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[ScaledAtAnnotation] = scaledAt
+	}
+
+	podSpec := &template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: ScaleMetadataVolume,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{Path: "labels", FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+					{Path: "annotations", FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations"}},
+				},
+			},
+		},
+	})
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      ScaleMetadataVolume,
+			MountPath: ScaleMetadataMountPath,
+			ReadOnly:  true,
+		})
+	}
+}
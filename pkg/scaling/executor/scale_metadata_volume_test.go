@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestCreateJobsWithScaleMetadataVolume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main"}, {Name: "sidecar"}},
+			},
+		},
+	}
+	scaledJob.Spec.InjectScaleMetadataVolume = true
+	scaledJob.Spec.ShardedCreation = true
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	scaledAt := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 2, 10, scaledAt, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	for _, job := range createdJobs {
+		template := job.Spec.Template
+		// the metadata set on the job is exposed to the pods
+		assert.Equal(t, job.Labels["scaledjob"], template.Labels["scaledjob"])
+		assert.NotEmpty(t, template.Labels[ShardIndexLabel])
+		assert.Equal(t, job.Labels[ShardIndexLabel], template.Labels[ShardIndexLabel])
+		assert.Equal(t, "2021-01-01T12:00:00Z", template.Annotations[ScaledAtAnnotation])
+
+		assert.Equal(t, 1, len(template.Spec.Volumes))
+		volume := template.Spec.Volumes[0]
+		assert.Equal(t, ScaleMetadataVolume, volume.Name)
+		assert.NotNil(t, volume.DownwardAPI)
+		assert.Equal(t, []corev1.DownwardAPIVolumeFile{
+			{Path: "labels", FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+			{Path: "annotations", FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations"}},
+		}, volume.DownwardAPI.Items)
+		for _, container := range template.Spec.Containers {
+			assert.Equal(t, []corev1.VolumeMount{
+				{Name: ScaleMetadataVolume, MountPath: ScaleMetadataMountPath, ReadOnly: true},
+			}, container.VolumeMounts)
+		}
+	}
+	// the template of the ScaledJob is not modified
+	assert.Empty(t, scaledJob.Spec.JobTargetRef.Template.Spec.Volumes)
+	assert.Empty(t, scaledJob.Spec.JobTargetRef.Template.Annotations)
+}
+
+func TestCreateJobsWithoutScaleMetadataVolume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main"}},
+			},
+		},
+	}
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 1, 10, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(createdJobs))
+	assert.Empty(t, createdJobs[0].Spec.Template.Spec.Volumes)
+	assert.Empty(t, createdJobs[0].Spec.Template.Spec.Containers[0].VolumeMounts)
+}
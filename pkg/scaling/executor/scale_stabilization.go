@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// getStabilizedScaleTo records scaleTo as the latest sample of the ScaledJob and returns the highest scaleTo
+// of the samples within the ScaleUpStabilizationWindow, the samples are kept in the status so they survive restarts
+func getStabilizedScaleTo(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, scaleTo int64) int64 {
+	window := getScaleUpStabilizationWindow(scaledJob)
+	if window <= 1 {
+		return scaleTo
+	}
+
+	samples := appendScaleToSample(scaledJob.Status.RecentScaleTo, scaleTo, window)
+	if !isEqualSamples(scaledJob.Status.RecentScaleTo, samples) {
+		scaledJob.Status.RecentScaleTo = samples
+	}
+
+	stabilized := scaleTo
+	for _, sample := range samples {
+		if sample > stabilized {
+			stabilized = sample
+		}
+	}
+	if stabilized > scaleTo {
+		logger.V(1).Info("Stabilizing scaleTo at the highest of the recent polls", "scaleTo", scaleTo, "stabilizedScaleTo", stabilized)
+	}
+	return stabilized
+}
+
+func getScaleUpStabilizationWindow(scaledJob *kedav1alpha1.ScaledJob) int {
+	modifiers := scaledJob.Spec.ScalingModifiers
+	if modifiers == nil || modifiers.ScaleUpStabilizationWindow == nil {
+		return 0
+	}
+	return int(*modifiers.ScaleUpStabilizationWindow)
+}
+
+// appendScaleToSample returns the samples with scaleTo appended, only the last window samples are kept
+func appendScaleToSample(samples []int64, scaleTo int64, window int) []int64 {
+	result := make([]int64, 0, window)
+	if dropped := len(samples) + 1 - window; dropped > 0 {
+		samples = samples[dropped:]
+	}
+	result = append(result, samples...)
+	return append(result, scaleTo)
+}
+
+func isEqualSamples(a []int64, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// scaleDownStabilization is the peak scaleTo of a ScaledJob and the time scaleTo dropped below it
+type scaleDownStabilization struct {
+	peak     int64
+	lowSince time.Time
+}
+
+// scaleDownStabilizations stores the scaleDownStabilization of every ScaledJob by its namespace and name
+type scaleDownStabilizations struct {
+	states sync.Map
+}
+
+var scaleDowns = &scaleDownStabilizations{}
+
+// stabilize returns scaleTo if it isn't below the peak or stayed below it for the whole window,
+// the peak is returned otherwise
+func (s *scaleDownStabilizations) stabilize(namespace string, name string, scaleTo int64, window time.Duration, now time.Time) int64 {
+	key := jobScaleStateKey(namespace, name)
+	existing, ok := s.states.Load(key)
+	if !ok || scaleTo >= existing.(scaleDownStabilization).peak {
+		s.states.Store(key, scaleDownStabilization{peak: scaleTo})
+		return scaleTo
+	}
+
+	state := existing.(scaleDownStabilization)
+	if state.lowSince.IsZero() {
+		state.lowSince = now
+		s.states.Store(key, state)
+	}
+	if now.Sub(state.lowSince) >= window {
+		s.states.Store(key, scaleDownStabilization{peak: scaleTo})
+		return scaleTo
+	}
+	return state.peak
+}
+
+func (s *scaleDownStabilizations) remove(namespace string, name string) {
+	s.states.Delete(jobScaleStateKey(namespace, name))
+}
+
+// getScaleDownStabilizedScaleTo returns the last peak of scaleTo until scaleTo stayed below it
+// for the ScaleDownStabilizationWindow of the ScaledJob
+func getScaleDownStabilizedScaleTo(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, scaleTo int64, now time.Time) int64 {
+	modifiers := scaledJob.Spec.ScalingModifiers
+	if modifiers == nil || modifiers.ScaleDownStabilizationWindow == nil || *modifiers.ScaleDownStabilizationWindow <= 0 {
+		return scaleTo
+	}
+
+	window := time.Duration(*modifiers.ScaleDownStabilizationWindow) * time.Second
+	stabilized := scaleDowns.stabilize(scaledJob.Namespace, scaledJob.Name, scaleTo, window, now)
+	if stabilized > scaleTo {
+		logger.V(1).Info("Stabilizing scaleTo at its last peak within the scale down window", "scaleTo", scaleTo, "stabilizedScaleTo", stabilized)
+	}
+	return stabilized
+}
@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestGetStabilizedScaleTo(t *testing.T) {
+	scaleExecutor := getMockScaleExecutor(nil)
+
+	scaledJob := getMockScaledJobWithDefault()
+	window := int32(3)
+	scaledJob.Spec.ScalingModifiers = &kedav1alpha1.ScalingModifiers{ScaleUpStabilizationWindow: &window}
+
+	tests := []struct {
+		scaleTo         int64
+		expectedScaleTo int64
+		expectedSamples []int64
+	}{
+		{scaleTo: 5, expectedScaleTo: 5, expectedSamples: []int64{5}},
+		// transient dips keep the highest scaleTo of the window
+		{scaleTo: 1, expectedScaleTo: 5, expectedSamples: []int64{5, 1}},
+		{scaleTo: 0, expectedScaleTo: 5, expectedSamples: []int64{5, 1, 0}},
+		// the peak left the window
+		{scaleTo: 2, expectedScaleTo: 2, expectedSamples: []int64{1, 0, 2}},
+		// rising scaleTo is used right away
+		{scaleTo: 8, expectedScaleTo: 8, expectedSamples: []int64{0, 2, 8}},
+	}
+	for i, test := range tests {
+		scaleTo := getStabilizedScaleTo(scaleExecutor.logger, scaledJob, test.scaleTo)
+		assert.Equal(t, test.expectedScaleTo, scaleTo, "poll %d", i)
+		assert.Equal(t, test.expectedSamples, scaledJob.Status.RecentScaleTo, "poll %d", i)
+	}
+}
+
+func TestGetStabilizedScaleToWithoutWindow(t *testing.T) {
+	scaleExecutor := getMockScaleExecutor(nil)
+
+	// no samples are recorded without the window
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Status.RecentScaleTo = []int64{10}
+	assert.Equal(t, int64(1), getStabilizedScaleTo(scaleExecutor.logger, scaledJob, 1))
+
+	window := int32(1)
+	scaledJob.Spec.ScalingModifiers = &kedav1alpha1.ScalingModifiers{ScaleUpStabilizationWindow: &window}
+	assert.Equal(t, int64(1), getStabilizedScaleTo(scaleExecutor.logger, scaledJob, 1))
+	assert.Equal(t, []int64{10}, scaledJob.Status.RecentScaleTo)
+}
+
+func TestAppendScaleToSample(t *testing.T) {
+	// the window was shrunk, the oldest samples are dropped
+	assert.Equal(t, []int64{3, 4}, appendScaleToSample([]int64{1, 2, 3}, 4, 2))
+	assert.Equal(t, []int64{1, 2}, appendScaleToSample([]int64{1}, 2, 5))
+	assert.Equal(t, []int64{7}, appendScaleToSample(nil, 7, 3))
+}
+
+func TestGetScaleDownStabilizedScaleTo(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "scale-down-stabilization"
+	window := int32(60)
+	scaledJob.Spec.ScalingModifiers = &kedav1alpha1.ScalingModifiers{ScaleDownStabilizationWindow: &window}
+	defer scaleDowns.remove(scaledJob.Namespace, scaledJob.Name)
+
+	logger := getMockScaleExecutor(nil).logger
+	start := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		scaleTo         int64
+		elapsed         time.Duration
+		expectedScaleTo int64
+	}{
+		{scaleTo: 10, elapsed: 0, expectedScaleTo: 10},
+		// the metric dropped, the peak is kept within the window
+		{scaleTo: 2, elapsed: 10 * time.Second, expectedScaleTo: 10},
+		{scaleTo: 6, elapsed: 40 * time.Second, expectedScaleTo: 10},
+		// the metric stayed below the peak for the whole window
+		{scaleTo: 3, elapsed: 70 * time.Second, expectedScaleTo: 3},
+		// the lower scaleTo is the new peak, a higher one is used right away
+		{scaleTo: 1, elapsed: 80 * time.Second, expectedScaleTo: 3},
+		{scaleTo: 8, elapsed: 90 * time.Second, expectedScaleTo: 8},
+		// the window starts again with the next drop
+		{scaleTo: 0, elapsed: 100 * time.Second, expectedScaleTo: 8},
+		{scaleTo: 0, elapsed: 159 * time.Second, expectedScaleTo: 8},
+		{scaleTo: 0, elapsed: 160 * time.Second, expectedScaleTo: 0},
+	}
+	for i, test := range tests {
+		scaleTo := getScaleDownStabilizedScaleTo(logger, scaledJob, test.scaleTo, start.Add(test.elapsed))
+		assert.Equal(t, test.expectedScaleTo, scaleTo, "poll %d", i)
+	}
+}
+
+func TestGetScaleDownStabilizedScaleToWithoutWindow(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Name = "no-scale-down-stabilization"
+	defer scaleDowns.remove(scaledJob.Namespace, scaledJob.Name)
+
+	logger := getMockScaleExecutor(nil).logger
+	now := time.Now()
+	assert.Equal(t, int64(10), getScaleDownStabilizedScaleTo(logger, scaledJob, 10, now))
+	assert.Equal(t, int64(2), getScaleDownStabilizedScaleTo(logger, scaledJob, 2, now))
+}
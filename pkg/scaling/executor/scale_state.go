@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JobScaleState holds the inputs of the last scaling decision of a ScaledJob
+type JobScaleState struct {
+	Namespace       string    `json:"namespace"`
+	Name            string    `json:"name"`
+	IsActive        bool      `json:"isActive"`
+	ScaleTo         int64     `json:"scaleTo"`
+	MaxScale        int64     `json:"maxScale"`
+	RunningJobCount int64     `json:"runningJobCount"`
+	ScaledAt        time.Time `json:"scaledAt"`
+}
+
+// jobScaleStates stores the last JobScaleState of every ScaledJob by its namespace and name
+type jobScaleStates struct {
+	states sync.Map
+}
+
+var scaleStates = &jobScaleStates{}
+
+func (s *jobScaleStates) record(state JobScaleState) {
+	s.states.Store(jobScaleStateKey(state.Namespace, state.Name), state)
+}
+
+func (s *jobScaleStates) remove(namespace string, name string) {
+	s.states.Delete(jobScaleStateKey(namespace, name))
+}
+
+func (s *jobScaleStates) get(namespace string, name string) (JobScaleState, bool) {
+	state, ok := s.states.Load(jobScaleStateKey(namespace, name))
+	if !ok {
+		return JobScaleState{}, false
+	}
+	return state.(JobScaleState), true
+}
+
+// ValidateJobScaleStateAddr checks the address the endpoints serving the state of the ScaledJobs bind to is a loopback
+// address, the endpoints are served without authentication and must not be reachable from the network
+func ValidateJobScaleStateAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("%s is not a loopback address, eg. 127.0.0.1:8082, the endpoints are served without authentication", addr)
+}
+
+// JobScaleStateHandler serves the last JobScaleState of the ScaledJob given by the "namespace" and "name"
+// query parameters as JSON
+func JobScaleStateHandler(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	if namespace == "" || name == "" {
+		http.Error(w, "namespace and name query parameters are required", http.StatusBadRequest)
+		return
+	}
+
+	state, ok := scaleStates.get(namespace, name)
+	if !ok {
+		http.Error(w, "no scaling decision recorded for ScaledJob "+jobScaleStateKey(namespace, name), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(state); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobScaleStateHandler(t *testing.T) {
+	scaledAt := time.Date(2020, 7, 29, 15, 37, 0, 0, time.UTC)
+	scaleStates.record(JobScaleState{
+		Namespace:       "default",
+		Name:            "consumer",
+		IsActive:        true,
+		ScaleTo:         12,
+		MaxScale:        10,
+		RunningJobCount: 4,
+		ScaledAt:        scaledAt,
+	})
+	defer scaleStates.remove("default", "consumer")
+
+	recorder := httptest.NewRecorder()
+	JobScaleStateHandler(recorder, httptest.NewRequest(http.MethodGet, "/scaledjobs?namespace=default&name=consumer", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	state := JobScaleState{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &state))
+	assert.Equal(t, int64(12), state.ScaleTo)
+	assert.Equal(t, int64(10), state.MaxScale)
+	assert.Equal(t, int64(4), state.RunningJobCount)
+	assert.True(t, state.IsActive)
+	assert.True(t, scaledAt.Equal(state.ScaledAt))
+}
+
+func TestJobScaleStateHandlerErrors(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	JobScaleStateHandler(recorder, httptest.NewRequest(http.MethodGet, "/scaledjobs?namespace=default", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	JobScaleStateHandler(recorder, httptest.NewRequest(http.MethodGet, "/scaledjobs?namespace=default&name=unknown", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestValidateJobScaleStateAddr(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:8082", "localhost:8082", "[::1]:8082"} {
+		assert.Nil(t, ValidateJobScaleStateAddr(addr), addr)
+	}
+	// the endpoints are served without authentication, they must not be reachable from the network
+	for _, addr := range []string{":8082", "0.0.0.0:8082", "10.0.0.12:8082", "keda-operator:8082", "8082"} {
+		assert.NotNil(t, ValidateJobScaleStateAddr(addr), addr)
+	}
+}
@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"fmt"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// ValidateScalingStrategy checks the values of the ScalingStrategy are in the allowed ranges and not conflicting
+func ValidateScalingStrategy(strategy *kedav1alpha1.ScalingStrategy) error {
+	if strategy == nil {
+		return nil
+	}
+	if strategy.CompletionsDivisor != nil && *strategy.CompletionsDivisor < 1 {
+		return fmt.Errorf("completionsDivisor must be greater than 0, got %d", *strategy.CompletionsDivisor)
+	}
+	if strategy.BacklogAgeThresholdSeconds != nil && *strategy.BacklogAgeThresholdSeconds < 0 {
+		return fmt.Errorf("backlogAgeThresholdSeconds must not be negative, got %d", *strategy.BacklogAgeThresholdSeconds)
+	}
+	if strategy.BacklogAgeBoostPercentage != nil {
+		if strategy.BacklogAgeThresholdSeconds == nil {
+			return fmt.Errorf("backlogAgeBoostPercentage is set, but backlogAgeThresholdSeconds is not, set backlogAgeThresholdSeconds to enable the boost")
+		}
+		if *strategy.BacklogAgeBoostPercentage < 0 {
+			return fmt.Errorf("backlogAgeBoostPercentage must not be negative, got %d", *strategy.BacklogAgeBoostPercentage)
+		}
+	}
+	if strategy.ExpectedJobDurationSeconds != nil && strategy.Strategy != kedav1alpha1.ThroughputScalingStrategy {
+		return fmt.Errorf("expectedJobDurationSeconds is only used by the %s strategy, set strategy to %s or unset expectedJobDurationSeconds", kedav1alpha1.ThroughputScalingStrategy, kedav1alpha1.ThroughputScalingStrategy)
+	}
+	switch strategy.Strategy {
+	case "", kedav1alpha1.DefaultScalingStrategy:
+		if strategy.Percentage != nil {
+			return fmt.Errorf("percentage is only used by the %s strategy, set strategy to %s or unset percentage", kedav1alpha1.PercentageScalingStrategy, kedav1alpha1.PercentageScalingStrategy)
+		}
+	case kedav1alpha1.PercentageScalingStrategy:
+		if strategy.Percentage == nil || *strategy.Percentage < 1 || *strategy.Percentage > 100 {
+			return fmt.Errorf("percentage must be set between 1 and 100 for the %s strategy", strategy.Strategy)
+		}
+	case kedav1alpha1.ThroughputScalingStrategy:
+		if strategy.ExpectedJobDurationSeconds == nil || *strategy.ExpectedJobDurationSeconds < 1 {
+			return fmt.Errorf("expectedJobDurationSeconds must be greater than 0 for the %s strategy", strategy.Strategy)
+		}
+		if strategy.Percentage != nil {
+			return fmt.Errorf("percentage is only used by the %s strategy, set strategy to %s or unset percentage", kedav1alpha1.PercentageScalingStrategy, kedav1alpha1.PercentageScalingStrategy)
+		}
+	default:
+		return fmt.Errorf("unknown scaling strategy %s", strategy.Strategy)
+	}
+	return nil
+}
+
+// ValidateScalingStrategyForScaledJob checks the ScalingStrategy and its combination with the other fields of the ScaledJob,
+// the strategy is the one of the ScaledJob or the default the ScaledJob falls back to
+func ValidateScalingStrategyForScaledJob(scaledJob *kedav1alpha1.ScaledJob, strategy *kedav1alpha1.ScalingStrategy) error {
+	if err := ValidateScalingStrategy(strategy); err != nil {
+		return err
+	}
+	if strategy != nil && strategy.CompletionsDivisor != nil && scaledJob.Spec.ReplaceFailedJobs {
+		return fmt.Errorf("completionsDivisor can't be combined with replaceFailedJobs, replacements of failed jobs don't get a share of the completions, unset one of them")
+	}
+	return nil
+}
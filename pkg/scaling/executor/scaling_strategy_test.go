@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestValidateScalingStrategyCompletionsDivisor(t *testing.T) {
+	assert.Nil(t, ValidateScalingStrategy(nil))
+	assert.Nil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{}))
+
+	divisor := int32(3)
+	assert.Nil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{CompletionsDivisor: &divisor}))
+
+	divisor = 0
+	assert.NotNil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{CompletionsDivisor: &divisor}))
+
+	divisor = -1
+	assert.NotNil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{CompletionsDivisor: &divisor}))
+}
+
+func TestValidateScalingStrategyPercentage(t *testing.T) {
+	assert.Nil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.DefaultScalingStrategy}))
+	assert.NotNil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{Strategy: "unknown"}))
+	assert.NotNil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.PercentageScalingStrategy}))
+
+	percentage := int32(50)
+	assert.Nil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.PercentageScalingStrategy, Percentage: &percentage}))
+
+	percentage = 101
+	assert.NotNil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.PercentageScalingStrategy, Percentage: &percentage}))
+}
+
+func TestValidateScalingStrategyConflicts(t *testing.T) {
+	percentage := int32(50)
+	err := ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{Percentage: &percentage})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "percentage is only used by the percentage strategy")
+
+	boost := int32(50)
+	err = ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{BacklogAgeBoostPercentage: &boost})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "set backlogAgeThresholdSeconds")
+
+	threshold := int32(-1)
+	err = ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{BacklogAgeThresholdSeconds: &threshold})
+	assert.NotNil(t, err)
+
+	threshold = 60
+	assert.Nil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{BacklogAgeThresholdSeconds: &threshold, BacklogAgeBoostPercentage: &boost}))
+}
+
+func TestValidateScalingStrategyThroughput(t *testing.T) {
+	assert.NotNil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.ThroughputScalingStrategy}))
+
+	expected := int32(60)
+	assert.Nil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.ThroughputScalingStrategy, ExpectedJobDurationSeconds: &expected}))
+	assert.NotNil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{ExpectedJobDurationSeconds: &expected}))
+
+	expected = 0
+	assert.NotNil(t, ValidateScalingStrategy(&kedav1alpha1.ScalingStrategy{Strategy: kedav1alpha1.ThroughputScalingStrategy, ExpectedJobDurationSeconds: &expected}))
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"context"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// ShardIndexLabel holds the SHARD_INDEX of a Job created with shardedCreation, the indices of the running Jobs
+	// are reserved so the Jobs created by a later scaling, eg. after a partial creation, don't reuse them
+	ShardIndexLabel = "keda.sh/shard-index"
+)
+
+// reserveShardIndices returns count shard indices not held by the running Jobs of the ScaledJob, the lowest free
+// indices are used. The returned total is the number of the shards once the Jobs are created, every running Job
+// holds an index below it
+func (e *scaleExecutor) reserveShardIndices(scaledJob *kedav1alpha1.ScaledJob, count int64) ([]int64, int64, error) {
+	jobs, err := e.listJobs(context.TODO(), scaledJob)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	indices, total := getFreeShardIndices(e.getReservedShardIndices(jobs.Items), count)
+	return indices, total, nil
+}
+
+// getReservedShardIndices returns the shard indices held by the running jobs
+func (e *scaleExecutor) getReservedShardIndices(jobs []batchv1.Job) map[int64]bool {
+	reserved := map[int64]bool{}
+	for _, job := range jobs {
+		value, ok := job.Labels[ShardIndexLabel]
+		if !ok || IsJobFinished(&job) {
+			continue
+		}
+		index, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || index < 0 {
+			continue
+		}
+		reserved[index] = true
+	}
+	return reserved
+}
+
+// getFreeShardIndices returns the count lowest indices that aren't reserved and the number of the shards
+// once they are used, ie. the highest index in use plus one
+func getFreeShardIndices(reserved map[int64]bool, count int64) ([]int64, int64) {
+	var total int64
+	for index := range reserved {
+		if index+1 > total {
+			total = index + 1
+		}
+	}
+
+	indices := make([]int64, 0, count)
+	for index := int64(0); int64(len(indices)) < count; index++ {
+		if !reserved[index] {
+			indices = append(indices, index)
+			if index+1 > total {
+				total = index + 1
+			}
+		}
+	}
+	return indices, total
+}
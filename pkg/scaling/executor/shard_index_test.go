@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestCreateJobsWithShardedCreationReservesIndices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{
+		Template: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "main"}},
+			},
+		},
+	}
+	scaledJob.Spec.ShardedCreation = true
+
+	// the jobs left by a partial creation hold their indices, the index of the finished job is free again
+	running1 := getRunningJob("running1", time.Now(), nil)
+	running1.Labels = map[string]string{ShardIndexLabel: "1"}
+	running3 := getRunningJob("running3", time.Now(), nil)
+	running3.Labels = map[string]string{ShardIndexLabel: "3"}
+	finished := *getJob(t, "finished", "2020-07-29T15:31:00Z", batchv1.JobComplete)
+	finished.Labels = map[string]string{ShardIndexLabel: "0"}
+	unsharded := getRunningJob("unsharded", time.Now(), nil)
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = []batchv1.Job{running1, running3, finished, unsharded}
+	}).
+		Return(nil)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 4, 10, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(createdJobs))
+	for i, expectedIndex := range []string{"0", "2", "4", "5"} {
+		job := createdJobs[i]
+		assert.Equal(t, expectedIndex, job.Labels[ShardIndexLabel])
+		assert.Equal(t, []v1.EnvVar{
+			{Name: ShardIndexEnv, Value: expectedIndex},
+			{Name: ShardTotalEnv, Value: "6"},
+		}, job.Spec.Template.Spec.Containers[0].Env)
+	}
+}
+
+func TestGetFreeShardIndices(t *testing.T) {
+	indices, total := getFreeShardIndices(map[int64]bool{}, 3)
+	assert.Equal(t, []int64{0, 1, 2}, indices)
+	assert.Equal(t, int64(3), total)
+
+	// the running job with the highest index is still within the shards
+	indices, total = getFreeShardIndices(map[int64]bool{7: true}, 2)
+	assert.Equal(t, []int64{0, 1}, indices)
+	assert.Equal(t, int64(8), total)
+
+	indices, total = getFreeShardIndices(map[int64]bool{0: true, 1: true, 2: true, 3: true}, 2)
+	assert.Equal(t, []int64{4, 5}, indices)
+	assert.Equal(t, int64(6), total)
+}
@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// MainContainerTerminatedLabel is set on the Jobs whose main container terminated while a sidecar keeps the pod running,
+	// its value is the condition type the Job finished with, Complete or Failed
+	MainContainerTerminatedLabel = "keda.sh/main-container-terminated"
+)
+
+// detectSidecarCompletedJobs checks the main container of the running pods of the running Jobs of the ScaledJob and
+// labels the Jobs whose main container terminated with the MainContainerTerminatedLabel, so they no longer count as running.
+// The sidecars of such pods never terminate, so the Job controller doesn't finish the Job
+func (e *scaleExecutor) detectSidecarCompletedJobs(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) error {
+	containerName := scaledJob.Spec.MainContainerName
+
+	jobs, err := e.listJobs(ctx, scaledJob)
+	if err != nil {
+		return err
+	}
+
+	pods, err := e.listJobPods(ctx, scaledJob)
+	if err != nil {
+		return err
+	}
+
+	jobPods := make(map[string][]*corev1.Pod)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodRunning {
+			jobName := getPodJobName(pod)
+			jobPods[jobName] = append(jobPods[jobName], pod)
+		}
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if IsJobFinished(job) || isJobSuspended(job) {
+			continue
+		}
+
+		for _, pod := range jobPods[job.GetName()] {
+			conditionType := getMainContainerConditionType(pod, containerName)
+			if conditionType == "" {
+				continue
+			}
+
+			patch := client.MergeFrom(job.DeepCopy())
+			if job.Labels == nil {
+				job.Labels = map[string]string{}
+			}
+			job.Labels[MainContainerTerminatedLabel] = string(conditionType)
+			err = e.client.Patch(ctx, job, patch)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			logger.Info("Job finished by the termination of its main container", "job.Name", job.GetName(), "pod.Name", pod.GetName(), "condition", conditionType)
+			break
+		}
+	}
+	return nil
+}
+
+// getMainContainerConditionType returns the condition type the Job finishes with once the main container of the pod
+// terminated, an empty type is returned while it runs. A failed container restarted by the kubelet isn't final,
+// so the failure only finishes the Job if the pod is never restarted
+func getMainContainerConditionType(pod *corev1.Pod, containerName string) batchv1.JobConditionType {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != containerName || status.State.Terminated == nil {
+			continue
+		}
+		if status.State.Terminated.ExitCode == 0 {
+			return batchv1.JobComplete
+		}
+		if pod.Spec.RestartPolicy == corev1.RestartPolicyNever {
+			return batchv1.JobFailed
+		}
+	}
+	return ""
+}
+
+// getMainContainerTerminatedConditionType returns the condition type of the Job finished by the termination
+// of its main container, an empty type is returned if it wasn't
+func getMainContainerTerminatedConditionType(job *batchv1.Job) batchv1.JobConditionType {
+	switch conditionType := batchv1.JobConditionType(job.Labels[MainContainerTerminatedLabel]); conditionType {
+	case batchv1.JobComplete, batchv1.JobFailed:
+		return conditionType
+	default:
+		return ""
+	}
+}
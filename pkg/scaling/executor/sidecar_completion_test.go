@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestDetectSidecarCompletedJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.MainContainerName = "worker"
+
+	jobs := []batchv1.Job{
+		{ObjectMeta: metav1.ObjectMeta{Name: "succeeded"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "failed"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "retried"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "working"}},
+		*getJob(t, "finished", "2020-07-29T15:31:00Z", batchv1.JobComplete),
+	}
+	pods := []corev1.Pod{
+		getSidecarPod("succeeded", corev1.RestartPolicyNever, &corev1.ContainerStateTerminated{ExitCode: 0}),
+		getSidecarPod("failed", corev1.RestartPolicyNever, &corev1.ContainerStateTerminated{ExitCode: 1}),
+		// the kubelet restarts the failed main container
+		getSidecarPod("retried", corev1.RestartPolicyOnFailure, &corev1.ContainerStateTerminated{ExitCode: 1}),
+		getSidecarPod("working", corev1.RestartPolicyNever, nil),
+		getSidecarPod("finished", corev1.RestartPolicyNever, &corev1.ContainerStateTerminated{ExitCode: 0}),
+	}
+
+	var patchedJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		switch l := list.(type) {
+		case *batchv1.JobList:
+			for _, job := range jobs {
+				l.Items = append(l.Items, *job.DeepCopy())
+			}
+		case *corev1.PodList:
+			l.Items = pods
+		}
+	}).
+		Return(nil).AnyTimes()
+	client.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) {
+		patchedJobs = append(patchedJobs, obj.(*batchv1.Job))
+	}).
+		Return(nil).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.detectSidecarCompletedJobs(context.TODO(), scaleExecutor.logger, scaledJob)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(patchedJobs))
+	assert.Equal(t, "succeeded", patchedJobs[0].Name)
+	assert.Equal(t, "Complete", patchedJobs[0].Labels[MainContainerTerminatedLabel])
+	assert.Equal(t, "failed", patchedJobs[1].Name)
+	assert.Equal(t, "Failed", patchedJobs[1].Labels[MainContainerTerminatedLabel])
+
+	// the jobs are finished with the condition of their main container and no longer count as running
+	assert.True(t, IsJobFinished(patchedJobs[0]))
+	assert.Equal(t, batchv1.JobComplete, scaleExecutor.getFinishedJobConditionType(patchedJobs[0]))
+	assert.Equal(t, batchv1.JobFailed, scaleExecutor.getFinishedJobConditionType(patchedJobs[1]))
+	assert.Equal(t, int64(2), scaleExecutor.countRunningJobs([]batchv1.Job{jobs[2], jobs[3], *patchedJobs[0], *patchedJobs[1]}))
+}
+
+func TestGetMainContainerTerminatedConditionType(t *testing.T) {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{MainContainerTerminatedLabel: "Complete"}}}
+	assert.Equal(t, batchv1.JobComplete, getMainContainerTerminatedConditionType(job))
+
+	// an unknown value doesn't finish the job
+	job.Labels[MainContainerTerminatedLabel] = "Suspended"
+	assert.Equal(t, batchv1.JobConditionType(""), getMainContainerTerminatedConditionType(job))
+}
+
+// getSidecarPod returns a running pod of the Job whose sidecar keeps running, the main container is in the terminated state
+// or still running if terminated is nil
+func getSidecarPod(jobName string, restartPolicy corev1.RestartPolicy, terminated *corev1.ContainerStateTerminated) corev1.Pod {
+	mainState := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	if terminated != nil {
+		mainState = corev1.ContainerState{Terminated: terminated}
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   jobName + "-pod",
+			Labels: map[string]string{"job-name": jobName},
+		},
+		Spec: corev1.PodSpec{RestartPolicy: restartPolicy},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "worker", State: mainState},
+				{Name: "istio-proxy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+}
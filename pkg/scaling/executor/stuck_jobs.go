@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// StuckJobRecreated is the reason of the event emitted when a stuck Job is deleted and recreated
+const StuckJobRecreated = "StuckJobRecreated"
+
+// recreateStuckJobs deletes the running jobs without active pods and completions for longer than the
+// stuckSeconds of the ScaledJob's stuckJobRecreation, a new Job is created for every deleted one within replaceBudget.
+// The stuck jobs are counted as running, the ones that aren't recreated are replaced by the next scaling once
+// they are gone, if they still fit into maxScale then. The remaining budget is returned
+func (e *scaleExecutor) recreateStuckJobs(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, runningJobs []batchv1.Job, replaceBudget int64, now time.Time) (int64, error) {
+	if err := e.validateJobGeneration(scaledJob); err != nil {
+		logger.Error(err, "Not recreating stuck jobs")
+		return replaceBudget, nil
+	}
+
+	stuckDuration := time.Duration(scaledJob.Spec.StuckJobRecreation.StuckSeconds) * time.Second
+	for i := range runningJobs {
+		job := &runningJobs[i]
+		if !isJobStuck(job, stuckDuration, now) {
+			continue
+		}
+
+		err := e.client.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if errors.IsNotFound(err) {
+			// the job was deleted meanwhile, it is not replaced
+			continue
+		}
+		if err != nil {
+			return replaceBudget, err
+		}
+		if replaceBudget <= 0 {
+			logger.Info("Deleted a stuck job, not recreating it, maxScale reached", "job.Name", job.GetName(), "stuckDuration", stuckDuration)
+			continue
+		}
+		if err := e.createJob(logger, e.generateJob(logger, scaledJob)); err != nil {
+			return replaceBudget, err
+		}
+		replaceBudget--
+		logger.Info("Recreated a stuck job", "job.Name", job.GetName(), "stuckDuration", stuckDuration)
+		e.recordEvent(scaledJob, corev1.EventTypeWarning, StuckJobRecreated,
+			"Recreated Job %s, it had no active pods and no completions for %s", job.GetName(), stuckDuration)
+	}
+	return replaceBudget, nil
+}
+
+// isJobStuck returns true if the job has neither active pods nor completions for longer than stuckDuration since its start,
+// suspended jobs and jobs excluded from the count are never stuck. Neither are the jobs with failed pods,
+// the Job controller creates their next pod after its backoff, until the backoffLimit fails the job
+func isJobStuck(job *batchv1.Job, stuckDuration time.Duration, now time.Time) bool {
+	if isJobSuspended(job) || job.Labels[ExcludeFromCountLabel] == "true" {
+		return false
+	}
+	if job.Status.Failed > 0 {
+		return false
+	}
+	if job.Status.Active > 0 || job.Status.Succeeded > 0 {
+		return false
+	}
+
+	startTime := job.CreationTimestamp.Time
+	if job.Status.StartTime != nil {
+		startTime = job.Status.StartTime.Time
+	}
+	return now.Sub(startTime) > stuckDuration
+}
@@ -0,0 +1,157 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestCleanUpRecreatesStuckJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.StuckJobRecreation = &kedav1alpha1.StuckJobRecreation{StuckSeconds: 300}
+
+	now := time.Now()
+	jobs := []batchv1.Job{
+		// wedged, no pod is active and nothing completed since its start
+		getRunningJob("stuck", now.Add(-10*time.Minute), nil),
+		getRunningJob("active", now.Add(-10*time.Minute), nil),
+		getRunningJob("completing", now.Add(-10*time.Minute), nil),
+		// started recently, its pod may still be scheduled
+		getRunningJob("starting", now.Add(-10*time.Second), nil),
+		getSuspendedJob("suspended", now.Add(-10*time.Minute)),
+		// its pod failed, the next one is created after the backoff
+		getRunningJob("backoff", now.Add(-10*time.Minute), nil),
+	}
+	jobs[1].Status.Active = 1
+	jobs[2].Status.Succeeded = 1
+	jobs[5].Status.Failed = 1
+
+	deletedJobName := make(map[string]string)
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = jobs
+	}).
+		Return(nil)
+	expectDelete(t, client, &deletedJobName)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+	recorder := record.NewFakeRecorder(10)
+	scaleExecutor.recorder = recorder
+
+	err := scaleExecutor.cleanUp(scaledJob, 1, true)
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"stuck": "stuck"}, deletedJobName)
+	assert.Equal(t, 1, len(createdJobs))
+	assert.Equal(t, scaledJob.Name, createdJobs[0].Labels["scaledjob"])
+	assert.Equal(t, 1, len(recorder.Events))
+	assert.Contains(t, <-recorder.Events, "Warning StuckJobRecreated Recreated Job stuck")
+}
+
+func TestCleanUpRecreatesStuckJobsWithinMaxScale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.StuckJobRecreation = &kedav1alpha1.StuckJobRecreation{StuckSeconds: 300}
+
+	now := time.Now()
+	jobs := []batchv1.Job{
+		getRunningJob("stuck-1", now.Add(-10*time.Minute), nil),
+		getRunningJob("stuck-2", now.Add(-10*time.Minute), nil),
+	}
+
+	deletedJobName := make(map[string]string)
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = jobs
+	}).
+		Return(nil)
+	expectDelete(t, client, &deletedJobName)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// both stuck jobs are deleted, only one of them fits into maxScale
+	err := scaleExecutor.cleanUp(scaledJob, 1, true)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(deletedJobName))
+	assert.Equal(t, 1, len(createdJobs))
+}
+
+func TestCleanUpSkipsStuckJobsWhenJobsCantBeCreated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{}
+	scaledJob.Spec.StuckJobRecreation = &kedav1alpha1.StuckJobRecreation{StuckSeconds: 300}
+	jobs := []batchv1.Job{getRunningJob("stuck", time.Now().Add(-10*time.Minute), nil)}
+
+	// no job is deleted nor created
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = jobs
+	}).
+		Return(nil).AnyTimes()
+	client.EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ runtimeclient.ObjectKey, obj runtime.Object) {
+		if latest, ok := obj.(*kedav1alpha1.ScaledJob); ok {
+			scaledJob.DeepCopyInto(latest)
+		}
+	}).
+		Return(nil).AnyTimes()
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	// the ScaledJob is inactive
+	_, err := scaleExecutor.RequestJobScale(context.TODO(), scaledJob, false, 0, 10, 0, time.Time{})
+	assert.Nil(t, err)
+
+	// the ScaledJob is paused
+	scaledJob.Annotations = map[string]string{PausedAnnotation: "true"}
+	_, err = scaleExecutor.RequestJobScale(context.TODO(), scaledJob, true, 1, 10, 0, time.Time{})
+	assert.Nil(t, err)
+}
+
+func TestIsJobStuck(t *testing.T) {
+	now := time.Now()
+	job := getRunningJob("job", now.Add(-time.Minute), nil)
+	assert.True(t, isJobStuck(&job, 30*time.Second, now))
+	assert.False(t, isJobStuck(&job, 2*time.Minute, now))
+
+	// the creation time is used until the job is started
+	job.Status.StartTime = nil
+	job.CreationTimestamp.Time = now.Add(-time.Minute)
+	assert.True(t, isJobStuck(&job, 30*time.Second, now))
+
+	job.Labels = map[string]string{ExcludeFromCountLabel: "true"}
+	assert.False(t, isJobStuck(&job, 30*time.Second, now))
+
+	// the job controller retries the failed pods with its backoff
+	job.Labels = nil
+	job.Status.Failed = 2
+	assert.False(t, isJobStuck(&job, 30*time.Second, now))
+}
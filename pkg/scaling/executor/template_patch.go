@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+// getJobTemplatePatch returns the ScaledJob's jobTemplatePatch as JSON, it may be written as JSON or YAML.
+// nil is returned if no patch is set
+func getJobTemplatePatch(patch string) ([]byte, error) {
+	if patch == "" {
+		return nil, nil
+	}
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch))
+	if err != nil {
+		return nil, fmt.Errorf("jobTemplatePatch is neither JSON nor YAML: %s", err)
+	}
+	return patchJSON, nil
+}
+
+// applyJobTemplatePatch applies the strategic merge patch to the pod template of the Job,
+// e.g. containers are merged by their name and env vars are added to the existing ones
+func applyJobTemplatePatch(job *batchv1.Job, patchJSON []byte) error {
+	templateJSON, err := json.Marshal(job.Spec.Template)
+	if err != nil {
+		return err
+	}
+	patchedJSON, err := strategicpatch.StrategicMergePatch(templateJSON, patchJSON, corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf("failed to apply jobTemplatePatch: %s", err)
+	}
+	template := corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(patchedJSON, &template); err != nil {
+		return fmt.Errorf("failed to apply jobTemplatePatch: %s", err)
+	}
+	job.Spec.Template = template
+	return nil
+}
+
+// ValidateJobTemplatePatch checks the patch can be applied to the pod template of the Jobs
+func ValidateJobTemplatePatch(patch string, jobSpec *batchv1.JobSpec) error {
+	patchJSON, err := getJobTemplatePatch(patch)
+	if err != nil || patchJSON == nil {
+		return err
+	}
+	job := &batchv1.Job{}
+	if jobSpec != nil {
+		job.Spec = *jobSpec.DeepCopy()
+	}
+	return applyJobTemplatePatch(job, patchJSON)
+}
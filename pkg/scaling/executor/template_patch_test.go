@@ -0,0 +1,67 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestCreateJobsWithJobTemplatePatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.JobTargetRef = &batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "worker", Image: "worker:1.0", Env: []corev1.EnvVar{{Name: "QUEUE", Value: "orders"}}},
+					{Name: "sidecar", Image: "sidecar:1.0"},
+				},
+			},
+		},
+	}
+	scaledJob.Spec.JobTemplatePatch = `
+spec:
+  containers:
+  - name: worker
+    env:
+    - name: LOG_LEVEL
+      value: debug
+`
+
+	var createdJobs []*batchv1.Job
+	client := mock_client.NewMockClient(ctrl)
+	expectCreate(t, client, &createdJobs)
+	scaleExecutor := getMockScaleExecutor(client)
+
+	_, err := scaleExecutor.createJobs(scaleExecutor.logger, scaledJob, scaledJob.Spec.ScalingStrategy, 2, 10, time.Time{}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(createdJobs))
+	for _, job := range createdJobs {
+		containers := job.Spec.Template.Spec.Containers
+		assert.Equal(t, 2, len(containers))
+		assert.Equal(t, "worker:1.0", containers[0].Image)
+		assert.ElementsMatch(t, []corev1.EnvVar{{Name: "QUEUE", Value: "orders"}, {Name: "LOG_LEVEL", Value: "debug"}}, containers[0].Env)
+		assert.Empty(t, containers[1].Env)
+		// the labels set by KEDA are kept
+		assert.Equal(t, scaledJob.Name, job.Spec.Template.Labels["scaledjob"])
+	}
+	// the patch is applied to the created Jobs only
+	assert.Equal(t, 1, len(scaledJob.Spec.JobTargetRef.Template.Spec.Containers[0].Env))
+}
+
+func TestValidateJobTemplatePatch(t *testing.T) {
+	assert.NoError(t, ValidateJobTemplatePatch("", nil))
+	assert.NoError(t, ValidateJobTemplatePatch(`{"spec": {"containers": [{"name": "worker", "env": [{"name": "LOG_LEVEL", "value": "debug"}]}]}}`, &batchv1.JobSpec{}))
+	assert.Error(t, ValidateJobTemplatePatch(`spec: [`, &batchv1.JobSpec{}))
+	// the containers must be a list
+	assert.Error(t, ValidateJobTemplatePatch(`{"spec": {"containers": "worker"}}`, &batchv1.JobSpec{}))
+}
@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/robfig/cron/v3"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// ValidateTimeWindows checks the cron expressions and timezones of the TimeWindows can be parsed
+func ValidateTimeWindows(windows []kedav1alpha1.TimeWindow) error {
+	for i, window := range windows {
+		if window.MaxReplicaCount < 0 {
+			return fmt.Errorf("timeWindows[%d]: maxReplicaCount must not be negative, got %d", i, window.MaxReplicaCount)
+		}
+		if _, err := isTimeWindowInEffect(window, time.Now()); err != nil {
+			return fmt.Errorf("timeWindows[%d]: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// getTimeWindowMaxScale returns maxScale capped by the TimeWindows of the ScaledJob that are in effect at now,
+// windows that can't be evaluated are ignored
+func getTimeWindowMaxScale(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, maxScale int64, now time.Time) int64 {
+	modifiers := scaledJob.Spec.ScalingModifiers
+	if modifiers == nil {
+		return maxScale
+	}
+
+	for i, window := range modifiers.TimeWindows {
+		inEffect, err := isTimeWindowInEffect(window, now)
+		if err != nil {
+			logger.Error(err, "Failed to evaluate time window, ignoring it", "timeWindow", i)
+			continue
+		}
+		if inEffect && int64(window.MaxReplicaCount) < maxScale {
+			logger.V(1).Info("Time window in effect, capping maxScale", "timeWindow", i, "maxReplicaCount", window.MaxReplicaCount)
+			maxScale = int64(window.MaxReplicaCount)
+		}
+	}
+	return maxScale
+}
+
+// isTimeWindowInEffect returns true if the window was started and not ended yet at now,
+// ie. its next end comes before its next start
+func isTimeWindowInEffect(window kedav1alpha1.TimeWindow, now time.Time) (bool, error) {
+	location := time.UTC
+	if window.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(window.Timezone)
+		if err != nil {
+			return false, fmt.Errorf("unable to load timezone %s: %s", window.Timezone, err)
+		}
+	}
+
+	start, err := cron.ParseStandard(window.Start)
+	if err != nil {
+		return false, fmt.Errorf("error parsing start %s: %s", window.Start, err)
+	}
+	end, err := cron.ParseStandard(window.End)
+	if err != nil {
+		return false, fmt.Errorf("error parsing end %s: %s", window.End, err)
+	}
+
+	localNow := now.In(location)
+	return end.Next(localNow).Before(start.Next(localNow)), nil
+}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func TestGetTimeWindowMaxScale(t *testing.T) {
+	logger := logf.Log.WithName("test")
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.ScalingModifiers = &kedav1alpha1.ScalingModifiers{
+		TimeWindows: []kedav1alpha1.TimeWindow{
+			// off-hours on weekdays
+			{Start: "0 18 * * 1-5", End: "0 8 * * 1-5", Timezone: "Europe/Prague", MaxReplicaCount: 2},
+			// weekends
+			{Start: "0 0 * * 6", End: "0 0 * * 1", MaxReplicaCount: 5},
+		},
+	}
+
+	prague, _ := time.LoadLocation("Europe/Prague")
+
+	// Wednesday noon, within business hours
+	now := time.Date(2020, 7, 29, 12, 0, 0, 0, prague)
+	assert.Equal(t, int64(10), getTimeWindowMaxScale(logger, scaledJob, 10, now))
+
+	// Wednesday evening, off-hours
+	now = time.Date(2020, 7, 29, 20, 0, 0, 0, prague)
+	assert.Equal(t, int64(2), getTimeWindowMaxScale(logger, scaledJob, 10, now))
+
+	// the lower maxScale is kept
+	assert.Equal(t, int64(1), getTimeWindowMaxScale(logger, scaledJob, 1, now))
+
+	// Saturday noon, the off-hours last from Friday evening, the lowest cap is used
+	now = time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(2), getTimeWindowMaxScale(logger, scaledJob, 10, now))
+
+	// the weekend window alone
+	scaledJob.Spec.ScalingModifiers.TimeWindows = scaledJob.Spec.ScalingModifiers.TimeWindows[1:]
+	assert.Equal(t, int64(5), getTimeWindowMaxScale(logger, scaledJob, 10, now))
+
+	scaledJob.Spec.ScalingModifiers = nil
+	assert.Equal(t, int64(10), getTimeWindowMaxScale(logger, scaledJob, 10, now))
+}
+
+func TestValidateTimeWindows(t *testing.T) {
+	assert.Nil(t, ValidateTimeWindows(nil))
+	assert.Nil(t, ValidateTimeWindows([]kedav1alpha1.TimeWindow{{Start: "0 18 * * *", End: "0 8 * * *", Timezone: "Europe/Prague"}}))
+	assert.NotNil(t, ValidateTimeWindows([]kedav1alpha1.TimeWindow{{Start: "0 18 * *", End: "0 8 * * *"}}))
+	assert.NotNil(t, ValidateTimeWindows([]kedav1alpha1.TimeWindow{{Start: "0 18 * * *", End: "0 8 * * *", Timezone: "Unknown/Zone"}}))
+	assert.NotNil(t, ValidateTimeWindows([]kedav1alpha1.TimeWindow{{Start: "0 18 * * *", End: "0 8 * * *", MaxReplicaCount: -1}}))
+}
@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+// triggerHistoryBucket holds the finished jobs of a trigger with its own history limits
+type triggerHistoryBucket struct {
+	trigger                    string
+	completedJobs              []batchv1.Job
+	failedJobs                 []batchv1.Job
+	successfulJobsHistoryLimit int32
+	failedJobsHistoryLimit     int32
+}
+
+// getJobTrigger returns the value of the TriggerLabel of the Jobs of the ScaledJob. Only the Jobs of a ScaledJob
+// with a single trigger are labeled, "" is returned otherwise as the Jobs are created for all the triggers together
+func getJobTrigger(scaledJob *kedav1alpha1.ScaledJob) string {
+	if len(scaledJob.Spec.Triggers) != 1 {
+		return ""
+	}
+	trigger := getTriggerName(scaledJob.Spec.Triggers[0])
+	if len(validation.IsValidLabelValue(trigger)) > 0 {
+		return ""
+	}
+	return trigger
+}
+
+// splitTriggerHistoryBuckets moves the jobs labeled with a trigger of the ScaledJob's triggerHistoryLimits into
+// a bucket per trigger, the order of the jobs is kept. The limits not set for a trigger default to the passed ones.
+// The remaining jobs are returned with the buckets
+func splitTriggerHistoryBuckets(scaledJob *kedav1alpha1.ScaledJob, completedJobs []batchv1.Job, failedJobs []batchv1.Job, successfulJobsHistoryLimit int32, failedJobsHistoryLimit int32) ([]batchv1.Job, []batchv1.Job, []*triggerHistoryBucket) {
+	if len(scaledJob.Spec.TriggerHistoryLimits) == 0 {
+		return completedJobs, failedJobs, nil
+	}
+
+	buckets := make([]*triggerHistoryBucket, 0, len(scaledJob.Spec.TriggerHistoryLimits))
+	bucketsByTrigger := make(map[string]*triggerHistoryBucket, len(scaledJob.Spec.TriggerHistoryLimits))
+	for _, limit := range scaledJob.Spec.TriggerHistoryLimits {
+		if _, ok := bucketsByTrigger[limit.Trigger]; ok {
+			continue
+		}
+		bucket := &triggerHistoryBucket{
+			trigger:                    limit.Trigger,
+			successfulJobsHistoryLimit: successfulJobsHistoryLimit,
+			failedJobsHistoryLimit:     failedJobsHistoryLimit,
+		}
+		if limit.SuccessfulJobsHistoryLimit != nil {
+			bucket.successfulJobsHistoryLimit = *limit.SuccessfulJobsHistoryLimit
+		}
+		if limit.FailedJobsHistoryLimit != nil {
+			bucket.failedJobsHistoryLimit = *limit.FailedJobsHistoryLimit
+		}
+		buckets = append(buckets, bucket)
+		bucketsByTrigger[limit.Trigger] = bucket
+	}
+
+	remainingCompletedJobs := []batchv1.Job{}
+	for _, job := range completedJobs {
+		if bucket, ok := bucketsByTrigger[job.Labels[TriggerLabel]]; ok {
+			bucket.completedJobs = append(bucket.completedJobs, job)
+		} else {
+			remainingCompletedJobs = append(remainingCompletedJobs, job)
+		}
+	}
+	remainingFailedJobs := []batchv1.Job{}
+	for _, job := range failedJobs {
+		if bucket, ok := bucketsByTrigger[job.Labels[TriggerLabel]]; ok {
+			bucket.failedJobs = append(bucket.failedJobs, job)
+		} else {
+			remainingFailedJobs = append(remainingFailedJobs, job)
+		}
+	}
+	return remainingCompletedJobs, remainingFailedJobs, buckets
+}
+
+// cleanUpTriggerHistoryBuckets deletes the jobs exceeding the history limits of their trigger,
+// the numbers of the retained completed and failed jobs are returned
+func (e *scaleExecutor) cleanUpTriggerHistoryBuckets(logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, buckets []*triggerHistoryBucket, budget *deletionBudget) (int, int, error) {
+	var retainedCompletedCount, retainedFailedCount int
+	for _, bucket := range buckets {
+		bucketLogger := logger.WithValues("trigger", bucket.trigger)
+		_, err := e.deleteJobsWithHistoryLimit(bucketLogger, scaledJob, bucket.completedJobs, fmt.Sprintf("successfulJobsHistoryLimit of trigger %s", bucket.trigger), bucket.successfulJobsHistoryLimit, scaledJob.Spec.SoftDeleteAnnotation, budget)
+		if err != nil {
+			return 0, 0, err
+		}
+		_, err = e.deleteJobsWithHistoryLimit(bucketLogger, scaledJob, bucket.failedJobs, fmt.Sprintf("failedJobsHistoryLimit of trigger %s", bucket.trigger), bucket.failedJobsHistoryLimit, scaledJob.Spec.SoftDeleteAnnotation, budget)
+		if err != nil {
+			return 0, 0, err
+		}
+		retainedCompletedCount += len(getRetainedJobs(bucket.completedJobs, bucket.successfulJobsHistoryLimit))
+		retainedFailedCount += len(getRetainedJobs(bucket.failedJobs, bucket.failedJobsHistoryLimit))
+	}
+	return retainedCompletedCount, retainedFailedCount, nil
+}
@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+	"github.com/kedacore/keda/pkg/mock/mock_client"
+)
+
+func TestCleanUpTriggerHistoryLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// successfulJobHistoryLimit = 1
+	// failedJobHistoryLimit = 1
+	scaledJob := getMockScaledJob(1, 1)
+	scaledJob.Spec.TriggerHistoryLimits = []kedav1alpha1.TriggerHistoryLimit{
+		{Trigger: "orders", SuccessfulJobsHistoryLimit: int32Ptr(2)},
+		{Trigger: "payments", SuccessfulJobsHistoryLimit: int32Ptr(0), FailedJobsHistoryLimit: int32Ptr(2)},
+	}
+
+	jobs := []batchv1.Job{
+		*getJob(t, "orders-success1", "2020-07-29T15:31:00Z", batchv1.JobComplete),
+		*getJob(t, "orders-success2", "2020-07-29T15:32:00Z", batchv1.JobComplete),
+		*getJob(t, "orders-success3", "2020-07-29T15:33:00Z", batchv1.JobComplete),
+		*getJob(t, "orders-fail1", "2020-07-29T15:34:00Z", batchv1.JobFailed),
+		*getJob(t, "orders-fail2", "2020-07-29T15:35:00Z", batchv1.JobFailed),
+		*getJob(t, "payments-success1", "2020-07-29T15:36:00Z", batchv1.JobComplete),
+		*getJob(t, "payments-fail1", "2020-07-29T15:37:00Z", batchv1.JobFailed),
+		*getJob(t, "payments-fail2", "2020-07-29T15:38:00Z", batchv1.JobFailed),
+		*getJob(t, "payments-fail3", "2020-07-29T15:39:00Z", batchv1.JobFailed),
+		// not labeled with a trigger, the limits of the ScaledJob apply
+		*getJob(t, "success1", "2020-07-29T15:40:00Z", batchv1.JobComplete),
+		*getJob(t, "success2", "2020-07-29T15:41:00Z", batchv1.JobComplete),
+	}
+	for i := range jobs[:9] {
+		trigger := "orders"
+		if i >= 5 {
+			trigger = "payments"
+		}
+		jobs[i].Labels = map[string]string{TriggerLabel: trigger}
+	}
+
+	var actualDeletedJobName = make(map[string]string)
+	client := mock_client.NewMockClient(ctrl)
+	client.EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, list runtime.Object, _ ...runtimeclient.ListOption) {
+		list.(*batchv1.JobList).Items = jobs
+	}).
+		Return(nil)
+	expectDelete(t, client, &actualDeletedJobName)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	client.EXPECT().Status().Return(statusWriter).AnyTimes()
+	scaleExecutor := getMockScaleExecutor(client)
+
+	err := scaleExecutor.cleanUp(scaledJob, 0, false)
+
+	assert.Nil(t, err)
+	expected := []string{"orders-success1", "orders-fail1", "payments-success1", "payments-fail1", "success1"}
+	assert.Equal(t, len(expected), len(actualDeletedJobName))
+	for _, name := range expected {
+		_, ok := actualDeletedJobName[name]
+		assert.True(t, ok, "expected %s to be deleted", name)
+	}
+	assert.Equal(t, kedav1alpha1.CleanupDeletedCount{Completed: 3, Failed: 2}, *scaledJob.Status.LastCleanupDeletedCount)
+}
+
+func TestGetJobTrigger(t *testing.T) {
+	scaledJob := getMockScaledJobWithDefault()
+	assert.Equal(t, "", getJobTrigger(scaledJob))
+
+	scaledJob.Spec.Triggers = []kedav1alpha1.ScaleTriggers{{Type: "rabbitmq"}}
+	assert.Equal(t, "rabbitmq", getJobTrigger(scaledJob))
+
+	scaledJob.Spec.Triggers[0].Name = "orders"
+	assert.Equal(t, "orders", getJobTrigger(scaledJob))
+
+	// the Jobs are created for all the triggers together
+	scaledJob.Spec.Triggers = append(scaledJob.Spec.Triggers, kedav1alpha1.ScaleTriggers{Type: "kafka"})
+	assert.Equal(t, "", getJobTrigger(scaledJob))
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"fmt"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+const (
+	// Weight, in percent, of the queue length of a trigger without a weight in the triggerWeights
+	defaultTriggerWeight = 100
+)
+
+// getTriggerName returns the name of the trigger, or its type if it is unnamed
+func getTriggerName(trigger kedav1alpha1.ScaleTriggers) string {
+	if trigger.Name != "" {
+		return trigger.Name
+	}
+	return trigger.Type
+}
+
+// GetTriggerWeights returns the weight of every trigger of the ScaledJob, in the order of its triggers
+func GetTriggerWeights(scaledJob *kedav1alpha1.ScaledJob) []int64 {
+	weights := make([]int64, len(scaledJob.Spec.Triggers))
+	for i, trigger := range scaledJob.Spec.Triggers {
+		weights[i] = defaultTriggerWeight
+		if scaledJob.Spec.ScalingStrategy == nil {
+			continue
+		}
+		for _, weight := range scaledJob.Spec.ScalingStrategy.TriggerWeights {
+			if weight.Trigger == getTriggerName(trigger) {
+				weights[i] = int64(weight.Weight)
+				break
+			}
+		}
+	}
+	return weights
+}
+
+// GetWeightedQueueLength returns the sum of the queue lengths of the triggers weighted by their weights, rounded up,
+// a queue length without a weight is weighted 100 so the plain sum is returned when no weights are set
+func GetWeightedQueueLength(queueLengths []int64, weights []int64) int64 {
+	var weighted int64
+	for i, queueLength := range queueLengths {
+		weight := int64(defaultTriggerWeight)
+		if i < len(weights) {
+			weight = weights[i]
+		}
+		weighted += queueLength * weight
+	}
+	if weighted <= 0 {
+		return 0
+	}
+	return devideWithCeil(weighted, 100)
+}
+
+// ValidateTriggerWeights checks the triggerWeights of the ScaledJob refer to its triggers once and aren't negative
+func ValidateTriggerWeights(scaledJob *kedav1alpha1.ScaledJob) error {
+	if scaledJob.Spec.ScalingStrategy == nil {
+		return nil
+	}
+
+	triggers := map[string]bool{}
+	for _, trigger := range scaledJob.Spec.Triggers {
+		triggers[getTriggerName(trigger)] = true
+	}
+	weighted := map[string]bool{}
+	for _, weight := range scaledJob.Spec.ScalingStrategy.TriggerWeights {
+		if !triggers[weight.Trigger] {
+			return fmt.Errorf("triggerWeights refers to the trigger %q, but the ScaledJob has no trigger named so (or of this type if unnamed)", weight.Trigger)
+		}
+		if weighted[weight.Trigger] {
+			return fmt.Errorf("triggerWeights has more than one weight for the trigger %q", weight.Trigger)
+		}
+		if weight.Weight < 0 {
+			return fmt.Errorf("the weight of the trigger %q must not be negative, got %d", weight.Trigger, weight.Weight)
+		}
+		weighted[weight.Trigger] = true
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kedav1alpha1 "github.com/kedacore/keda/api/v1alpha1"
+)
+
+func getMockScaledJobWithTriggerWeights(weights ...kedav1alpha1.TriggerWeight) *kedav1alpha1.ScaledJob {
+	scaledJob := getMockScaledJobWithDefault()
+	scaledJob.Spec.Triggers = []kedav1alpha1.ScaleTriggers{
+		{Type: "rabbitmq", Name: "priority"},
+		{Type: "rabbitmq", Name: "bulk"},
+		{Type: "azure-queue"},
+	}
+	scaledJob.Spec.ScalingStrategy = &kedav1alpha1.ScalingStrategy{TriggerWeights: weights}
+	return scaledJob
+}
+
+func TestGetWeightedQueueLength(t *testing.T) {
+	scaledJob := getMockScaledJobWithTriggerWeights(
+		kedav1alpha1.TriggerWeight{Trigger: "priority", Weight: 200},
+		kedav1alpha1.TriggerWeight{Trigger: "bulk", Weight: 25},
+	)
+
+	// the unnamed trigger is referred to by its type and defaults to 100
+	weights := GetTriggerWeights(scaledJob)
+	assert.Equal(t, []int64{200, 25, 100}, weights)
+
+	// 2*4 + 10/4 + 3, the fraction is rounded up
+	assert.Equal(t, int64(14), GetWeightedQueueLength([]int64{4, 10, 3}, weights))
+
+	// only the bulk trigger is active
+	assert.Equal(t, int64(3), GetWeightedQueueLength([]int64{0, 10, 0}, weights))
+	assert.Equal(t, int64(0), GetWeightedQueueLength([]int64{0, 0, 0}, weights))
+}
+
+func TestGetWeightedQueueLengthIgnoredTrigger(t *testing.T) {
+	scaledJob := getMockScaledJobWithTriggerWeights(kedav1alpha1.TriggerWeight{Trigger: "azure-queue", Weight: 0})
+
+	assert.Equal(t, int64(14), GetWeightedQueueLength([]int64{4, 10, 300}, GetTriggerWeights(scaledJob)))
+}
+
+func TestGetWeightedQueueLengthWithoutWeights(t *testing.T) {
+	scaledJob := getMockScaledJobWithTriggerWeights()
+	scaledJob.Spec.ScalingStrategy = nil
+
+	// the plain sum of the queue lengths
+	assert.Equal(t, int64(17), GetWeightedQueueLength([]int64{4, 10, 3}, GetTriggerWeights(scaledJob)))
+	assert.Equal(t, int64(17), GetWeightedQueueLength([]int64{4, 10, 3}, nil))
+}
+
+func TestValidateTriggerWeights(t *testing.T) {
+	assert.Nil(t, ValidateTriggerWeights(getMockScaledJobWithTriggerWeights(
+		kedav1alpha1.TriggerWeight{Trigger: "priority", Weight: 200},
+		kedav1alpha1.TriggerWeight{Trigger: "azure-queue", Weight: 0},
+	)))
+
+	// named triggers aren't referred to by their type
+	err := ValidateTriggerWeights(getMockScaledJobWithTriggerWeights(kedav1alpha1.TriggerWeight{Trigger: "rabbitmq", Weight: 50}))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `refers to the trigger "rabbitmq"`)
+
+	err = ValidateTriggerWeights(getMockScaledJobWithTriggerWeights(
+		kedav1alpha1.TriggerWeight{Trigger: "bulk", Weight: 50},
+		kedav1alpha1.TriggerWeight{Trigger: "bulk", Weight: 60},
+	))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "more than one weight")
+
+	err = ValidateTriggerWeights(getMockScaledJobWithTriggerWeights(kedav1alpha1.TriggerWeight{Trigger: "bulk", Weight: -1}))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "must not be negative")
+}
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/scale"
+	"k8s.io/client-go/tools/record"
 	"knative.dev/pkg/apis/duck"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,22 +35,26 @@ type ScaleHandler interface {
 	HandleScalableObject(scalableObject interface{}) error
 	DeleteScalableObject(scalableObject interface{}) error
 	GetScalers(scalableObject interface{}) ([]scalers.Scaler, error)
+	TriggerScaleLoop(scalableObject interface{})
 }
 
 type scaleHandler struct {
 	client            client.Client
 	logger            logr.Logger
 	scaleLoopContexts *sync.Map
+	// scaleLoopTriggers holds the channels requesting an extra check from the running ScaleLoops
+	scaleLoopTriggers *sync.Map
 	scaleExecutor     executor.ScaleExecutor
 }
 
-// NewScaleHandler creates a ScaleHandler object
-func NewScaleHandler(client client.Client, scaleClient *scale.ScalesGetter, reconcilerScheme *runtime.Scheme) ScaleHandler {
+// NewScaleHandler creates a ScaleHandler object, the Jobs of the ScaledJobs are scaled with the jobScaleConfig
+func NewScaleHandler(client client.Client, scaleClient *scale.ScalesGetter, reconcilerScheme *runtime.Scheme, recorder record.EventRecorder, jobScaleConfig executor.Config) ScaleHandler {
 	return &scaleHandler{
 		client:            client,
 		logger:            logf.Log.WithName("scalehandler"),
 		scaleLoopContexts: &sync.Map{},
-		scaleExecutor:     executor.NewScaleExecutor(client, scaleClient, reconcilerScheme),
+		scaleLoopTriggers: &sync.Map{},
+		scaleExecutor:     executor.NewScaleExecutor(client, scaleClient, reconcilerScheme, recorder, jobScaleConfig),
 	}
 }
 
@@ -88,10 +93,14 @@ func (h *scaleHandler) HandleScalableObject(scalableObject interface{}) error {
 		h.scaleLoopContexts.Store(key, cancel)
 	}
 
+	// the triggers requested while a check runs are coalesced into one more check
+	trigger := make(chan struct{}, 1)
+	h.scaleLoopTriggers.Store(key, trigger)
+
 	// a mutex is used to synchronize scale requests per scalableObject
 	scalingMutex := &sync.Mutex{}
 	go h.startPushScalers(ctx, withTriggers, scalableObject, scalingMutex)
-	go h.startScaleLoop(ctx, withTriggers, scalableObject, scalingMutex)
+	go h.startScaleLoop(ctx, withTriggers, scalableObject, scalingMutex, trigger)
 	return nil
 }
 
@@ -111,6 +120,7 @@ func (h *scaleHandler) DeleteScalableObject(scalableObject interface{}) error {
 			cancel()
 		}
 		h.scaleLoopContexts.Delete(key)
+		h.scaleLoopTriggers.Delete(key)
 	} else {
 		h.logger.V(1).Info("ScaleObject was not found in controller cache", "key", key)
 	}
@@ -118,20 +128,58 @@ func (h *scaleHandler) DeleteScalableObject(scalableObject interface{}) error {
 	return nil
 }
 
-// startScaleLoop blocks forever and checks the scaledObject based on its pollingInterval
-func (h *scaleHandler) startScaleLoop(ctx context.Context, withTriggers *kedav1alpha1.WithTriggers, scalableObject interface{}, scalingMutex *sync.Mutex) {
+// TriggerScaleLoop requests a check of the scalers from the running ScaleLoop of the scalableObject ahead of its pollingInterval,
+// the ScaleLoop isn't restarted, nothing happens if no ScaleLoop is running
+func (h *scaleHandler) TriggerScaleLoop(scalableObject interface{}) {
+	withTriggers, err := asDuckWithTriggers(scalableObject)
+	if err != nil {
+		h.logger.Error(err, "error duck typing object into withTrigger")
+		return
+	}
+
+	key := generateKey(withTriggers)
+	result, ok := h.scaleLoopTriggers.Load(key)
+	if !ok {
+		h.logger.V(1).Info("ScaleLoop was not found in controller cache", "key", key)
+		return
+	}
+	select {
+	case result.(chan struct{}) <- struct{}{}:
+	default:
+		// a check is already pending
+	}
+}
+
+// startScaleLoop blocks forever and checks the scaledObject based on its pollingInterval or when triggered
+func (h *scaleHandler) startScaleLoop(ctx context.Context, withTriggers *kedav1alpha1.WithTriggers, scalableObject interface{}, scalingMutex *sync.Mutex, trigger <-chan struct{}) {
 	logger := h.logger.WithValues("type", withTriggers.Kind, "namespace", withTriggers.Namespace, "name", withTriggers.Name)
 
 	// kick off one check to the scalers now
-	h.checkScalers(ctx, scalableObject, scalingMutex)
+	requeueAfter := h.checkScalers(ctx, scalableObject, scalingMutex)
 
 	pollingInterval := getPollingInterval(withTriggers)
 	logger.V(1).Info("Watching with pollingInterval", "PollingInterval", pollingInterval)
 
 	for {
+		// the scaling request can ask for an earlier check than the pollingInterval
+		interval := pollingInterval
+		if requeueAfter > 0 && requeueAfter < pollingInterval {
+			interval = requeueAfter
+		}
+		// the Jobs of the ScaledJob can't be created or deleted, checking earlier would only add to the failures
+		if _, ok := scalableObject.(*kedav1alpha1.ScaledJob); ok {
+			if delay := executor.GetJobScaleBackpressure(withTriggers.Namespace, withTriggers.Name); delay > 0 {
+				logger.V(1).Info("ScaledJob is under backpressure, slowing down", "delay", delay)
+				interval = pollingInterval + delay
+			}
+		}
+
 		select {
-		case <-time.After(pollingInterval):
-			h.checkScalers(ctx, scalableObject, scalingMutex)
+		case <-time.After(interval):
+			requeueAfter = h.checkScalers(ctx, scalableObject, scalingMutex)
+		case <-trigger:
+			logger.V(1).Info("ScaleLoop triggered")
+			requeueAfter = h.checkScalers(ctx, scalableObject, scalingMutex)
 		case <-ctx.Done():
 			logger.V(1).Info("Context canceled")
 			return
@@ -167,7 +215,9 @@ func (h *scaleHandler) startPushScalers(ctx context.Context, withTriggers *kedav
 						h.scaleExecutor.RequestScale(ctx, obj, active)
 					case *kedav1alpha1.ScaledJob:
 						// TODO: revisit when implementing ScaledJob
-						h.scaleExecutor.RequestJobScale(ctx, obj, active, 1, 1)
+						if _, err := h.scaleExecutor.RequestJobScale(ctx, obj, active, 1, 1, 0, time.Now()); err != nil {
+							logger.Error(err, "Error scaling Jobs")
+						}
 					}
 					scalingMutex.Unlock()
 				}
@@ -177,12 +227,13 @@ func (h *scaleHandler) startPushScalers(ctx context.Context, withTriggers *kedav
 }
 
 // checkScalers contains the main logic for the ScaleHandler scaling logic.
-// It'll check each trigger active status then call RequestScale
-func (h *scaleHandler) checkScalers(ctx context.Context, scalableObject interface{}, scalingMutex *sync.Mutex) {
+// It'll check each trigger active status then call RequestScale.
+// The returned duration is the suggested interval to the next check, 0 means the pollingInterval.
+func (h *scaleHandler) checkScalers(ctx context.Context, scalableObject interface{}, scalingMutex *sync.Mutex) time.Duration {
 	scalers, err := h.GetScalers(scalableObject)
 	if err != nil {
 		h.logger.Error(err, "Error getting scalers", "object", scalableObject)
-		return
+		return 0
 	}
 
 	scalingMutex.Lock()
@@ -192,9 +243,16 @@ func (h *scaleHandler) checkScalers(ctx context.Context, scalableObject interfac
 		h.scaleExecutor.RequestScale(ctx, obj, h.checkScaledObjectScalers(ctx, scalers))
 	case *kedav1alpha1.ScaledJob:
 		scaledJob := scalableObject.(*kedav1alpha1.ScaledJob)
-		isActive, scaleTo, maxScale := h.checkScaledJobScalers(ctx, scalers, scaledJob)
-		h.scaleExecutor.RequestJobScale(ctx, obj, isActive, scaleTo, maxScale)
+		// taken before the metrics are computed, the duration until the Jobs are created is recorded by the executor
+		scaledAt := time.Now()
+		isActive, scaleTo, maxScale, backlogAge := h.checkScaledJobScalers(ctx, scalers, scaledJob)
+		requeueAfter, err := h.scaleExecutor.RequestJobScale(ctx, obj, isActive, scaleTo, maxScale, backlogAge, scaledAt)
+		if err != nil {
+			h.logger.Error(err, "Error scaling Jobs", "object", scalableObject)
+		}
+		return requeueAfter
 	}
+	return 0
 }
 
 func (h *scaleHandler) checkScaledObjectScalers(ctx context.Context, scalers []scalers.Scaler) bool {
@@ -215,13 +273,15 @@ func (h *scaleHandler) checkScaledObjectScalers(ctx context.Context, scalers []s
 	return isActive
 }
 
-func (h *scaleHandler) checkScaledJobScalers(ctx context.Context, scalers []scalers.Scaler, scaledJob *kedav1alpha1.ScaledJob) (bool, int64, int64) {
-	var queueLength int64
+func (h *scaleHandler) checkScaledJobScalers(ctx context.Context, scalers []scalers.Scaler, scaledJob *kedav1alpha1.ScaledJob) (bool, int64, int64, time.Duration) {
 	var targetAverageValue int64
 	var maxValue int64
+	var backlogAge time.Duration
 	isActive := false
 
-	for _, scaler := range scalers {
+	// the queue lengths of the triggers are weighted by the triggerWeights of the scalingStrategy
+	queueLengths := make([]int64, len(scalers))
+	for i, scaler := range scalers {
 		scalerLogger := h.logger.WithValues("Scaler", scaler)
 
 		isTriggerActive, err := scaler.IsActive(ctx)
@@ -250,10 +310,17 @@ func (h *scaleHandler) checkScaledJobScalers(ctx context.Context, scalers []scal
 		for _, m := range metrics {
 			if m.MetricName == "queueLength" {
 				metricValue, _ = m.Value.AsInt64()
-				queueLength += metricValue
+				queueLengths[i] += metricValue
+			}
+			// the oldest backlog across all scalers is used
+			if m.MetricName == "backlogAge" {
+				metricValue, _ = m.Value.AsInt64()
+				if age := time.Duration(metricValue) * time.Second; age > backlogAge {
+					backlogAge = age
+				}
 			}
 		}
-		scalerLogger.Info("QueueLength Metric value", "queueLength", queueLength)
+		scalerLogger.Info("QueueLength Metric value", "queueLength", queueLengths[i])
 
 		scaler.Close()
 		if err != nil {
@@ -264,15 +331,11 @@ func (h *scaleHandler) checkScaledJobScalers(ctx context.Context, scalers []scal
 			scalerLogger.Info("Scaler is active")
 		}
 	}
-	var maxReplicaCount int64
-	if scaledJob.Spec.MaxReplicaCount != nil {
-		maxReplicaCount = int64(*scaledJob.Spec.MaxReplicaCount)
-	} else {
-		maxReplicaCount = 100
-	}
+	maxReplicaCount := executor.GetMaxReplicaCount(scaledJob)
+	queueLength := executor.GetWeightedQueueLength(queueLengths, executor.GetTriggerWeights(scaledJob))
 	maxValue = min(maxReplicaCount, devideWithCeil(queueLength, targetAverageValue))
 	h.logger.Info("Scaler maxValue", "maxValue", maxValue)
-	return isActive, queueLength, maxValue
+	return isActive, queueLength, maxValue, backlogAge
 }
 
 func devideWithCeil(x, y int64) int64 {